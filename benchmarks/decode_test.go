@@ -478,6 +478,34 @@ func Benchmark_Decode_LargeStruct_Stream_GoJsonFirstWinMode(b *testing.B) {
 	}
 }
 
+// The Skip benchmarks decode none of the fields of the payload,
+// so they measure how fast the decoder skips over values.
+func Benchmark_Decode_LargeStruct_Unmarshal_GoJsonSkip(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		result := struct {
+			Missing string `json:"missing"`
+		}{}
+		if err := gojson.Unmarshal(LargeFixture, &result); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Benchmark_Decode_LargeStruct_Stream_GoJsonSkip(b *testing.B) {
+	b.ReportAllocs()
+	reader := bytes.NewReader(LargeFixture)
+	for i := 0; i < b.N; i++ {
+		result := struct {
+			Missing string `json:"missing"`
+		}{}
+		reader.Reset(LargeFixture)
+		if err := gojson.NewDecoder(reader).Decode(&result); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func Benchmark_Decode_LargeSlice_EscapedString_GoJson(b *testing.B) {
 	b.ReportAllocs()
 	for i := 0; i < b.N; i++ {
@@ -34,23 +34,48 @@ func unmarshal(data []byte, v interface{}, optFuncs ...DecodeOptionFunc) error {
 	if err := validateType(header.typ, uintptr(header.ptr)); err != nil {
 		return err
 	}
-	dec, err := decoder.CompileToGetDecoder(header.typ)
-	if err != nil {
-		return err
-	}
 	ctx := decoder.TakeRuntimeContext()
 	ctx.Buf = src
 	ctx.Option.Flags = 0
 	for _, optFunc := range optFuncs {
 		optFunc(ctx.Option)
 	}
-	cursor, err := dec.Decode(ctx, 0, 0, header.ptr)
+	dec, err := decoder.CompileToGetDecoderWithOption(header.typ, ctx.Option)
 	if err != nil {
 		decoder.ReleaseRuntimeContext(ctx)
 		return err
 	}
+	if err := ctx.Option.CheckBytes(int64(len(data))); err != nil {
+		decoder.ReleaseRuntimeContext(ctx)
+		return err
+	}
+	ctx.Option.BeginSchema(src)
+	cursor, err := dec.Decode(ctx, 0, 0, header.ptr)
+	if err != nil {
+		ctx.Option.Errors = nil
+		schemaErr := ctx.Option.SchemaFailures(data)
+		decoder.ReleaseRuntimeContext(ctx)
+		if schemaErr != nil {
+			return schemaErr
+		}
+		return errors.Locate(err, data, 0, 1, 0)
+	}
+	schemaErr := ctx.Option.EndSchema(src, 0, cursor)
+	collected := takeCollectedErrors(ctx.Option)
 	decoder.ReleaseRuntimeContext(ctx)
-	return validateEndBuf(src, cursor)
+	if schemaErr != nil {
+		return schemaErr
+	}
+	if err := validateEndBuf(src, cursor); err != nil {
+		return errors.Locate(err, data, 0, 1, 0)
+	}
+	if len(collected) > 0 {
+		for _, err := range collected {
+			errors.Locate(err, data, 0, 1, 0)
+		}
+		return collected
+	}
+	return nil
 }
 
 func unmarshalContext(ctx context.Context, data []byte, v interface{}, optFuncs ...DecodeOptionFunc) error {
@@ -62,10 +87,6 @@ func unmarshalContext(ctx context.Context, data []byte, v interface{}, optFuncs
 	if err := validateType(header.typ, uintptr(header.ptr)); err != nil {
 		return err
 	}
-	dec, err := decoder.CompileToGetDecoder(header.typ)
-	if err != nil {
-		return err
-	}
 	rctx := decoder.TakeRuntimeContext()
 	rctx.Buf = src
 	rctx.Option.Flags = 0
@@ -74,13 +95,42 @@ func unmarshalContext(ctx context.Context, data []byte, v interface{}, optFuncs
 	for _, optFunc := range optFuncs {
 		optFunc(rctx.Option)
 	}
-	cursor, err := dec.Decode(rctx, 0, 0, header.ptr)
+	dec, err := decoder.CompileToGetDecoderWithOption(header.typ, rctx.Option)
 	if err != nil {
 		decoder.ReleaseRuntimeContext(rctx)
 		return err
 	}
+	if err := rctx.Option.CheckBytes(int64(len(data))); err != nil {
+		decoder.ReleaseRuntimeContext(rctx)
+		return err
+	}
+	rctx.Option.BeginSchema(src)
+	cursor, err := dec.Decode(rctx, 0, 0, header.ptr)
+	if err != nil {
+		rctx.Option.Errors = nil
+		schemaErr := rctx.Option.SchemaFailures(data)
+		decoder.ReleaseRuntimeContext(rctx)
+		if schemaErr != nil {
+			return schemaErr
+		}
+		return errors.Locate(err, data, 0, 1, 0)
+	}
+	schemaErr := rctx.Option.EndSchema(src, 0, cursor)
+	collected := takeCollectedErrors(rctx.Option)
 	decoder.ReleaseRuntimeContext(rctx)
-	return validateEndBuf(src, cursor)
+	if schemaErr != nil {
+		return schemaErr
+	}
+	if err := validateEndBuf(src, cursor); err != nil {
+		return errors.Locate(err, data, 0, 1, 0)
+	}
+	if len(collected) > 0 {
+		for _, err := range collected {
+			errors.Locate(err, data, 0, 1, 0)
+		}
+		return collected
+	}
+	return nil
 }
 
 func unmarshalNoEscape(data []byte, v interface{}, optFuncs ...DecodeOptionFunc) error {
@@ -92,10 +142,6 @@ func unmarshalNoEscape(data []byte, v interface{}, optFuncs ...DecodeOptionFunc)
 	if err := validateType(header.typ, uintptr(header.ptr)); err != nil {
 		return err
 	}
-	dec, err := decoder.CompileToGetDecoder(header.typ)
-	if err != nil {
-		return err
-	}
 
 	ctx := decoder.TakeRuntimeContext()
 	ctx.Buf = src
@@ -103,13 +149,50 @@ func unmarshalNoEscape(data []byte, v interface{}, optFuncs ...DecodeOptionFunc)
 	for _, optFunc := range optFuncs {
 		optFunc(ctx.Option)
 	}
-	cursor, err := dec.Decode(ctx, 0, 0, noescape(header.ptr))
+	dec, err := decoder.CompileToGetDecoderWithOption(header.typ, ctx.Option)
 	if err != nil {
 		decoder.ReleaseRuntimeContext(ctx)
 		return err
 	}
+	if err := ctx.Option.CheckBytes(int64(len(data))); err != nil {
+		decoder.ReleaseRuntimeContext(ctx)
+		return err
+	}
+	ctx.Option.BeginSchema(src)
+	cursor, err := dec.Decode(ctx, 0, 0, noescape(header.ptr))
+	if err != nil {
+		ctx.Option.Errors = nil
+		schemaErr := ctx.Option.SchemaFailures(data)
+		decoder.ReleaseRuntimeContext(ctx)
+		if schemaErr != nil {
+			return schemaErr
+		}
+		return errors.Locate(err, data, 0, 1, 0)
+	}
+	schemaErr := ctx.Option.EndSchema(src, 0, cursor)
+	collected := takeCollectedErrors(ctx.Option)
 	decoder.ReleaseRuntimeContext(ctx)
-	return validateEndBuf(src, cursor)
+	if schemaErr != nil {
+		return schemaErr
+	}
+	if err := validateEndBuf(src, cursor); err != nil {
+		return errors.Locate(err, data, 0, 1, 0)
+	}
+	if len(collected) > 0 {
+		for _, err := range collected {
+			errors.Locate(err, data, 0, 1, 0)
+		}
+		return collected
+	}
+	return nil
+}
+
+// takeCollectedErrors returns the type errors skipped with DecodeCollectErrors
+// and clears them from opt.
+func takeCollectedErrors(opt *decoder.Option) DecodeErrors {
+	errs := DecodeErrors(opt.Errors)
+	opt.Errors = nil
+	return errs
 }
 
 func validateEndBuf(src []byte, cursor int64) error {
@@ -188,20 +271,75 @@ func (d *Decoder) DecodeWithOption(v interface{}, optFuncs ...DecodeOptionFunc)
 		return err
 	}
 
-	dec, err := decoder.CompileToGetDecoder(typ)
+	s := d.s
+	s.Option.Errors = nil
+	for _, optFunc := range optFuncs {
+		optFunc(s.Option)
+	}
+	dec, err := decoder.CompileToGetDecoderWithOption(typ, s.Option)
 	if err != nil {
 		return err
 	}
-	if err := d.s.PrepareForDecode(); err != nil {
+	if err := s.PrepareForDecode(); err != nil {
+		if limitErr := s.LimitError(); limitErr != nil {
+			return limitErr
+		}
 		return err
 	}
-	s := d.s
-	for _, optFunc := range optFuncs {
-		optFunc(s.Option)
-	}
+	s.BeginSchema()
 	if err := dec.DecodeStream(s, 0, header.ptr); err != nil {
+		schemaErr := s.SchemaFailures()
+		if limitErr := s.LimitError(); limitErr != nil {
+			return limitErr
+		}
+		if schemaErr != nil {
+			return schemaErr
+		}
+		return s.LocateError(err)
+	}
+	schemaErr := s.EndSchema()
+	collected := takeCollectedErrors(s.Option)
+	s.Reset()
+	if schemaErr != nil {
+		return schemaErr
+	}
+	if len(collected) > 0 {
+		for _, err := range collected {
+			s.LocateError(err)
+		}
+		return collected
+	}
+	return nil
+}
+
+// DecodeEach reads the next JSON-encoded value, which must be an array,
+// and calls f for each of its elements without buffering the whole array.
+// i is the index of the element. f may decode the element with a single call
+// to dec.Decode or one of its variants; elements that f does not decode are skipped.
+// If f returns an error, DecodeEach stops and returns it, leaving the rest of the array unread.
+func (d *Decoder) DecodeEach(f func(i int, dec *Decoder) error) error {
+	s := d.s
+	if err := s.ArrayBegin(); err != nil {
 		return err
 	}
+	for i := 0; ; i++ {
+		more, err := s.ArrayElement(i == 0)
+		if err != nil {
+			return s.LocateError(err)
+		}
+		if !more {
+			break
+		}
+		start := s.TotalOffset()
+		if err := f(i, d); err != nil {
+			return err
+		}
+		if s.TotalOffset() == start {
+			if err := s.SkipValue(); err != nil {
+				return s.LocateError(err)
+			}
+		}
+	}
 	s.Reset()
 	return nil
 }
@@ -0,0 +1,15 @@
+package json
+
+// DecodeArray reads the next JSON-encoded value from dec, which must be an array,
+// and calls f with each of its elements decoded into a new value of type T.
+// Only one element is held in memory at a time.
+// If f returns an error, DecodeArray stops and returns it.
+func DecodeArray[T any](dec *Decoder, f func(T) error) error {
+	return dec.DecodeEach(func(_ int, dec *Decoder) error {
+		var v T
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		return f(v)
+	})
+}
@@ -0,0 +1,53 @@
+package json_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+func TestDecodeArray(t *testing.T) {
+	type T struct {
+		A int `json:"a"`
+	}
+	t.Run("elements", func(t *testing.T) {
+		var got []T
+		err := json.DecodeArray(json.NewDecoder(strings.NewReader(`[{"a":1}, {"a":2} ,{"a":3}]`)), func(v T) error {
+			got = append(got, v)
+			return nil
+		})
+		assertErr(t, err)
+		if !reflect.DeepEqual([]T{{A: 1}, {A: 2}, {A: 3}}, got) {
+			t.Fatalf("unexpected elements: %v", got)
+		}
+	})
+	t.Run("stop", func(t *testing.T) {
+		errStop := errors.New("stop")
+		var got []int
+		err := json.DecodeArray(json.NewDecoder(strings.NewReader(`[1,2,3]`)), func(v int) error {
+			got = append(got, v)
+			if v == 2 {
+				return errStop
+			}
+			return nil
+		})
+		if err != errStop {
+			t.Fatalf("expected stop error, got %v", err)
+		}
+		if !reflect.DeepEqual([]int{1, 2}, got) {
+			t.Fatalf("unexpected elements: %v", got)
+		}
+	})
+	t.Run("type error", func(t *testing.T) {
+		err := json.DecodeArray(json.NewDecoder(strings.NewReader(`[1,"x"]`)), func(v int) error {
+			return nil
+		})
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			t.Fatalf("expected UnmarshalTypeError, got %v", err)
+		}
+	})
+}
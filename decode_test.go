@@ -15,10 +15,12 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 	"unsafe"
 
 	"github.com/goccy/go-json"
+	"github.com/goccy/go-json/schema"
 )
 
 func Test_Decoder(t *testing.T) {
@@ -758,28 +760,28 @@ type DoublePtr struct {
 
 var unmarshalTests = []unmarshalTest{
 	// basic types
-	{in: `true`, ptr: new(bool), out: true},                                                                                                                       // 0
-	{in: `1`, ptr: new(int), out: 1},                                                                                                                              // 1
-	{in: `1.2`, ptr: new(float64), out: 1.2},                                                                                                                      // 2
-	{in: `-5`, ptr: new(int16), out: int16(-5)},                                                                                                                   // 3
-	{in: `2`, ptr: new(json.Number), out: json.Number("2"), useNumber: true},                                                                                      // 4
-	{in: `2`, ptr: new(json.Number), out: json.Number("2")},                                                                                                       // 5
-	{in: `2`, ptr: new(interface{}), out: float64(2.0)},                                                                                                           // 6
-	{in: `2`, ptr: new(interface{}), out: json.Number("2"), useNumber: true},                                                                                      // 7
-	{in: `"a\u1234"`, ptr: new(string), out: "a\u1234"},                                                                                                           // 8
-	{in: `"http:\/\/"`, ptr: new(string), out: "http://"},                                                                                                         // 9
-	{in: `"g-clef: \uD834\uDD1E"`, ptr: new(string), out: "g-clef: \U0001D11E"},                                                                                   // 10
-	{in: `"invalid: \uD834x\uDD1E"`, ptr: new(string), out: "invalid: \uFFFDx\uFFFD"},                                                                             // 11
-	{in: "null", ptr: new(interface{}), out: nil},                                                                                                                 // 12
-	{in: `{"X": [1,2,3], "Y": 4}`, ptr: new(T), out: T{Y: 4}, err: &json.UnmarshalTypeError{"array", reflect.TypeOf(""), 7, "T", "X"}},                            // 13
-	{in: `{"X": 23}`, ptr: new(T), out: T{}, err: &json.UnmarshalTypeError{"number", reflect.TypeOf(""), 8, "T", "X"}}, {in: `{"x": 1}`, ptr: new(tx), out: tx{}}, // 14
+	{in: `true`, ptr: new(bool), out: true},                                           // 0
+	{in: `1`, ptr: new(int), out: 1},                                                  // 1
+	{in: `1.2`, ptr: new(float64), out: 1.2},                                          // 2
+	{in: `-5`, ptr: new(int16), out: int16(-5)},                                       // 3
+	{in: `2`, ptr: new(json.Number), out: json.Number("2"), useNumber: true},          // 4
+	{in: `2`, ptr: new(json.Number), out: json.Number("2")},                           // 5
+	{in: `2`, ptr: new(interface{}), out: float64(2.0)},                               // 6
+	{in: `2`, ptr: new(interface{}), out: json.Number("2"), useNumber: true},          // 7
+	{in: `"a\u1234"`, ptr: new(string), out: "a\u1234"},                               // 8
+	{in: `"http:\/\/"`, ptr: new(string), out: "http://"},                             // 9
+	{in: `"g-clef: \uD834\uDD1E"`, ptr: new(string), out: "g-clef: \U0001D11E"},       // 10
+	{in: `"invalid: \uD834x\uDD1E"`, ptr: new(string), out: "invalid: \uFFFDx\uFFFD"}, // 11
+	{in: "null", ptr: new(interface{}), out: nil},                                     // 12
+	{in: `{"X": [1,2,3], "Y": 4}`, ptr: new(T), out: T{Y: 4}, err: &json.UnmarshalTypeError{Value: "array", Type: reflect.TypeOf(""), Offset: 7, Struct: "T", Field: "X"}},                            // 13
+	{in: `{"X": 23}`, ptr: new(T), out: T{}, err: &json.UnmarshalTypeError{Value: "number", Type: reflect.TypeOf(""), Offset: 8, Struct: "T", Field: "X"}}, {in: `{"x": 1}`, ptr: new(tx), out: tx{}}, // 14
 	{in: `{"x": 1}`, ptr: new(tx), out: tx{}}, // 15, 16
-	{in: `{"x": 1}`, ptr: new(tx), err: fmt.Errorf("json: unknown field \"x\""), disallowUnknownFields: true},                           // 17
-	{in: `{"S": 23}`, ptr: new(W), out: W{}, err: &json.UnmarshalTypeError{"number", reflect.TypeOf(SS("")), 0, "W", "S"}},              // 18
-	{in: `{"F1":1,"F2":2,"F3":3}`, ptr: new(V), out: V{F1: float64(1), F2: int32(2), F3: json.Number("3")}},                             // 19
-	{in: `{"F1":1,"F2":2,"F3":3}`, ptr: new(V), out: V{F1: json.Number("1"), F2: int32(2), F3: json.Number("3")}, useNumber: true},      // 20
-	{in: `{"k1":1,"k2":"s","k3":[1,2.0,3e-3],"k4":{"kk1":"s","kk2":2}}`, ptr: new(interface{}), out: ifaceNumAsFloat64},                 // 21
-	{in: `{"k1":1,"k2":"s","k3":[1,2.0,3e-3],"k4":{"kk1":"s","kk2":2}}`, ptr: new(interface{}), out: ifaceNumAsNumber, useNumber: true}, // 22
+	{in: `{"x": 1}`, ptr: new(tx), err: fmt.Errorf("json: unknown field \"x\""), disallowUnknownFields: true},                                                  // 17
+	{in: `{"S": 23}`, ptr: new(W), out: W{}, err: &json.UnmarshalTypeError{Value: "number", Type: reflect.TypeOf(SS("")), Offset: 0, Struct: "W", Field: "S"}}, // 18
+	{in: `{"F1":1,"F2":2,"F3":3}`, ptr: new(V), out: V{F1: float64(1), F2: int32(2), F3: json.Number("3")}},                                                    // 19
+	{in: `{"F1":1,"F2":2,"F3":3}`, ptr: new(V), out: V{F1: json.Number("1"), F2: int32(2), F3: json.Number("3")}, useNumber: true},                             // 20
+	{in: `{"k1":1,"k2":"s","k3":[1,2.0,3e-3],"k4":{"kk1":"s","kk2":2}}`, ptr: new(interface{}), out: ifaceNumAsFloat64},                                        // 21
+	{in: `{"k1":1,"k2":"s","k3":[1,2.0,3e-3],"k4":{"kk1":"s","kk2":2}}`, ptr: new(interface{}), out: ifaceNumAsNumber, useNumber: true},                        // 22
 
 	// raw values with whitespace
 	{in: "\n true ", ptr: new(bool), out: true},                  // 23
@@ -2436,6 +2438,32 @@ func TestSkipArrayObjects(t *testing.T) {
 	}
 }
 
+// Test that skipping and decoding values finds the strings and brackets
+// wherever they are in the words that the decoder scans at once.
+func TestSkipValuesAtAnyOffset(t *testing.T) {
+	skipped := `[ "x{[\"]}\\", {"k\"}": "é]\\\"}", "n": [-1.5e3, true, null, "long string without special characters"]}, {} ]`
+	str := `"a \"quoted\" \\ string with é and \u00e9 and ]}"`
+	for pad := 0; pad < 16; pad++ {
+		space := strings.Repeat(" ", pad)
+		data := `{"skip":` + space + skipped + `,` + space + `"a":` + space + str + `, "b": 1}`
+		type T struct {
+			A string `json:"a"`
+			B int    `json:"b"`
+		}
+		var v T
+		if err := json.Unmarshal([]byte(data), &v); err != nil {
+			t.Fatalf("pad %d: %v", pad, err)
+		}
+		want := T{A: `a "quoted" \ string with é and é and ]}`, B: 1}
+		assertEq(t, "unmarshal", want, v)
+		v = T{}
+		if err := json.NewDecoder(iotest.OneByteReader(strings.NewReader(data))).Decode(&v); err != nil {
+			t.Fatalf("pad %d: %v", pad, err)
+		}
+		assertEq(t, "stream", want, v)
+	}
+}
+
 // Test semantics of pre-filled data, such as struct fields, map elements,
 // slices, and arrays.
 // Issues 4900 and 8837, among others.
@@ -2693,23 +2721,53 @@ func TestUnmarshalEmbeddedUnexported(t *testing.T) {
 
 func TestUnmarshalErrorAfterMultipleJSON(t *testing.T) {
 	tests := []struct {
-		in  string
-		err error
+		in     string
+		err    *json.SyntaxError
+		line   int64
+		column int64
+		path   string
 	}{{
-		in:  `1 false null :`,
-		err: json.NewSyntaxError("invalid character '\x00' looking for beginning of value", 14),
+		in:     `1 false null :`,
+		err:    json.NewSyntaxError("invalid character '\x00' looking for beginning of value", 14),
+		line:   1,
+		column: 15,
+		path:   "$",
 	}, {
-		in:  `1 [] [,]`,
-		err: json.NewSyntaxError("invalid character ',' looking for beginning of value", 6),
+		in:     `1 [] [,]`,
+		err:    json.NewSyntaxError("invalid character ',' looking for beginning of value", 6),
+		line:   1,
+		column: 7,
+		path:   "$[0]",
 	}, {
-		in:  `1 [] [true:]`,
-		err: json.NewSyntaxError("json: slice unexpected end of JSON input", 10),
+		in:     `1 [] [true:]`,
+		err:    json.NewSyntaxError("json: slice unexpected end of JSON input", 10),
+		line:   1,
+		column: 11,
+		path:   "$",
 	}, {
-		in:  `1  {}    {"x"=}`,
-		err: json.NewSyntaxError("expected colon after object key", 13),
+		in:     `1  {}    {"x"=}`,
+		err:    json.NewSyntaxError("expected colon after object key", 13),
+		line:   1,
+		column: 14,
+		path:   "$",
 	}, {
-		in:  `falsetruenul#`,
-		err: json.NewSyntaxError("json: invalid character # as null", 12),
+		in:     `falsetruenul#`,
+		err:    json.NewSyntaxError("json: invalid character # as null", 12),
+		line:   1,
+		column: 13,
+		path:   "$",
+	}, {
+		in:     "1\n[]\n  [,]",
+		err:    json.NewSyntaxError("invalid character ',' looking for beginning of value", 8),
+		line:   3,
+		column: 4,
+		path:   "$[0]",
+	}, {
+		in:     "{\"a\": 1}\n{\"a\": [1,\n  2}",
+		err:    json.NewSyntaxError("json: slice unexpected end of JSON input", 22),
+		line:   3,
+		column: 4,
+		path:   "$.a",
 	}}
 	for i, tt := range tests {
 		dec := json.NewDecoder(strings.NewReader(tt.in))
@@ -2720,8 +2778,14 @@ func TestUnmarshalErrorAfterMultipleJSON(t *testing.T) {
 				break
 			}
 		}
-		if !reflect.DeepEqual(err, tt.err) {
+		serr, ok := err.(*json.SyntaxError)
+		if !ok || serr.Error() != tt.err.Error() || serr.Offset != tt.err.Offset {
 			t.Errorf("#%d: got %#v, want %#v", i, err, tt.err)
+			continue
+		}
+		if serr.Line != tt.line || serr.Column != tt.column || serr.Path != tt.path {
+			t.Errorf("#%d: got line %d, column %d, path %q, want line %d, column %d, path %q",
+				i, serr.Line, serr.Column, serr.Path, tt.line, tt.column, tt.path)
 		}
 	}
 }
@@ -3968,3 +4032,1121 @@ func TestIssue335(t *testing.T) {
 		t.Errorf("unexpected success")
 	}
 }
+
+func TestDecodeLimits(t *testing.T) {
+	t.Run("MaxDepth", func(t *testing.T) {
+		limits := json.DecodeLimits{MaxDepth: 2}
+		var v interface{}
+		assertErr(t, json.UnmarshalWithOption([]byte(`[[1]]`), &v, json.DecodeWithLimits(limits)))
+		err := json.UnmarshalWithOption([]byte(`[[[1]]]`), &v, json.DecodeWithLimits(limits))
+		var depthErr *json.DepthLimitError
+		if !errors.As(err, &depthErr) {
+			t.Fatalf("expected DepthLimitError but got %v", err)
+		}
+		err = json.NewDecoder(strings.NewReader(`{"a":{"b":{"c":1}}}`)).DecodeWithOption(&v, json.DecodeWithLimits(limits))
+		if !errors.As(err, &depthErr) {
+			t.Fatalf("expected DepthLimitError but got %v", err)
+		}
+	})
+	t.Run("MaxStringLen", func(t *testing.T) {
+		limits := json.DecodeLimits{MaxStringLen: 3}
+		var v struct {
+			A string
+			B interface{}
+		}
+		assertErr(t, json.UnmarshalWithOption([]byte(`{"A":"abc"}`), &v, json.DecodeWithLimits(limits)))
+		var strErr *json.StringLimitError
+		err := json.UnmarshalWithOption([]byte(`{"A":"abcd"}`), &v, json.DecodeWithLimits(limits))
+		if !errors.As(err, &strErr) {
+			t.Fatalf("expected StringLimitError but got %v", err)
+		}
+		err = json.NewDecoder(strings.NewReader(`{"B":"abcd"}`)).DecodeWithOption(&v, json.DecodeWithLimits(limits))
+		if !errors.As(err, &strErr) {
+			t.Fatalf("expected StringLimitError but got %v", err)
+		}
+	})
+	t.Run("MaxArrayLen", func(t *testing.T) {
+		limits := json.DecodeLimits{MaxArrayLen: 2}
+		var v []int
+		assertErr(t, json.UnmarshalWithOption([]byte(`[1,2]`), &v, json.DecodeWithLimits(limits)))
+		var arrErr *json.ArrayLimitError
+		err := json.UnmarshalWithOption([]byte(`[1,2,3]`), &v, json.DecodeWithLimits(limits))
+		if !errors.As(err, &arrErr) {
+			t.Fatalf("expected ArrayLimitError but got %v", err)
+		}
+		err = json.NewDecoder(strings.NewReader(`[1,2,3]`)).DecodeWithOption(&v, json.DecodeWithLimits(limits))
+		if !errors.As(err, &arrErr) {
+			t.Fatalf("expected ArrayLimitError but got %v", err)
+		}
+	})
+	t.Run("MaxObjectKeys", func(t *testing.T) {
+		limits := json.DecodeLimits{MaxObjectKeys: 2}
+		var m map[string]int
+		assertErr(t, json.UnmarshalWithOption([]byte(`{"a":1,"b":2}`), &m, json.DecodeWithLimits(limits)))
+		var keysErr *json.ObjectKeysLimitError
+		err := json.UnmarshalWithOption([]byte(`{"a":1,"b":2,"c":3}`), &m, json.DecodeWithLimits(limits))
+		if !errors.As(err, &keysErr) {
+			t.Fatalf("expected ObjectKeysLimitError but got %v", err)
+		}
+		var s struct{ A int }
+		err = json.NewDecoder(strings.NewReader(`{"x":1,"y":2,"A":3}`)).DecodeWithOption(&s, json.DecodeWithLimits(limits))
+		if !errors.As(err, &keysErr) {
+			t.Fatalf("expected ObjectKeysLimitError but got %v", err)
+		}
+	})
+	t.Run("MaxBytes", func(t *testing.T) {
+		limits := json.DecodeLimits{MaxBytes: 8}
+		var v interface{}
+		assertErr(t, json.UnmarshalWithOption([]byte(`[1,2,3]`), &v, json.DecodeWithLimits(limits)))
+		var bytesErr *json.BytesLimitError
+		err := json.UnmarshalWithOption([]byte(`[1,2,3,4,5]`), &v, json.DecodeWithLimits(limits))
+		if !errors.As(err, &bytesErr) {
+			t.Fatalf("expected BytesLimitError but got %v", err)
+		}
+		err = json.NewDecoder(strings.NewReader(`[1,2,3,4,5]`)).DecodeWithOption(&v, json.DecodeWithLimits(limits))
+		if !errors.As(err, &bytesErr) {
+			t.Fatalf("expected BytesLimitError but got %v", err)
+		}
+	})
+}
+
+func TestDecodeRejectDuplicateKeys(t *testing.T) {
+	type T struct {
+		A int    `json:"a"`
+		B string `json:"b"`
+	}
+	unmarshal := func(src string, v interface{}) error {
+		return json.UnmarshalWithOption([]byte(src), v, json.DecodeRejectDuplicateKeys())
+	}
+	stream := func(src string, v interface{}) error {
+		return json.NewDecoder(strings.NewReader(src)).DecodeWithOption(v, json.DecodeRejectDuplicateKeys())
+	}
+	for _, test := range []struct {
+		name   string
+		decode func(string, interface{}) error
+	}{
+		{name: "Unmarshal", decode: unmarshal},
+		{name: "Stream", decode: stream},
+	} {
+		decode := test.decode
+		t.Run(test.name, func(t *testing.T) {
+			expectDuplicate := func(t *testing.T, err error, key string, offset int64) {
+				t.Helper()
+				var dupErr *json.DuplicateKeyError
+				if !errors.As(err, &dupErr) {
+					t.Fatalf("expected DuplicateKeyError but got %v", err)
+				}
+				assertEq(t, "key", key, dupErr.Key)
+				assertEq(t, "offset", offset, dupErr.Offset)
+			}
+			t.Run("struct", func(t *testing.T) {
+				var v T
+				assertErr(t, decode(`{"a":1,"b":"x","c":1}`, &v))
+				expectDuplicate(t, decode(`{"a":1,"b":"x","a":2}`, &v), "a", 15)
+				expectDuplicate(t, decode(`{"c":1,"a":1,"c":2}`, &v), "c", 13)
+				var dupErr *json.DuplicateKeyError
+				if !errors.As(decode(`{"a":1,"A":2}`, &v), &dupErr) {
+					t.Fatal("expected keys selecting the same field to be rejected")
+				}
+			})
+			t.Run("map", func(t *testing.T) {
+				var v map[string]int
+				assertErr(t, decode(`{"a":1,"b":2}`, &v))
+				expectDuplicate(t, decode(`{"a":1, "b":2, "a":3}`, &v), "a", 15)
+				expectDuplicate(t, decode(`{"a":1,"a":2}`, &v), "a", 7)
+				var m map[int]int
+				expectDuplicate(t, decode(`{"1":1,"1":2}`, &m), "1", 7)
+			})
+			t.Run("interface", func(t *testing.T) {
+				var v interface{}
+				assertErr(t, decode(`{"a":{"a":1},"b":[{"a":1},{"a":2}]}`, &v))
+				expectDuplicate(t, decode(`[{"x":{"y":1,"y":2}}]`, &v), "y", 13)
+			})
+			t.Run("escaped key", func(t *testing.T) {
+				var v T
+				expectDuplicate(t, decode(`{"x":1,"\u0078":2}`, &v), "x", 7)
+				expectDuplicate(t, decode(`{"a":1,"\u0061":2}`, &v), "a", 7)
+				var m map[string]int
+				expectDuplicate(t, decode(`{"x":1,"\u0078":2}`, &m), "x", 7)
+			})
+			t.Run("skipped value", func(t *testing.T) {
+				var v T
+				assertErr(t, decode(`{"c":{"a":1,"b":[{"a":1},{"a":2}]},"d":[1,{}]}`, &v))
+				expectDuplicate(t, decode(`{"c":{"x":1,"x":2}}`, &v), "x", 12)
+				expectDuplicate(t, decode(`{"c":[{"x":1,"\u0078":2}]}`, &v), "x", 13)
+				var arr [1]T
+				expectDuplicate(t, decode(`[{},{"x":1,"x":2}]`, &arr), "x", 11)
+			})
+		})
+	}
+	t.Run("validate", func(t *testing.T) {
+		for _, test := range []struct {
+			src    string
+			key    string
+			offset int64
+		}{
+			{src: `{"a":{"b":1,"b":2}}`, key: "b", offset: 12},
+			{src: `[{"x":1,"\u0078":2}]`, key: "x", offset: 8},
+		} {
+			var dupErr *json.DuplicateKeyError
+			if !errors.As(json.Validate([]byte(test.src), json.DecodeRejectDuplicateKeys()), &dupErr) {
+				t.Fatalf("expected Validate to report the duplicated key of %s", test.src)
+			}
+			assertEq(t, "key", test.key, dupErr.Key)
+			assertEq(t, "offset", test.offset, dupErr.Offset)
+			dupErr = nil
+			if !errors.As(json.ValidReader(strings.NewReader(test.src), json.DecodeRejectDuplicateKeys()), &dupErr) {
+				t.Fatalf("expected ValidReader to report the duplicated key of %s", test.src)
+			}
+			assertEq(t, "key", test.key, dupErr.Key)
+			assertEq(t, "offset", test.offset, dupErr.Offset)
+			assertErr(t, json.Validate([]byte(test.src)))
+			assertErr(t, json.ValidReader(strings.NewReader(test.src)))
+		}
+	})
+	t.Run("without option", func(t *testing.T) {
+		var v map[string]int
+		assertErr(t, json.Unmarshal([]byte(`{"a":1,"a":2}`), &v))
+		assertEq(t, "a", 2, v["a"])
+	})
+}
+
+func TestDecodeErrorLocation(t *testing.T) {
+	type Item struct {
+		Price int `json:"price"`
+	}
+	type Order struct {
+		Items []Item          `json:"items"`
+		Tags  map[string]bool `json:"tags"`
+	}
+	src := "{\n  \"items\": [\n    {\"price\": 1},\n    {\"price\": \"2\"}\n  ]\n}"
+	for _, test := range []struct {
+		name   string
+		decode func(string, interface{}) error
+	}{
+		{
+			name: "Unmarshal",
+			decode: func(src string, v interface{}) error {
+				return json.Unmarshal([]byte(src), v)
+			},
+		},
+		{
+			name: "Stream",
+			decode: func(src string, v interface{}) error {
+				return json.NewDecoder(strings.NewReader(src)).Decode(v)
+			},
+		},
+	} {
+		decode := test.decode
+		t.Run(test.name, func(t *testing.T) {
+			t.Run("type error", func(t *testing.T) {
+				var v Order
+				err := decode(src, &v)
+				var typeErr *json.UnmarshalTypeError
+				if !errors.As(err, &typeErr) {
+					t.Fatalf("expected UnmarshalTypeError but got %v", err)
+				}
+				assertEq(t, "path", "$.items[1].price", typeErr.Path)
+				assertEq(t, "line", int64(4), typeErr.Line)
+				assertEq(t, "column", int64(15), typeErr.Column)
+			})
+			t.Run("syntax error", func(t *testing.T) {
+				var v interface{}
+				err := decode("[\n  {\"a b\": [1, 2,]}\n]", &v)
+				var syntaxErr *json.SyntaxError
+				if !errors.As(err, &syntaxErr) {
+					t.Fatalf("expected SyntaxError but got %v", err)
+				}
+				assertEq(t, "path", `$[0]["a b"][2]`, syntaxErr.Path)
+				assertEq(t, "line", int64(2), syntaxErr.Line)
+				assertEq(t, "column", int64(17), syntaxErr.Column)
+			})
+			t.Run("map key", func(t *testing.T) {
+				var v Order
+				err := decode(`{"tags": {"x": true, "y": tru}}`, &v)
+				var syntaxErr *json.SyntaxError
+				if !errors.As(err, &syntaxErr) {
+					t.Fatalf("expected SyntaxError but got %v", err)
+				}
+				assertEq(t, "path", "$.tags.y", syntaxErr.Path)
+			})
+		})
+	}
+	t.Run("snippet", func(t *testing.T) {
+		var v Order
+		err := json.Unmarshal([]byte(src), &v)
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			t.Fatalf("expected UnmarshalTypeError but got %v", err)
+		}
+		assertEq(t, "snippet", "    {\"price\": \"2\"}\n              ^", typeErr.Snippet())
+	})
+	t.Run("snippet of long line", func(t *testing.T) {
+		var v []int
+		src := "[" + strings.Repeat("1,", 100) + "x" + strings.Repeat(",1", 100) + "]"
+		err := json.Unmarshal([]byte(src), &v)
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			t.Fatalf("expected UnmarshalTypeError but got %v", err)
+		}
+		assertEq(t, "path", "$[100]", typeErr.Path)
+		assertEq(t, "column", int64(202), typeErr.Column)
+		assertEq(t, "snippet", strings.Repeat("1,", 20)+"x"+strings.Repeat(",1", 20)+"\n"+strings.Repeat(" ", 40)+"^", typeErr.Snippet())
+	})
+}
+
+func TestDecodeCollectErrors(t *testing.T) {
+	type Item struct {
+		Name  string `json:"name"`
+		Price int    `json:"price"`
+	}
+	type Form struct {
+		Name   string         `json:"name"`
+		Age    int            `json:"age"`
+		Items  []Item         `json:"items"`
+		Scores map[string]int `json:"scores"`
+		Flags  [2]string      `json:"flags"`
+		Ratio  float64        `json:"ratio"`
+		Active bool           `json:"active"`
+		Owner  Item           `json:"owner"`
+		Tags   []string       `json:"tags"`
+		Note   string         `json:"note"`
+	}
+	src := `{
+  "name": 1,
+  "age": 20,
+  "items": [{"name": "a", "price": "10"}, {"name": "b", "price": 20}],
+  "scores": {"x": 1, "y": "2"},
+  "flags": ["a", 2],
+  "ratio": "0.5",
+  "active": 1,
+  "owner": 3,
+  "tags": "a",
+  "note": "n"
+}`
+	expected := []struct {
+		path string
+		typ  reflect.Type
+		line int64
+	}{
+		{path: "$.name", typ: reflect.TypeOf(""), line: 2},
+		{path: "$.items[0].price", typ: reflect.TypeOf(0), line: 4},
+		{path: "$.scores.y", typ: reflect.TypeOf(0), line: 5},
+		{path: "$.flags[1]", typ: reflect.TypeOf(""), line: 6},
+		{path: "$.ratio", typ: reflect.TypeOf(0.0), line: 7},
+		{path: "$.active", typ: reflect.TypeOf(false), line: 8},
+		{path: "$.owner", typ: reflect.TypeOf(Item{}), line: 9},
+		{path: "$.tags", typ: reflect.TypeOf([]string{}), line: 10},
+	}
+	for _, test := range []struct {
+		name   string
+		decode func(string, interface{}) error
+	}{
+		{
+			name: "Unmarshal",
+			decode: func(src string, v interface{}) error {
+				return json.UnmarshalWithOption([]byte(src), v, json.DecodeCollectErrors())
+			},
+		},
+		{
+			name: "Stream",
+			decode: func(src string, v interface{}) error {
+				return json.NewDecoder(strings.NewReader(src)).DecodeWithOption(v, json.DecodeCollectErrors())
+			},
+		},
+	} {
+		decode := test.decode
+		t.Run(test.name, func(t *testing.T) {
+			var v Form
+			err := decode(src, &v)
+			var errs json.DecodeErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("expected DecodeErrors but got %v", err)
+			}
+			if len(errs) != len(expected) {
+				t.Fatalf("expected %d errors but got %d: %v", len(expected), len(errs), err)
+			}
+			for i, e := range expected {
+				assertEq(t, "path", e.path, errs[i].Path)
+				assertEq(t, "type", e.typ, errs[i].Type)
+				assertEq(t, "line", e.line, errs[i].Line)
+			}
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				t.Fatal("expected DecodeErrors to unwrap to UnmarshalTypeError")
+			}
+			assertEq(t, "first path", "$.name", typeErr.Path)
+			assertEq(t, "age", 20, v.Age)
+			assertEq(t, "items", 2, len(v.Items))
+			assertEq(t, "item name", "b", v.Items[1].Name)
+			assertEq(t, "item price", 20, v.Items[1].Price)
+			assertEq(t, "score", 1, v.Scores["x"])
+			assertEq(t, "flag", "a", v.Flags[0])
+			assertEq(t, "note", "n", v.Note)
+		})
+	}
+	t.Run("syntax error", func(t *testing.T) {
+		var v Form
+		err := json.UnmarshalWithOption([]byte(`{"name": 1, "age": }`), &v, json.DecodeCollectErrors())
+		var syntaxErr *json.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("expected SyntaxError but got %v", err)
+		}
+	})
+	t.Run("no errors", func(t *testing.T) {
+		var v Form
+		assertErr(t, json.UnmarshalWithOption([]byte(`{"name": "a", "age": 1}`), &v, json.DecodeCollectErrors()))
+	})
+}
+
+func TestDecodeRejectNull(t *testing.T) {
+	type Inner struct {
+		ID int `json:"id"`
+	}
+	type Embedded struct {
+		Tag string `json:"tag"`
+	}
+	type T struct {
+		Embedded
+		Int       int             `json:"int"`
+		String    string          `json:"string"`
+		Struct    Inner           `json:"struct"`
+		Slice     []int           `json:"slice"`
+		Ptr       *Inner          `json:"ptr"`
+		Nullable  *Inner          `json:"nullable,nullable"`
+		NullInt   int             `json:"nullInt,nullable"`
+		Interface interface{}     `json:"interface"`
+		Raw       json.RawMessage `json:"raw"`
+	}
+	decoders := []struct {
+		name   string
+		decode func(string, interface{}, ...json.DecodeOptionFunc) error
+	}{
+		{
+			name: "Unmarshal",
+			decode: func(src string, v interface{}, opts ...json.DecodeOptionFunc) error {
+				return json.UnmarshalWithOption([]byte(src), v, opts...)
+			},
+		},
+		{
+			name: "Stream",
+			decode: func(src string, v interface{}, opts ...json.DecodeOptionFunc) error {
+				return json.NewDecoder(strings.NewReader(src)).DecodeWithOption(v, opts...)
+			},
+		},
+	}
+	for _, d := range decoders {
+		decode := d.decode
+		t.Run(d.name, func(t *testing.T) {
+			for _, test := range []struct {
+				key string
+				typ reflect.Type
+			}{
+				{key: "int", typ: reflect.TypeOf(0)},
+				{key: "string", typ: reflect.TypeOf("")},
+				{key: "struct", typ: reflect.TypeOf(Inner{})},
+				{key: "slice", typ: reflect.TypeOf([]int{})},
+				{key: "ptr", typ: reflect.TypeOf(&Inner{})},
+				{key: "tag", typ: reflect.TypeOf("")},
+			} {
+				src := `{"interface": 1, "` + test.key + `": null}`
+				v := T{Int: 1}
+				assertErr(t, decode(src, &v))
+
+				err := decode(src, &v, json.DecodeRejectNull())
+				var typeErr *json.UnmarshalTypeError
+				if !errors.As(err, &typeErr) {
+					t.Fatalf("%s: expected UnmarshalTypeError but got %v", test.key, err)
+				}
+				assertEq(t, "value", "null", typeErr.Value)
+				assertEq(t, "type", test.typ, typeErr.Type)
+				assertEq(t, "offset", int64(len(`{"interface": 1, "`+test.key+`": `)), typeErr.Offset)
+				assertEq(t, "path", "$."+test.key, typeErr.Path)
+			}
+
+			v := T{Nullable: &Inner{}, NullInt: 1, Interface: 1, Raw: json.RawMessage(`1`)}
+			assertErr(t, decode(`{"nullable": null, "nullInt": null, "interface": null, "raw": null}`, &v, json.DecodeRejectNull()))
+			assertEq(t, "nullable", true, v.Nullable == nil)
+			assertEq(t, "nullInt", 1, v.NullInt)
+			assertEq(t, "interface", nil, v.Interface)
+			assertEq(t, "raw", "null", string(v.Raw))
+
+			var items []T
+			err := decode(`[{"int": 1}, {"int": null, "string": null}]`, &items, json.DecodeRejectNull(), json.DecodeCollectErrors())
+			var errs json.DecodeErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("expected DecodeErrors but got %v", err)
+			}
+			assertEq(t, "errors", 2, len(errs))
+			assertEq(t, "first path", "$[1].int", errs[0].Path)
+			assertEq(t, "second path", "$[1].string", errs[1].Path)
+
+			var syntaxErr *json.SyntaxError
+			if err := decode(`{"int": nul}`, &v, json.DecodeRejectNull()); !errors.As(err, &syntaxErr) {
+				t.Fatalf("expected SyntaxError but got %v", err)
+			}
+		})
+	}
+}
+
+func TestDecodeAliases(t *testing.T) {
+	type Embedded struct {
+		Region string `json:"region,alias=zone"`
+	}
+	type T struct {
+		Embedded
+		NewName string `json:"newName,alias=oldName|legacy_name"`
+		Count   int    `json:"count,alias=total|n"`
+		Total   int    `json:"total"`
+	}
+	for _, test := range []struct {
+		name     string
+		src      string
+		expected T
+	}{
+		{name: "key", src: `{"newName":"a"}`, expected: T{NewName: "a"}},
+		{name: "alias", src: `{"oldName":"a"}`, expected: T{NewName: "a"}},
+		{name: "second alias", src: `{"legacy_name":"a"}`, expected: T{NewName: "a"}},
+		{name: "case insensitive", src: `{"OLDNAME":"a"}`, expected: T{NewName: "a"}},
+		{name: "last wins", src: `{"newName":"a","oldName":"b"}`, expected: T{NewName: "b"}},
+		{name: "key of other field", src: `{"total":1,"n":2}`, expected: T{Count: 2, Total: 1}},
+		{name: "embedded", src: `{"zone":"a"}`, expected: T{Embedded: Embedded{Region: "a"}}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var v T
+			assertErr(t, json.Unmarshal([]byte(test.src), &v))
+			assertEq(t, "unmarshal", test.expected, v)
+
+			v = T{}
+			assertErr(t, json.NewDecoder(strings.NewReader(test.src)).Decode(&v))
+			assertEq(t, "stream", test.expected, v)
+		})
+	}
+	t.Run("encode", func(t *testing.T) {
+		b, err := json.Marshal(T{NewName: "a"})
+		assertErr(t, err)
+		assertEq(t, "json", `{"region":"","newName":"a","count":0,"total":0}`, string(b))
+	})
+	t.Run("first win", func(t *testing.T) {
+		var v T
+		assertErr(t, json.UnmarshalWithOption([]byte(`{"oldName":"a","newName":"b"}`), &v, json.DecodeFieldPriorityFirstWin()))
+		assertEq(t, "first win", "a", v.NewName)
+	})
+	t.Run("duplicate", func(t *testing.T) {
+		var v T
+		err := json.UnmarshalWithOption([]byte(`{"newName":"a","legacy_name":"b"}`), &v, json.DecodeRejectDuplicateKeys())
+		var dupErr *json.DuplicateKeyError
+		if !errors.As(err, &dupErr) {
+			t.Fatalf("expected DuplicateKeyError but got %v", err)
+		}
+		assertEq(t, "key", "legacy_name", dupErr.Key)
+	})
+	t.Run("many keys", func(t *testing.T) {
+		type M struct {
+			A string `json:"a,alias=a1|a2|a3|a4|a5"`
+			B string `json:"b,alias=b1|b2|b3|b4|b5"`
+		}
+		var v M
+		assertErr(t, json.Unmarshal([]byte(`{"a5":"x","b3":"y"}`), &v))
+		assertEq(t, "unmarshal", M{A: "x", B: "y"}, v)
+	})
+}
+
+func TestDecodeLenient(t *testing.T) {
+	type T struct {
+		Int   int     `json:"int"`
+		Uint  uint8   `json:"uint"`
+		Float float64 `json:"float"`
+		Bool  bool    `json:"bool"`
+		Str   string  `json:"str"`
+		Ptr   *int    `json:"ptr"`
+		Map   map[string]string
+	}
+	unmarshal := func(src string, v interface{}, rules json.LenientRules) error {
+		return json.UnmarshalWithOption([]byte(src), v, json.DecodeLenient(rules))
+	}
+	stream := func(src string, v interface{}, rules json.LenientRules) error {
+		return json.NewDecoder(strings.NewReader(src)).DecodeWithOption(v, json.DecodeLenient(rules))
+	}
+	for _, test := range []struct {
+		name   string
+		decode func(string, interface{}, json.LenientRules) error
+	}{
+		{name: "Unmarshal", decode: unmarshal},
+		{name: "Stream", decode: stream},
+	} {
+		decode := test.decode
+		t.Run(test.name, func(t *testing.T) {
+			t.Run("string to number", func(t *testing.T) {
+				var v T
+				assertErr(t, decode(`{"int": "-42", "uint": "7", "float": "1.5e2"}`, &v, json.LenientStringToNumber))
+				assertEq(t, "int", -42, v.Int)
+				assertEq(t, "uint", uint8(7), v.Uint)
+				assertEq(t, "float", 150.0, v.Float)
+				if err := decode(`{"int": "1.5"}`, &v, json.LenientStringToNumber); err == nil {
+					t.Fatal("expected error for a float string into int")
+				}
+				if err := decode(`{"uint": "-1"}`, &v, json.LenientStringToNumber); err == nil {
+					t.Fatal("expected error for a negative string into uint")
+				}
+				if err := decode(`{"int": "42"}`, &v, json.LenientNumberToBool); err == nil {
+					t.Fatal("expected error without LenientStringToNumber")
+				}
+			})
+			t.Run("number to bool", func(t *testing.T) {
+				var v T
+				assertErr(t, decode(`{"bool": 1}`, &v, json.LenientNumberToBool))
+				assertEq(t, "bool", true, v.Bool)
+				assertErr(t, decode(`{"bool": 0}`, &v, json.LenientNumberToBool))
+				assertEq(t, "bool", false, v.Bool)
+				if err := decode(`{"bool": 2}`, &v, json.LenientNumberToBool); err == nil {
+					t.Fatal("expected error for 2 into bool")
+				}
+				if err := decode(`{"bool": 10}`, &v, json.LenientNumberToBool); err == nil {
+					t.Fatal("expected error for 10 into bool")
+				}
+			})
+			t.Run("empty string to null", func(t *testing.T) {
+				one := 1
+				v := T{Int: 1, Float: 1, Bool: true, Ptr: &one}
+				assertErr(t, decode(`{"int": "", "float": "", "bool": "", "ptr": ""}`, &v, json.LenientEmptyStringToNull))
+				assertEq(t, "int", 1, v.Int)
+				assertEq(t, "float", 1.0, v.Float)
+				assertEq(t, "bool", true, v.Bool)
+				if v.Ptr != nil {
+					t.Fatal("expected nil pointer")
+				}
+				assertErr(t, decode(`{"str": ""}`, &v, json.LenientEmptyStringToNull))
+				if err := decode(`{"int": "1"}`, &v, json.LenientEmptyStringToNull); err == nil {
+					t.Fatal("expected error without LenientStringToNumber")
+				}
+			})
+			t.Run("number to string", func(t *testing.T) {
+				var v T
+				assertErr(t, decode(`{"str": -1.50e3, "Map": {"a": 1}}`, &v, json.LenientNumberToString))
+				assertEq(t, "str", "-1.50e3", v.Str)
+				assertEq(t, "map", "1", v.Map["a"])
+				if err := decode(`{"str": true}`, &v, json.LenientNumberToString); err == nil {
+					t.Fatal("expected error for bool into string")
+				}
+				var m map[string]int
+				if err := decode(`{1: 1}`, &m, json.LenientNumberToString); err == nil {
+					t.Fatal("expected error for number object key")
+				}
+			})
+			t.Run("combined rules", func(t *testing.T) {
+				var v T
+				rules := json.LenientStringToNumber | json.LenientNumberToBool | json.LenientNumberToString
+				assertErr(t, decode(`{"int": "3", "bool": 1, "str": 5}`, &v, rules))
+				assertEq(t, "int", 3, v.Int)
+				assertEq(t, "bool", true, v.Bool)
+				assertEq(t, "str", "5", v.Str)
+			})
+		})
+	}
+	t.Run("without option", func(t *testing.T) {
+		var v T
+		if err := json.Unmarshal([]byte(`{"int": "42"}`), &v); err == nil {
+			t.Fatal("expected error without DecodeLenient")
+		}
+	})
+}
+
+func TestDecodeBigNumbers(t *testing.T) {
+	type T struct {
+		I *big.Int   `json:"i"`
+		F *big.Float `json:"f"`
+		R *big.Rat   `json:"r"`
+		V big.Int    `json:"v"`
+	}
+	const src = `{"i":123456789012345678901234567890,"f":1234567890.12345678901234567890,"r":"1/3","v":-98765432109876543210}`
+	assertResult := func(t *testing.T, v T) {
+		t.Helper()
+		assertEq(t, "int", "123456789012345678901234567890", v.I.String())
+		assertEq(t, "float", "1234567890.12345678901234567890", v.F.Text('f', 20))
+		assertEq(t, "rat", "1/3", v.R.String())
+		assertEq(t, "value", "-98765432109876543210", v.V.String())
+	}
+	t.Run("unmarshal", func(t *testing.T) {
+		var v T
+		assertErr(t, json.Unmarshal([]byte(src), &v))
+		assertResult(t, v)
+	})
+	t.Run("stream", func(t *testing.T) {
+		var v T
+		assertErr(t, json.NewDecoder(strings.NewReader(src)).Decode(&v))
+		assertResult(t, v)
+	})
+	t.Run("rat number", func(t *testing.T) {
+		var v *big.Rat
+		assertErr(t, json.Unmarshal([]byte(`0.125`), &v))
+		assertEq(t, "rat", "1/8", v.String())
+	})
+	t.Run("int with fraction", func(t *testing.T) {
+		var v *big.Int
+		err := json.Unmarshal([]byte(`1.5`), &v)
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			t.Fatalf("expected UnmarshalTypeError, got %v", err)
+		}
+		assertEq(t, "value", "number 1.5", typeErr.Value)
+	})
+	t.Run("null", func(t *testing.T) {
+		v := T{I: big.NewInt(1)}
+		assertErr(t, json.Unmarshal([]byte(`{"i":null}`), &v))
+		if v.I != nil {
+			t.Fatalf("expected nil, got %v", v.I)
+		}
+	})
+}
+
+func TestDecodeInterfaceNumbers(t *testing.T) {
+	const src = `{"id":12345678901234567890,"n":42,"f":1.5,"list":[-7,1e3]}`
+	t.Run("use number", func(t *testing.T) {
+		var v map[string]interface{}
+		assertErr(t, json.UnmarshalWithOption([]byte(src), &v, json.DecodeUseNumber()))
+		assertEq(t, "id", json.Number("12345678901234567890"), v["id"])
+		assertEq(t, "n", json.Number("42"), v["n"])
+		if exp := []interface{}{json.Number("-7"), json.Number("1e3")}; !reflect.DeepEqual(exp, v["list"]) {
+			t.Fatalf("failed to test for list. exp=[%#v] but act=[%#v]", exp, v["list"])
+		}
+	})
+	t.Run("integers as int64", func(t *testing.T) {
+		var v map[string]interface{}
+		assertErr(t, json.UnmarshalWithOption([]byte(src), &v, json.DecodeIntegersAsInt64()))
+		assertEq(t, "id", 12345678901234567890.0, v["id"])
+		assertEq(t, "n", int64(42), v["n"])
+		assertEq(t, "f", 1.5, v["f"])
+		if exp := []interface{}{int64(-7), 1000.0}; !reflect.DeepEqual(exp, v["list"]) {
+			t.Fatalf("failed to test for list. exp=[%#v] but act=[%#v]", exp, v["list"])
+		}
+	})
+	t.Run("both", func(t *testing.T) {
+		var v map[string]interface{}
+		assertErr(t, json.UnmarshalWithOption([]byte(src), &v, json.DecodeIntegersAsInt64(), json.DecodeUseNumber()))
+		assertEq(t, "id", json.Number("12345678901234567890"), v["id"])
+		assertEq(t, "n", int64(42), v["n"])
+		assertEq(t, "f", json.Number("1.5"), v["f"])
+	})
+	t.Run("stream", func(t *testing.T) {
+		var v map[string]interface{}
+		dec := json.NewDecoder(strings.NewReader(src))
+		assertErr(t, dec.DecodeWithOption(&v, json.DecodeIntegersAsInt64(), json.DecodeUseNumber()))
+		assertEq(t, "id", json.Number("12345678901234567890"), v["id"])
+		assertEq(t, "n", int64(42), v["n"])
+		if exp := []interface{}{int64(-7), json.Number("1e3")}; !reflect.DeepEqual(exp, v["list"]) {
+			t.Fatalf("failed to test for list. exp=[%#v] but act=[%#v]", exp, v["list"])
+		}
+	})
+	t.Run("default", func(t *testing.T) {
+		var v interface{}
+		assertErr(t, json.Unmarshal([]byte(`42`), &v))
+		assertEq(t, "n", 42.0, v)
+	})
+}
+
+func TestDecoderDecodeEach(t *testing.T) {
+	type T struct {
+		A int `json:"a"`
+	}
+	t.Run("elements", func(t *testing.T) {
+		dec := json.NewDecoder(strings.NewReader(`[{"a":1}, {"a":2}, "skipped", {"a":4}] [5]`))
+		var got []T
+		var indexes []int
+		err := dec.DecodeEach(func(i int, dec *json.Decoder) error {
+			indexes = append(indexes, i)
+			if i == 2 {
+				return nil
+			}
+			var v T
+			if err := dec.Decode(&v); err != nil {
+				return err
+			}
+			got = append(got, v)
+			return nil
+		})
+		assertErr(t, err)
+		if !reflect.DeepEqual([]T{{A: 1}, {A: 2}, {A: 4}}, got) {
+			t.Fatalf("unexpected elements: %v", got)
+		}
+		if !reflect.DeepEqual([]int{0, 1, 2, 3}, indexes) {
+			t.Fatalf("unexpected indexes: %v", indexes)
+		}
+		var next []int
+		assertErr(t, dec.Decode(&next))
+		if !reflect.DeepEqual([]int{5}, next) {
+			t.Fatalf("unexpected next value: %v", next)
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		called := false
+		err := json.NewDecoder(strings.NewReader(` [ ] `)).DecodeEach(func(int, *json.Decoder) error {
+			called = true
+			return nil
+		})
+		assertErr(t, err)
+		assertEq(t, "called", false, called)
+	})
+	t.Run("stop", func(t *testing.T) {
+		errStop := errors.New("stop")
+		count := 0
+		err := json.NewDecoder(strings.NewReader(`[1,2,3]`)).DecodeEach(func(i int, dec *json.Decoder) error {
+			count++
+			return errStop
+		})
+		if err != errStop {
+			t.Fatalf("expected stop error, got %v", err)
+		}
+		assertEq(t, "count", 1, count)
+	})
+	t.Run("errors", func(t *testing.T) {
+		for _, src := range []string{`{"a":1}`, `[1 2]`, `[1,]`, `[,1]`, `[1,2`} {
+			err := json.NewDecoder(strings.NewReader(src)).DecodeEach(func(i int, dec *json.Decoder) error {
+				var v int
+				return dec.Decode(&v)
+			})
+			if err == nil {
+				t.Fatalf("%s: expected error", src)
+			}
+		}
+	})
+}
+
+func TestDecodeValidateSchema(t *testing.T) {
+	s := schema.MustCompile([]byte(`{
+  "type": "object",
+  "properties": {
+    "name": {"type": "string", "minLength": 1},
+    "tags": {"type": "array", "items": {"type": "string"}}
+  },
+  "required": ["name"]
+}`))
+	type T struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	t.Run("unmarshal", func(t *testing.T) {
+		var v T
+		assertErr(t, json.UnmarshalWithOption([]byte(`{"name":"a","tags":["b"]}`), &v, json.DecodeValidateSchema(s)))
+		assertEq(t, "name", "a", v.Name)
+
+		err := json.UnmarshalWithOption([]byte(`{"name":"","tags":["b",1]}`), &v, json.DecodeValidateSchema(s))
+		var errs schema.ValidationErrors
+		if !errors.As(err, &errs) {
+			t.Fatalf("expected ValidationErrors, got %v", err)
+		}
+		assertEq(t, "errors", 2, len(errs))
+		assertEq(t, "path", "$.name", errs[0].Path)
+		assertEq(t, "path", "$.tags[1]", errs[1].Path)
+	})
+	t.Run("nested values", func(t *testing.T) {
+		s := schema.MustCompile([]byte(`{
+  "properties": {
+    "items": {"items": {"properties": {"id": {"minimum": 1}}, "required": ["id"]}},
+    "attrs": {"additionalProperties": {"type": "string"}},
+    "any": {"properties": {"n": {"maximum": 3}}},
+    "extra": {"type": "array", "minItems": 2}
+  }
+}`))
+		type Item struct {
+			ID int `json:"id"`
+		}
+		type V struct {
+			Items []Item          `json:"items"`
+			Attrs map[string]int  `json:"attrs"`
+			Any   interface{}     `json:"any"`
+			Named map[string]Item `json:"named"`
+		}
+		data := `{"items":[{"id":1},{"id":0},{}],"attrs":{"a":1},"any":{"n":4},"extra":[1],"named":{"x":{"id":2}}}`
+		expected := s.Validate([]byte(data))
+		var expectedErrs schema.ValidationErrors
+		if !errors.As(expected, &expectedErrs) {
+			t.Fatalf("expected ValidationErrors, got %v", expected)
+		}
+		assertEq(t, "expected errors", 5, len(expectedErrs))
+		var v V
+		actual := json.UnmarshalWithOption([]byte(data), &v, json.DecodeValidateSchema(s))
+		assertEq(t, "errors", expected.Error(), fmt.Sprint(actual))
+		assertEq(t, "decoded", 1, v.Items[0].ID)
+
+		dec := json.NewDecoder(strings.NewReader(data))
+		actual = dec.DecodeWithOption(&v, json.DecodeValidateSchema(s))
+		assertEq(t, "stream errors", expected.Error(), fmt.Sprint(actual))
+	})
+	t.Run("escaped strings", func(t *testing.T) {
+		s := schema.MustCompile([]byte(`{
+  "propertyNames": {"maxLength": 2},
+  "properties": {"es": {"maxLength": 3}, "a": {"items": {"const": "x\"y"}}}
+}`))
+		type V struct {
+			Es string   `json:"es"`
+			A  []string `json:"a"`
+		}
+		data := `{"es":"a\"\\b\u00e9","a":["x\"y","x\\y"],"\u0061\"b":1}`
+		expected := s.Validate([]byte(data))
+		var expectedErrs schema.ValidationErrors
+		if !errors.As(expected, &expectedErrs) {
+			t.Fatalf("expected ValidationErrors, got %v", expected)
+		}
+		assertEq(t, "expected errors", 3, len(expectedErrs))
+		var v V
+		actual := json.UnmarshalWithOption([]byte(data), &v, json.DecodeValidateSchema(s))
+		assertEq(t, "errors", expected.Error(), fmt.Sprint(actual))
+		assertEq(t, "decoded", "a\"\\bé", v.Es)
+
+		dec := json.NewDecoder(strings.NewReader(data))
+		actual = dec.DecodeWithOption(&v, json.DecodeValidateSchema(s))
+		assertEq(t, "stream errors", expected.Error(), fmt.Sprint(actual))
+	})
+	t.Run("stream", func(t *testing.T) {
+		dec := json.NewDecoder(strings.NewReader(`{"name":"a"}` + "\n" + `{"tags":[]}` + "\n" + `{"name":"c"}`))
+		var v T
+		assertErr(t, dec.DecodeWithOption(&v, json.DecodeValidateSchema(s)))
+		assertEq(t, "name", "a", v.Name)
+
+		err := dec.Decode(&v)
+		var errs schema.ValidationErrors
+		if !errors.As(err, &errs) {
+			t.Fatalf("expected ValidationErrors, got %v", err)
+		}
+		assertEq(t, "keyword", "required", errs[0].Keyword)
+		assertEq(t, "line", int64(2), errs[0].Line)
+		assertEq(t, "offset", int64(13), errs[0].Offset)
+	})
+}
+
+func TestLazyValue(t *testing.T) {
+	var v struct {
+		Body json.LazyValue `json:"body"`
+	}
+	assertErr(t, json.Unmarshal([]byte(`{"body": {"id": 12, "name": "gopher", "tags": ["a", {"b": [1, 2]}], "id": 13, "ok": true, "none": null } }`), &v))
+	body := &v.Body
+	assertEq(t, "kind", json.KindObject, body.Kind())
+	n, err := body.Len()
+	assertErr(t, err)
+	assertEq(t, "len", 6, n)
+
+	id, err := body.Get("id")
+	assertErr(t, err)
+	i, err := id.Int()
+	assertErr(t, err)
+	assertEq(t, "last duplicated key", int64(13), i)
+
+	name, err := body.Get("name")
+	assertErr(t, err)
+	s, err := name.String()
+	assertErr(t, err)
+	assertEq(t, "name", "gopher", s)
+
+	tags, err := body.Get("tags")
+	assertErr(t, err)
+	assertEq(t, "tags kind", json.KindArray, tags.Kind())
+	elem, err := tags.Index(1)
+	assertErr(t, err)
+	b, err := elem.Get("b")
+	assertErr(t, err)
+	assertEq(t, "raw", `[1, 2]`, string(b.Raw()))
+	var nums []int
+	assertErr(t, b.Decode(&nums))
+	if !reflect.DeepEqual(nums, []int{1, 2}) {
+		t.Fatalf("unexpected decoded value %v", nums)
+	}
+	out, err := tags.Index(2)
+	assertErr(t, err)
+	if out != nil {
+		t.Fatalf("expected nil for index out of range, got %s", out.Raw())
+	}
+	missing, err := body.Get("missing")
+	assertErr(t, err)
+	if missing != nil {
+		t.Fatalf("expected nil for missing key, got %s", missing.Raw())
+	}
+
+	for _, key := range []string{"ok", "none"} {
+		value, err := body.Get(key)
+		assertErr(t, err)
+		var typeErr *json.UnmarshalTypeError
+		if _, err := value.String(); !errors.As(err, &typeErr) {
+			t.Fatalf("%s: expected UnmarshalTypeError, got %v", key, err)
+		}
+	}
+	if _, err := name.Get("x"); err == nil {
+		t.Fatal("expected error for Get on string")
+	}
+	if _, err := body.Index(0); err == nil {
+		t.Fatal("expected error for Index on object")
+	}
+
+	encoded, err := json.Marshal(v)
+	assertErr(t, err)
+	assertEq(t, "marshal", `{"body":{"id":12,"name":"gopher","tags":["a",{"b":[1,2]}],"id":13,"ok":true,"none":null}}`, string(encoded))
+
+	t.Run("zero", func(t *testing.T) {
+		var v json.LazyValue
+		assertEq(t, "kind", json.KindInvalid, v.Kind())
+		encoded, err := json.Marshal(v)
+		assertErr(t, err)
+		assertEq(t, "marshal", "null", string(encoded))
+		if err := v.Decode(new(int)); err == nil {
+			t.Fatal("expected error for Decode of zero value")
+		}
+	})
+	t.Run("syntax error", func(t *testing.T) {
+		var v json.LazyValue
+		assertErr(t, v.UnmarshalJSON([]byte("[1,\n 2 3]")))
+		_, err := v.Index(0)
+		var syntaxErr *json.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("expected SyntaxError, got %v", err)
+		}
+		assertEq(t, "line", int64(2), syntaxErr.Line)
+	})
+}
+
+func TestValue(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		src := `{"b":1.50,"a":[1,{"x":null},true],"c":"sé","e":{},"f":[]}`
+		var v json.Value
+		assertErr(t, json.Unmarshal([]byte(src), &v))
+		assertEq(t, "kind", json.KindObject, v.Kind())
+		if !reflect.DeepEqual(v.Keys(), []string{"b", "a", "c", "e", "f"}) {
+			t.Fatalf("unexpected keys %v", v.Keys())
+		}
+		assertEq(t, "number", json.Number("1.50"), v.Get("/b").Number())
+		assertEq(t, "null", json.KindNull, v.Get("/a/1/x").Kind())
+		assertEq(t, "bool", true, v.Get("/a/2").Bool())
+		assertEq(t, "string", "sé", v.Get("/c").Text())
+		got, err := json.Marshal(&v)
+		assertErr(t, err)
+		assertEq(t, "marshal", `{"b":1.50,"a":[1,{"x":null},true],"c":"sé","e":{},"f":[]}`, string(got))
+		got, err = json.Marshal(v)
+		assertErr(t, err)
+		assertEq(t, "marshal value", `{"b":1.50,"a":[1,{"x":null},true],"c":"sé","e":{},"f":[]}`, string(got))
+		got, err = json.MarshalIndent(&v, "", "  ")
+		assertErr(t, err)
+		assertEq(t, "indent", `{
+  "b": 1.50,
+  "a": [
+    1,
+    {
+      "x": null
+    },
+    true
+  ],
+  "c": "sé",
+  "e": {},
+  "f": []
+}`, string(got))
+	})
+	t.Run("colorize", func(t *testing.T) {
+		var v json.Value
+		assertErr(t, json.Unmarshal([]byte(`{"a":[1,true,null,"s"]}`), &v))
+		expected := struct {
+			A []interface{} `json:"a"`
+		}{A: []interface{}{1, true, nil, "s"}}
+		colorize := json.Colorize(json.DefaultColorScheme)
+		got, err := json.MarshalWithOption(&v, colorize)
+		assertErr(t, err)
+		want, err := json.MarshalWithOption(expected, colorize)
+		assertErr(t, err)
+		assertEq(t, "colorize", string(want), string(got))
+		got, err = json.MarshalIndentWithOption(&v, "", "  ", colorize)
+		assertErr(t, err)
+		want, err = json.MarshalIndentWithOption(expected, "", "  ", colorize)
+		assertErr(t, err)
+		assertEq(t, "colorize indent", string(want), string(got))
+	})
+	t.Run("get set delete", func(t *testing.T) {
+		var v json.Value
+		assertErr(t, json.Unmarshal([]byte(`{"items":[{"name":"a"}],"a/b":{"~":1}}`), &v))
+		assertEq(t, "escaped", json.Number("1"), v.Get("/a~1b/~0").Number())
+		if v.Get("/items/1") != nil || v.Get("/items/01") != nil || v.Get("items") != nil {
+			t.Fatal("expected nil for missing paths")
+		}
+		assertErr(t, v.Set("/items/0/count", json.NewNumber("2")))
+		assertErr(t, v.Set("/items/-", json.NewString("b")))
+		assertErr(t, v.Set("/items/2", json.NewBool(false)))
+		assertErr(t, v.Set("/meta/created/by", json.NewString("me")))
+		assertErr(t, v.Set("/items/0/name", nil))
+		if err := v.Set("/items/5", json.NewNull()); err == nil {
+			t.Fatal("expected out of range error")
+		}
+		if err := v.Set("/items/1/x", json.NewNull()); err == nil {
+			t.Fatal("expected error for setting a member of a string")
+		}
+		assertEq(t, "delete", true, v.Delete("/a~1b"))
+		assertEq(t, "delete missing", false, v.Delete("/a~1b"))
+		assertEq(t, "delete element", true, v.Delete("/items/2"))
+		got, err := json.Marshal(&v)
+		assertErr(t, err)
+		assertEq(t, "result", `{"items":[{"name":null,"count":2},"b"],"meta":{"created":{"by":"me"}}}`, string(got))
+
+		root := json.NewObject()
+		assertErr(t, root.Set("/list", json.NewArray(json.NewNumber("1"), nil)))
+		assertErr(t, root.Set("", json.NewArray(root.Get("/list"))))
+		got, err = json.Marshal(root)
+		assertErr(t, err)
+		assertEq(t, "replace root", `[[1,null]]`, string(got))
+	})
+	t.Run("many members", func(t *testing.T) {
+		v := json.NewObject()
+		for i := 0; i < 20; i++ {
+			assertErr(t, v.Set("/k"+strconv.Itoa(i), json.NewNumber(json.Number(strconv.Itoa(i)))))
+		}
+		assertErr(t, v.Set("/k3", json.NewString("x")))
+		assertEq(t, "len", 20, v.Len())
+		assertEq(t, "replaced", "x", v.Get("/k3").Text())
+		assertEq(t, "delete", true, v.Delete("/k0"))
+		assertEq(t, "after delete", json.Number("19"), v.Get("/k19").Number())
+		assertEq(t, "first key", "k1", v.Keys()[0])
+	})
+	t.Run("fields", func(t *testing.T) {
+		type T struct {
+			A json.Value             `json:"a"`
+			B *json.Value            `json:"b"`
+			C *json.Value            `json:"c,omitempty"`
+			D []*json.Value          `json:"d"`
+			E map[string]*json.Value `json:"e"`
+		}
+		var v T
+		src := `{"a":[1,2],"b":{"x":"y"},"d":[null,"s"],"e":{"k":1e3}}`
+		assertErr(t, json.Unmarshal([]byte(src), &v))
+		assertEq(t, "a", 2, v.A.Len())
+		assertEq(t, "b", "y", v.B.Get("/x").Text())
+		assertEq(t, "e", json.Number("1e3"), v.E["k"].Number())
+		got, err := json.Marshal(v)
+		assertErr(t, err)
+		assertEq(t, "marshal", src, string(got))
+		got, err = json.Marshal(T{})
+		assertErr(t, err)
+		assertEq(t, "zero", `{"a":null,"b":null,"d":null,"e":null}`, string(got))
+
+		var stream T
+		assertErr(t, json.NewDecoder(strings.NewReader(src)).Decode(&stream))
+		got, err = json.Marshal(&stream)
+		assertErr(t, err)
+		assertEq(t, "stream", src, string(got))
+		got, err = json.Marshal(map[string]interface{}{"v": &stream.A})
+		assertErr(t, err)
+		assertEq(t, "interface", `{"v":[1,2]}`, string(got))
+	})
+	t.Run("errors", func(t *testing.T) {
+		var v json.Value
+		err := json.Unmarshal([]byte(`{"a":[1,{"b":01}]}`), &v)
+		var syntaxErr *json.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("expected SyntaxError but got %v", err)
+		}
+		assertEq(t, "path", "$.a[1].b", syntaxErr.Path)
+		err = json.NewDecoder(strings.NewReader(`[1,-]`)).Decode(&v)
+		if err == nil {
+			t.Fatal("expected error for invalid number in stream")
+		}
+		if err := json.UnmarshalWithOption([]byte(`{"a":1,"a":2}`), &v, json.DecodeRejectDuplicateKeys()); err == nil {
+			t.Fatal("expected duplicate key error")
+		}
+		if _, err := json.Marshal(json.NewNumber("1x")); err == nil {
+			t.Fatal("expected error for invalid number literal")
+		}
+	})
+}
@@ -0,0 +1,443 @@
+package json
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json/internal/decoder"
+	"github.com/goccy/go-json/internal/errors"
+)
+
+// ChangeKind is the kind of a Change, named after the JSON Patch operation that applies it.
+type ChangeKind int
+
+const (
+	ChangeAdd     ChangeKind = iota // the value was added
+	ChangeRemove                    // the value was removed
+	ChangeReplace                   // the value was changed
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdd:
+		return "add"
+	case ChangeRemove:
+		return "remove"
+	}
+	return "replace"
+}
+
+// Change is a difference between two JSON documents found by Diff.
+type Change struct {
+	Kind ChangeKind
+	// Path is the JSON Pointer (RFC 6901) of the value, e.g. /items/0/name.
+	Path string
+	// Old is the value in the first document. It is nil for ChangeAdd.
+	Old RawMessage
+	// New is the value in the second document. It is nil for ChangeRemove.
+	New RawMessage
+}
+
+// Changes lists the differences between two JSON documents in the order they must be applied.
+type Changes []Change
+
+// Patch returns the changes as a JSON Patch (RFC 6902) document,
+// which transforms the first document passed to Diff into the second one.
+func (c Changes) Patch() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, change := range c {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		path, err := Marshal(change.Path)
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(`{"op":"`)
+		buf.WriteString(change.Kind.String())
+		buf.WriteString(`","path":`)
+		buf.Write(path)
+		if change.Kind != ChangeRemove {
+			buf.WriteString(`,"value":`)
+			if err := Compact(&buf, change.New); err != nil {
+				return nil, err
+			}
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// Equal reports whether the JSON documents a and b are semantically equal.
+// White space and the order of object members are insignificant, strings are compared
+// after unescaping and numbers by their exact value, so 1, 1.0 and 10e-1 are equal.
+// If an object has duplicate keys, the last value is compared like when decoding into a map.
+// Equal returns false if a or b is not valid JSON.
+//
+// The documents are compared with the decoder's tokenizer without decoding them into Go values,
+// and the comparison stops at the first difference.
+func Equal(a, b []byte) bool {
+	c := newComparer(a, b)
+	endA, endB, equal, err := c.equal(c.start(c.a), c.start(c.b), 0)
+	return err == nil && equal && c.end(c.a, endA) && c.end(c.b, endB)
+}
+
+// Diff returns the changes that transform the JSON document a into b, compared like Equal.
+// Objects are compared member by member and arrays element by element at the same index:
+// elements beyond the end of the shorter array are added or removed at the end.
+// The paths of the changes are JSON Pointers, and Changes.Patch returns them as a JSON Patch.
+// The values of the changes do not share memory with a and b.
+func Diff(a, b []byte) (Changes, error) {
+	c := newComparer(a, b)
+	startA, startB := c.start(c.a), c.start(c.b)
+	if err := validateDocument(c.a, startA, false); err != nil {
+		return nil, errors.Locate(err, a, 0, 1, 0)
+	}
+	if err := validateDocument(c.b, startB, false); err != nil {
+		return nil, errors.Locate(err, b, 0, 1, 0)
+	}
+	if err := c.diff(startA, startB, 0, ""); err != nil {
+		return nil, err
+	}
+	return c.changes, nil
+}
+
+// validateDocument checks the full JSON grammar of the document in buf starting at cursor.
+// If rejectDuplicateKeys is true, objects must not repeat a key.
+func validateDocument(buf []byte, cursor int64, rejectDuplicateKeys bool) error {
+	s := &decoder.Scanner{Buf: buf, Cursor: cursor, RejectDuplicateKeys: rejectDuplicateKeys}
+	if err := s.Skip(0); err != nil {
+		return err
+	}
+	if c := s.Next(); c != 0 || s.Cursor != int64(len(buf))-1 {
+		return errors.ErrAfterTopLevelValue(c, s.Cursor)
+	}
+	return nil
+}
+
+// comparer compares two nul terminated JSON documents.
+type comparer struct {
+	a, b    []byte
+	changes Changes
+}
+
+func newComparer(a, b []byte) *comparer {
+	c := &comparer{a: make([]byte, len(a)+1), b: make([]byte, len(b)+1)} // append nul byte to the end
+	copy(c.a, a)
+	copy(c.b, b)
+	return c
+}
+
+func (c *comparer) start(buf []byte) int64 {
+	s := &decoder.Scanner{Buf: buf}
+	s.Next()
+	return s.Cursor
+}
+
+// end reports whether only white space follows the cursor in buf.
+func (c *comparer) end(buf []byte, cursor int64) bool {
+	s := &decoder.Scanner{Buf: buf, Cursor: cursor}
+	return s.Next() == 0 && s.Cursor == int64(len(buf))-1
+}
+
+// compareMember is a member of an object or an element of an array.
+type compareMember struct {
+	key        string
+	start, end int64
+}
+
+// compareMembers returns the members of the object or the elements of the array at cursor
+// and the cursor after it. Member values are skipped without being decoded.
+func compareMembers(buf []byte, cursor, depth int64) ([]compareMember, int64, error) {
+	end := byte(']')
+	if buf[cursor] == '{' {
+		end = '}'
+	}
+	s := &decoder.Scanner{Buf: buf, Cursor: cursor}
+	if err := s.Begin(buf[cursor], depth+1); err != nil {
+		return nil, 0, err
+	}
+	var list []compareMember
+	for first := true; ; first = false {
+		more, err := s.Element(first, end)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !more {
+			return list, s.Cursor, nil
+		}
+		var key string
+		if end == '}' {
+			k, err := s.Key()
+			if err != nil {
+				return nil, 0, err
+			}
+			key = string(k)
+			s.Next()
+		}
+		valueEnd, err := decoder.SkipValue(buf, s.Cursor, depth+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		list = append(list, compareMember{key: key, start: s.Cursor, end: valueEnd})
+		s.Cursor = valueEnd
+	}
+}
+
+// lastIndex returns the index of the last member with each key.
+func lastIndex(list []compareMember) map[string]int {
+	index := make(map[string]int, len(list))
+	for i, m := range list {
+		index[m.key] = i
+	}
+	return index
+}
+
+// equal compares the values at ca in c.a and cb in c.b and returns the cursors after them.
+// The cursors are only valid if the values are equal.
+func (c *comparer) equal(ca, cb, depth int64) (int64, int64, bool, error) {
+	x, y := valueClass(c.a[ca]), valueClass(c.b[cb])
+	if x != y {
+		return 0, 0, false, nil
+	}
+	switch x {
+	case '{', '[':
+		listA, endA, err := compareMembers(c.a, ca, depth)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		listB, endB, err := compareMembers(c.b, cb, depth)
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if x == '[' {
+			if len(listA) != len(listB) {
+				return 0, 0, false, nil
+			}
+			for i := range listA {
+				if equal, err := c.equalMember(listA[i], listB[i], depth+1); err != nil || !equal {
+					return 0, 0, false, err
+				}
+			}
+			return endA, endB, true, nil
+		}
+		indexA, indexB := lastIndex(listA), lastIndex(listB)
+		if len(indexA) != len(indexB) {
+			return 0, 0, false, nil
+		}
+		for key, i := range indexA {
+			j, exists := indexB[key]
+			if !exists {
+				return 0, 0, false, nil
+			}
+			if equal, err := c.equalMember(listA[i], listB[j], depth+1); err != nil || !equal {
+				return 0, 0, false, err
+			}
+		}
+		return endA, endB, true, nil
+	case '"':
+		sa := &decoder.Scanner{Buf: c.a, Cursor: ca}
+		strA, err := sa.String()
+		if err != nil {
+			return 0, 0, false, err
+		}
+		sb := &decoder.Scanner{Buf: c.b, Cursor: cb}
+		strB, err := sb.String()
+		if err != nil {
+			return 0, 0, false, err
+		}
+		return sa.Cursor, sb.Cursor, bytes.Equal(strA, strB), nil
+	case '0':
+		sa := &decoder.Scanner{Buf: c.a, Cursor: ca}
+		numA, err := sa.Number()
+		if err != nil {
+			return 0, 0, false, err
+		}
+		sb := &decoder.Scanner{Buf: c.b, Cursor: cb}
+		numB, err := sb.Number()
+		if err != nil {
+			return 0, 0, false, err
+		}
+		return sa.Cursor, sb.Cursor, numberEqual(numA, numB), nil
+	}
+	sa := &decoder.Scanner{Buf: c.a, Cursor: ca}
+	litA, err := sa.Literal()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	sb := &decoder.Scanner{Buf: c.b, Cursor: cb}
+	litB, err := sb.Literal()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	return sa.Cursor, sb.Cursor, litA == litB, nil
+}
+
+// valueClass returns the first byte of the values of the same JSON type as the value starting with c.
+// Numbers return '0' and booleans 't'.
+func valueClass(c byte) byte {
+	switch c {
+	case '-', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return '0'
+	case 'f':
+		return 't'
+	}
+	return c
+}
+
+// equalMember compares member values, which must span the whole member.
+func (c *comparer) equalMember(a, b compareMember, depth int64) (bool, error) {
+	endA, endB, equal, err := c.equal(a.start, b.start, depth)
+	if err != nil || !equal {
+		return false, err
+	}
+	return endA == a.end && endB == b.end, nil
+}
+
+// diff appends the changes between the values at ca in c.a and cb in c.b to c.changes.
+// Both documents have been validated.
+func (c *comparer) diff(ca, cb, depth int64, path string) error {
+	x, y := c.a[ca], c.b[cb]
+	if (x != '{' && x != '[') || x != y {
+		_, _, equal, err := c.equal(ca, cb, depth)
+		if err != nil {
+			return err
+		}
+		if !equal {
+			c.changes = append(c.changes, Change{Kind: ChangeReplace, Path: path, Old: c.raw(c.a, ca, depth), New: c.raw(c.b, cb, depth)})
+		}
+		return nil
+	}
+	listA, _, err := compareMembers(c.a, ca, depth)
+	if err != nil {
+		return err
+	}
+	listB, _, err := compareMembers(c.b, cb, depth)
+	if err != nil {
+		return err
+	}
+	if x == '[' {
+		common := len(listA)
+		if len(listB) < common {
+			common = len(listB)
+		}
+		for i := 0; i < common; i++ {
+			if err := c.diff(listA[i].start, listB[i].start, depth+1, path+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+		for i := common; i < len(listB); i++ {
+			c.changes = append(c.changes, Change{Kind: ChangeAdd, Path: path + "/" + strconv.Itoa(i), New: c.member(c.b, listB[i])})
+		}
+		// remove from the end so that the indexes of the remaining removals stay valid
+		for i := len(listA) - 1; i >= common; i-- {
+			c.changes = append(c.changes, Change{Kind: ChangeRemove, Path: path + "/" + strconv.Itoa(i), Old: c.member(c.a, listA[i])})
+		}
+		return nil
+	}
+	indexA, indexB := lastIndex(listA), lastIndex(listB)
+	for i, m := range listA {
+		if indexA[m.key] != i {
+			continue
+		}
+		memberPath := path + "/" + escapePointerToken(m.key)
+		j, exists := indexB[m.key]
+		if !exists {
+			c.changes = append(c.changes, Change{Kind: ChangeRemove, Path: memberPath, Old: c.member(c.a, m)})
+			continue
+		}
+		if err := c.diff(m.start, listB[j].start, depth+1, memberPath); err != nil {
+			return err
+		}
+	}
+	for j, m := range listB {
+		if _, exists := indexA[m.key]; exists || indexB[m.key] != j {
+			continue
+		}
+		c.changes = append(c.changes, Change{Kind: ChangeAdd, Path: path + "/" + escapePointerToken(m.key), New: c.member(c.b, m)})
+	}
+	return nil
+}
+
+// raw returns the value at cursor in buf, which has been validated.
+func (c *comparer) raw(buf []byte, cursor, depth int64) RawMessage {
+	end, _ := decoder.SkipValue(buf, cursor, depth)
+	return RawMessage(buf[cursor:end:end])
+}
+
+func (c *comparer) member(buf []byte, m compareMember) RawMessage {
+	return RawMessage(buf[m.start:m.end:m.end])
+}
+
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// numberEqual reports whether the number literals a and b have the same value.
+func numberEqual(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	x, ok := parseDecimal(a)
+	if !ok {
+		return false
+	}
+	y, ok := parseDecimal(b)
+	if !ok {
+		return false
+	}
+	return x.neg == y.neg && x.exp == y.exp && bytes.Equal(x.digits, y.digits)
+}
+
+// decimal is a number in the form digits * 10^exp, where digits has no leading or trailing zeros.
+// Zero has no digits and is not negative.
+type decimal struct {
+	neg    bool
+	digits []byte
+	exp    int64
+}
+
+// maxDecimalExp bounds the exponents of the numbers compared by value.
+// Numbers with larger exponents are only equal to the same literal.
+const maxDecimalExp = 1 << 60
+
+func parseDecimal(literal []byte) (decimal, bool) {
+	var d decimal
+	i := 0
+	if literal[0] == '-' {
+		d.neg = true
+		i++
+	}
+	digits := make([]byte, 0, len(literal))
+	for ; i < len(literal) && literal[i] >= '0' && literal[i] <= '9'; i++ {
+		digits = append(digits, literal[i])
+	}
+	if i < len(literal) && literal[i] == '.' {
+		for i++; i < len(literal) && literal[i] >= '0' && literal[i] <= '9'; i++ {
+			digits = append(digits, literal[i])
+			d.exp--
+		}
+	}
+	if i < len(literal) {
+		exp, err := strconv.ParseInt(string(literal[i+1:]), 10, 64)
+		if err != nil || exp > maxDecimalExp || exp < -maxDecimalExp {
+			return d, false
+		}
+		d.exp += exp
+	}
+	for len(digits) > 0 && digits[0] == '0' {
+		digits = digits[1:]
+	}
+	if len(digits) == 0 {
+		return decimal{}, true
+	}
+	for digits[len(digits)-1] == '0' {
+		digits = digits[:len(digits)-1]
+		d.exp++
+	}
+	d.digits = digits
+	return d, true
+}
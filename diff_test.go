@@ -0,0 +1,93 @@
+package json_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		equal bool
+	}{
+		{`{"a": 1, "b": [true, null, "x"]}`, `{"b":[true,null,"x"],"a":1}`, true},
+		{`1`, `1.0`, true},
+		{`[100, -0, 0.5]`, `[1e2, 0, 5E-1]`, true},
+		{`12345678901234567890`, `12345678901234567891`, false},
+		{`"é\n"`, `"é\n"`, true},
+		{`{"a": 1, "a": 2}`, `{"a": 2}`, true},
+		{`{"a": 1}`, `{"a": 1, "b": 1}`, false},
+		{`[1, 2]`, `[2, 1]`, false},
+		{`[1]`, `[1, 1]`, false},
+		{`{"a": [1, {"b": false}]}`, `{"a": [1, {"b": true}]}`, false},
+		{`"1"`, `1`, false},
+		{`null`, `false`, false},
+		{`{}`, `[]`, false},
+		{`[1,]`, `[1,]`, false},
+		{`1 2`, `1 2`, false},
+		{``, ``, false},
+	}
+	for _, test := range tests {
+		assertEq(t, test.a+" == "+test.b, test.equal, json.Equal([]byte(test.a), []byte(test.b)))
+		assertEq(t, test.b+" == "+test.a, test.equal, json.Equal([]byte(test.b), []byte(test.a)))
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := `{"name": "gopher", "age": 10, "tags": ["a", "b", "c"], "owner": {"id": 1.0, "x/y": true}, "old": null}`
+	b := `{"age": 11, "name": "gopher", "tags": ["a", "B"], "owner": {"id": 1, "x/y": false}, "new": {"k": [1, 2]}}`
+	changes, err := json.Diff([]byte(a), []byte(b))
+	assertErr(t, err)
+	expected := []struct {
+		kind     json.ChangeKind
+		path     string
+		old, new string
+	}{
+		{json.ChangeReplace, "/age", "10", "11"},
+		{json.ChangeReplace, "/tags/1", `"b"`, `"B"`},
+		{json.ChangeRemove, "/tags/2", `"c"`, ""},
+		{json.ChangeReplace, "/owner/x~1y", "true", "false"},
+		{json.ChangeRemove, "/old", "null", ""},
+		{json.ChangeAdd, "/new", "", `{"k": [1, 2]}`},
+	}
+	assertEq(t, "changes", len(expected), len(changes))
+	for i, e := range expected {
+		assertEq(t, "kind", e.kind, changes[i].Kind)
+		assertEq(t, "path", e.path, changes[i].Path)
+		assertEq(t, "old", e.old, string(changes[i].Old))
+		assertEq(t, "new", e.new, string(changes[i].New))
+	}
+	patch, err := changes.Patch()
+	assertErr(t, err)
+	assertEq(t, "patch", `[{"op":"replace","path":"/age","value":11},{"op":"replace","path":"/tags/1","value":"B"},{"op":"remove","path":"/tags/2"},{"op":"replace","path":"/owner/x~1y","value":false},{"op":"remove","path":"/old"},{"op":"add","path":"/new","value":{"k":[1,2]}}]`, string(patch))
+
+	t.Run("array removals", func(t *testing.T) {
+		changes, err := json.Diff([]byte(`[1, 2, 3, 4]`), []byte(`[1]`))
+		assertErr(t, err)
+		patch, err := changes.Patch()
+		assertErr(t, err)
+		assertEq(t, "patch", `[{"op":"remove","path":"/3"},{"op":"remove","path":"/2"},{"op":"remove","path":"/1"}]`, string(patch))
+	})
+	t.Run("root", func(t *testing.T) {
+		changes, err := json.Diff([]byte(`[1]`), []byte(`{"a": 1}`))
+		assertErr(t, err)
+		patch, err := changes.Patch()
+		assertErr(t, err)
+		assertEq(t, "patch", `[{"op":"replace","path":"","value":{"a":1}}]`, string(patch))
+	})
+	t.Run("equal", func(t *testing.T) {
+		changes, err := json.Diff([]byte(` {"a": [1.0, "x"]} `), []byte(`{"a":[1,"x"]}`))
+		assertErr(t, err)
+		assertEq(t, "changes", 0, len(changes))
+	})
+	t.Run("syntax error", func(t *testing.T) {
+		_, err := json.Diff([]byte(`{}`), []byte("{\"a\": [1,\n 2,]}"))
+		var syntaxErr *json.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("expected SyntaxError but got %v", err)
+		}
+		assertEq(t, "line", int64(2), syntaxErr.Line)
+	})
+}
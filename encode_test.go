@@ -9,13 +9,16 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"math/big"
 	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/goccy/go-json"
+	"github.com/goccy/go-json/schema"
 )
 
 type recursiveT struct {
@@ -2414,3 +2417,648 @@ func TestIssue271(t *testing.T) {
 		t.Fatalf("failed to encode. expected %q but got %q", expected, got)
 	}
 }
+
+func TestEncodeBigNumbers(t *testing.T) {
+	i, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	f, _ := new(big.Float).SetPrec(200).SetString("1234567890.1234567890123")
+	type T struct {
+		I  *big.Int   `json:"i"`
+		F  *big.Float `json:"f"`
+		R1 *big.Rat   `json:"r1"`
+		R2 *big.Rat   `json:"r2"`
+		R3 *big.Rat   `json:"r3"`
+		V  big.Int    `json:"v"`
+		N  *big.Float `json:"n"`
+	}
+	v := T{
+		I:  i,
+		F:  f,
+		R1: big.NewRat(-3, 8),
+		R2: big.NewRat(1, 3),
+		R3: big.NewRat(10, 2),
+		V:  *big.NewInt(-7),
+	}
+	expected := `{"i":123456789012345678901234567890,"f":1234567890.1234567890123,"r1":-0.375,"r2":"1/3","r3":5,"v":-7,"n":null}`
+	got, err := json.Marshal(&v)
+	assertErr(t, err)
+	assertEq(t, "struct", expected, string(got))
+
+	got, err = json.Marshal(map[*big.Int]*big.Rat{big.NewInt(10): big.NewRat(1, 4)})
+	assertErr(t, err)
+	assertEq(t, "map", `{"10":0.25}`, string(got))
+
+	got, err = json.MarshalIndent([]*big.Float{big.NewFloat(1.5)}, "", " ")
+	assertErr(t, err)
+	assertEq(t, "indent", "[\n 1.5\n]", string(got))
+
+	got, err = json.Marshal([]*big.Float{big.NewFloat(1e21), big.NewFloat(-2.5e-7), new(big.Float)})
+	assertErr(t, err)
+	assertEq(t, "exponent", `[1e+21,-2.5e-07,0]`, string(got))
+
+	if _, err := json.Marshal(new(big.Float).SetInf(false)); err == nil {
+		t.Fatal("expected error for infinite big.Float")
+	}
+}
+
+type schemaColor int
+
+func (c schemaColor) MarshalText() ([]byte, error) { return []byte("red"), nil }
+
+func (*schemaColor) JSONSchema() []byte { return []byte(`{"enum": ["red", "green"]}`) }
+
+type schemaBase struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type schemaExtra struct {
+	Note string `json:"note"`
+}
+
+type schemaNode struct {
+	schemaBase
+	*schemaExtra
+	Name     string            `json:"name"`
+	Count    int64             `json:"count,string"`
+	Tags     []string          `json:"tags,omitempty"`
+	Parent   *schemaNode       `json:"parent"`
+	Children []schemaNode      `json:"children"`
+	Color    schemaColor       `json:"color"`
+	Created  time.Time         `json:"created"`
+	Scores   map[int]float64   `json:"scores"`
+	Pair     [2]bool           `json:"pair"`
+	Data     []byte            `json:"data"`
+	Any      interface{}       `json:"any"`
+	Skip     string            `json:"-"`
+	Extra    map[string]string `json:"extra,omitempty"`
+}
+
+func TestSchemaFor(t *testing.T) {
+	t.Run("scalar", func(t *testing.T) {
+		b, err := json.SchemaFor(reflect.TypeOf(0))
+		assertErr(t, err)
+		assertEq(t, "int", `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"integer"}`, string(b))
+		b, err = json.SchemaFor(reflect.TypeOf((*string)(nil)))
+		assertErr(t, err)
+		assertEq(t, "*string", `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":["string","null"]}`, string(b))
+	})
+	t.Run("struct", func(t *testing.T) {
+		b, err := json.SchemaFor(reflect.TypeOf(schemaBase{}))
+		assertErr(t, err)
+		assertEq(t, "schema", `{"$schema":"https://json-schema.org/draft/2020-12/schema","$ref":"#/$defs/schemaBase","$defs":{"schemaBase":{"type":"object","properties":{"id":{"type":"integer"},"name":{"type":"string"}},"required":["id","name"],"additionalProperties":false}}}`, string(b))
+	})
+	t.Run("fields", func(t *testing.T) {
+		b, err := json.SchemaFor(reflect.TypeOf(&schemaNode{}))
+		assertErr(t, err)
+		expected := `{"$schema":"https://json-schema.org/draft/2020-12/schema","anyOf":[{"$ref":"#/$defs/schemaNode"},{"type":"null"}],"$defs":{"schemaNode":{"type":"object","properties":{` +
+			`"id":{"type":"integer"},"note":{"type":"string"},"name":{"type":"string"},"count":{"type":"string"},"tags":{"type":["array","null"],"items":{"type":"string"}},` +
+			`"parent":{"anyOf":[{"$ref":"#/$defs/schemaNode"},{"type":"null"}]},"children":{"type":["array","null"],"items":{"$ref":"#/$defs/schemaNode"}},` +
+			`"color":{"enum":["red","green"]},"created":{"type":"string","format":"date-time"},` +
+			`"scores":{"type":["object","null"],"additionalProperties":{"type":"number"},"propertyNames":{"pattern":"^-?[0-9]+$"}},` +
+			`"pair":{"type":"array","items":{"type":"boolean"},"minItems":2,"maxItems":2},"data":{"type":["string","null"],"contentEncoding":"base64"},` +
+			`"any":{},"extra":{"type":["object","null"],"additionalProperties":{"type":"string"}}},` +
+			`"required":["id","name","count","parent","children","color","created","scores","pair","data","any"],"additionalProperties":false}}}`
+		if string(b) != expected {
+			t.Fatalf("unexpected schema:\n%s\nexpected:\n%s", b, expected)
+		}
+		s, err := schema.Compile(b)
+		assertErr(t, err)
+		for _, v := range []*schemaNode{
+			nil,
+			{},
+			{
+				schemaBase:  schemaBase{ID: 1},
+				schemaExtra: &schemaExtra{Note: "n"},
+				Name:        "root",
+				Count:       3,
+				Tags:        []string{"a"},
+				Parent:      &schemaNode{Name: "parent"},
+				Children:    []schemaNode{{Scores: map[int]float64{-1: 0.5}}},
+				Data:        []byte("data"),
+				Any:         []interface{}{1, "a"},
+				Extra:       map[string]string{"a": "b"},
+			},
+		} {
+			data, err := json.Marshal(v)
+			assertErr(t, err)
+			if err := s.Validate(data); err != nil {
+				t.Fatalf("schema does not validate %s: %v", data, err)
+			}
+		}
+		if err := s.Validate([]byte(`{"id":1}`)); err == nil {
+			t.Fatal("expected missing fields to be invalid")
+		}
+	})
+}
+
+func TestBytesFormat(t *testing.T) {
+	type T struct {
+		Std    []byte  `json:"std"`
+		URL    []byte  `json:"url,format:base64url"`
+		Raw    []byte  `json:"raw,omitempty,format:base64raw"`
+		Hex    []byte  `json:"hex,format:hex"`
+		Array  *[]byte `json:"array,format:array"`
+		Base64 []byte  `json:"base64,format:base64"`
+	}
+	data := []byte{0xfb, 0xff, 0x0a}
+	v := T{Std: data, URL: data, Raw: data, Hex: data, Array: &data, Base64: data}
+	expected := `{"std":"+/8K","url":"-_8K","raw":"+/8K","hex":"fbff0a","array":[251,255,10],"base64":"+/8K"}`
+
+	t.Run("encode", func(t *testing.T) {
+		b, err := json.Marshal(v)
+		assertErr(t, err)
+		assertEq(t, "json", expected, string(b))
+	})
+	t.Run("decode", func(t *testing.T) {
+		var got T
+		assertErr(t, json.Unmarshal([]byte(expected), &got))
+		assertEq(t, "value", true, reflect.DeepEqual(v, got))
+
+		got = T{}
+		assertErr(t, json.NewDecoder(strings.NewReader(expected)).Decode(&got))
+		assertEq(t, "stream value", true, reflect.DeepEqual(v, got))
+
+		got = T{}
+		assertErr(t, json.Unmarshal([]byte(`{"url":"-_8K","raw":"+/8K","hex":"FBFF0A","array":"+/8K"}`), &got))
+		assertEq(t, "url", true, bytes.Equal(data, got.URL))
+		assertEq(t, "raw", true, bytes.Equal(data, got.Raw))
+		assertEq(t, "hex", true, bytes.Equal(data, got.Hex))
+		assertEq(t, "array", true, bytes.Equal(data, *got.Array))
+
+		for _, in := range []string{`{"url":"+/8K"}`, `{"hex":"fbf"}`, `{"hex":"zz"}`, `{"std":"-_8K"}`} {
+			if err := json.Unmarshal([]byte(in), &got); err == nil {
+				t.Errorf("%s: expected error", in)
+			}
+		}
+	})
+	t.Run("option", func(t *testing.T) {
+		b, err := json.MarshalWithOption(v, json.EncodeBytesFormat(json.BytesFormatHex))
+		assertErr(t, err)
+		assertEq(t, "json", `{"std":"fbff0a","url":"-_8K","raw":"+/8K","hex":"fbff0a","array":[251,255,10],"base64":"+/8K"}`, string(b))
+
+		var got T
+		assertErr(t, json.UnmarshalWithOption(b, &got, json.DecodeBytesFormat(json.BytesFormatHex)))
+		assertEq(t, "value", true, reflect.DeepEqual(v, got))
+
+		b, err = json.MarshalWithOption(map[string][]byte{"a": {1, 2}, "b": nil}, json.EncodeBytesFormat(json.BytesFormatArray))
+		assertErr(t, err)
+		assertEq(t, "map", `{"a":[1,2],"b":null}`, string(b))
+	})
+	t.Run("schema", func(t *testing.T) {
+		b, err := json.SchemaFor(reflect.TypeOf(T{}))
+		assertErr(t, err)
+		for _, property := range []string{
+			`"url":{"type":["string","null"],"contentEncoding":"base64url"}`,
+			`"hex":{"type":["string","null"],"pattern":"^([0-9a-fA-F]{2})*$"}`,
+			`"array":{"type":["array","null"],"items":{"type":"integer","minimum":0,"maximum":255}}`,
+		} {
+			if !strings.Contains(string(b), property) {
+				t.Errorf("schema %s does not contain %s", b, property)
+			}
+		}
+	})
+	t.Run("indent", func(t *testing.T) {
+		b, err := json.MarshalIndent(struct {
+			A []byte `json:"a,format:array"`
+			B []byte `json:"b,format:array"`
+		}{A: []byte{1, 2}, B: []byte{}}, "", "  ")
+		assertErr(t, err)
+		assertEq(t, "json", "{\n  \"a\": [\n    1,\n    2\n  ],\n  \"b\": []\n}", string(b))
+	})
+	t.Run("color", func(t *testing.T) {
+		scheme := &json.ColorScheme{
+			Int:    json.ColorFormat{Header: "<int>", Footer: "</int>"},
+			Binary: json.ColorFormat{Header: "<binary>", Footer: "</binary>"},
+		}
+		v := struct {
+			A []byte `json:"a,format:array"`
+			B []byte `json:"b"`
+		}{A: []byte{1, 2}, B: []byte{1, 2}}
+		b, err := json.MarshalWithOption(v, json.Colorize(scheme))
+		assertErr(t, err)
+		assertEq(t, "color", `{"a":[<int>1</int>,<int>2</int>],"b":<binary>"AQI="</binary>}`, string(b))
+		b, err = json.MarshalIndentWithOption(v, "", " ", json.Colorize(scheme))
+		assertErr(t, err)
+		assertEq(t, "color indent", "{\n \"a\": [\n  <int>1</int>,\n  <int>2</int>\n ],\n \"b\": <binary>\"AQI=\"</binary>\n}", string(b))
+	})
+	t.Run("unknown format", func(t *testing.T) {
+		type T struct {
+			A []byte `json:"a,format:bogus"`
+		}
+		const expected = `json: unknown format "bogus" in struct tag of field A`
+		if _, err := json.Marshal(T{}); err == nil || err.Error() != expected {
+			t.Fatalf("expected %q but got %v", expected, err)
+		}
+		var v T
+		if err := json.Unmarshal([]byte(`{"a":"AQI="}`), &v); err == nil || err.Error() != expected {
+			t.Fatalf("expected %q but got %v", expected, err)
+		}
+		if _, err := json.SchemaFor(reflect.TypeOf(v)); err == nil {
+			t.Fatal("expected an error for the unknown format")
+		}
+	})
+}
+
+var colorCode = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestNilAsEmpty(t *testing.T) {
+	type T struct {
+		Slice      []int          `json:"slice"`
+		Map        map[string]int `json:"map"`
+		TagSlice   []int          `json:"tagSlice,nilasempty"`
+		TagMap     map[string]int `json:"tagMap,nilasempty"`
+		PtrSlice   *[]int         `json:"ptrSlice,nilasempty"`
+		OmitSlice  []int          `json:"omitSlice,omitempty,nilasempty"`
+		NestedMaps []map[int]bool `json:"nestedMaps"`
+		Bytes      []byte         `json:"bytes"`
+		TagBytes   []byte         `json:"tagBytes,nilasempty"`
+		ArrayBytes []byte         `json:"arrayBytes,nilasempty,format:array"`
+	}
+	v := T{NestedMaps: []map[int]bool{nil}}
+	for _, test := range []struct {
+		name     string
+		opts     []json.EncodeOptionFunc
+		expected string
+	}{
+		{
+			name:     "tag",
+			expected: `{"slice":null,"map":null,"tagSlice":[],"tagMap":{},"ptrSlice":null,"nestedMaps":[null],"bytes":null,"tagBytes":"","arrayBytes":[]}`,
+		},
+		{
+			name:     "slice option",
+			opts:     []json.EncodeOptionFunc{json.EncodeNilSliceAsEmpty()},
+			expected: `{"slice":[],"map":null,"tagSlice":[],"tagMap":{},"ptrSlice":null,"nestedMaps":[null],"bytes":"","tagBytes":"","arrayBytes":[]}`,
+		},
+		{
+			name:     "map option",
+			opts:     []json.EncodeOptionFunc{json.EncodeNilMapAsEmpty()},
+			expected: `{"slice":null,"map":{},"tagSlice":[],"tagMap":{},"ptrSlice":null,"nestedMaps":[{}],"bytes":null,"tagBytes":"","arrayBytes":[]}`,
+		},
+		{
+			name:     "both options",
+			opts:     []json.EncodeOptionFunc{json.EncodeNilSliceAsEmpty(), json.EncodeNilMapAsEmpty()},
+			expected: `{"slice":[],"map":{},"tagSlice":[],"tagMap":{},"ptrSlice":null,"nestedMaps":[{}],"bytes":"","tagBytes":"","arrayBytes":[]}`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := json.MarshalWithOption(v, test.opts...)
+			assertErr(t, err)
+			assertEq(t, "json", test.expected, string(b))
+
+			b, err = json.MarshalIndentWithOption(v, "", "", test.opts...)
+			assertErr(t, err)
+			assertEq(t, "indent", test.expected, strings.NewReplacer("\n", "", " ", "").Replace(string(b)))
+
+			b, err = json.MarshalWithOption(v, append(test.opts, json.Colorize(json.DefaultColorScheme))...)
+			assertErr(t, err)
+			assertEq(t, "color", test.expected, colorCode.ReplaceAllString(string(b), ""))
+
+			b, err = json.MarshalIndentWithOption(v, "", "", append(test.opts, json.Colorize(json.DefaultColorScheme))...)
+			assertErr(t, err)
+			assertEq(t, "color indent", test.expected, strings.NewReplacer("\n", "", " ", "").Replace(colorCode.ReplaceAllString(string(b), "")))
+		})
+	}
+	t.Run("top level", func(t *testing.T) {
+		var s []string
+		b, err := json.MarshalWithOption(s, json.EncodeNilSliceAsEmpty())
+		assertErr(t, err)
+		assertEq(t, "slice", `[]`, string(b))
+
+		var m map[string]string
+		b, err = json.MarshalIndentWithOption(m, "", "  ", json.EncodeNilMapAsEmpty(), json.Colorize(json.DefaultColorScheme))
+		assertErr(t, err)
+		assertEq(t, "map", `{}`, string(b))
+
+		b, err = json.MarshalWithOption(&s, json.EncodeNilSliceAsEmpty())
+		assertErr(t, err)
+		assertEq(t, "pointer", `[]`, string(b))
+	})
+	t.Run("pointers", func(t *testing.T) {
+		var s []int
+		b, err := json.Marshal(T{PtrSlice: &s})
+		assertErr(t, err)
+		assertEq(t, "slice", true, strings.Contains(string(b), `"ptrSlice":[]`))
+
+		type P struct {
+			A int             `json:"a"`
+			M *map[string]int `json:"m"`
+		}
+		var m map[string]int
+		b, err = json.MarshalWithOption([]interface{}{P{}, P{M: &m}, []*map[string]int{nil, &m}}, json.EncodeNilMapAsEmpty())
+		assertErr(t, err)
+		assertEq(t, "map", `[{"a":0,"m":null},{"a":0,"m":{}},[null,{}]]`, string(b))
+	})
+	t.Run("schema", func(t *testing.T) {
+		b, err := json.SchemaFor(reflect.TypeOf(T{}))
+		assertErr(t, err)
+		for _, property := range []string{
+			`"slice":{"type":["array","null"]`,
+			`"tagSlice":{"type":"array"`,
+			`"tagMap":{"type":"object"`,
+			`"ptrSlice":{"type":["array","null"]`,
+		} {
+			if !strings.Contains(string(b), property) {
+				t.Errorf("schema %s does not contain %s", b, property)
+			}
+		}
+	})
+}
+
+type redactToken string
+
+type redactLateToken string
+
+type redactCredentials struct {
+	User     string `json:"user"`
+	Password string `json:"password"`
+}
+
+func TestEncodeRedact(t *testing.T) {
+	json.RegisterSensitiveType(reflect.TypeOf(redactToken("")))
+
+	type Item struct {
+		Secret string `json:"secret,sensitive"`
+		N      int    `json:"n"`
+	}
+	type T struct {
+		Name     string          `json:"name"`
+		Password string          `json:"password,sensitive"`
+		PIN      int             `json:"pin,sensitive"`
+		Token    redactToken     `json:"token"`
+		PtrToken *redactToken    `json:"ptrToken,omitempty"`
+		Items    []Item          `json:"items"`
+		Map      map[string]Item `json:"map"`
+		Any      interface{}     `json:"any"`
+	}
+	token := redactToken("t")
+	v := T{
+		Name:     "a",
+		Password: "p",
+		PIN:      1234,
+		Token:    "t",
+		PtrToken: &token,
+		Items:    []Item{{Secret: "s", N: 1}},
+		Map:      map[string]Item{"k": {Secret: "s", N: 2}},
+		Any:      Item{Secret: "s", N: 3},
+	}
+	path := json.EncodeRedact(func(path string) string { return "<" + path + ">" })
+	for _, test := range []struct {
+		name     string
+		opts     []json.EncodeOptionFunc
+		expected string
+	}{
+		{
+			name:     "no option",
+			expected: `{"name":"a","password":"p","pin":1234,"token":"t","ptrToken":"t","items":[{"secret":"s","n":1}],"map":{"k":{"secret":"s","n":2}},"any":{"secret":"s","n":3}}`,
+		},
+		{
+			name:     "default mask",
+			opts:     []json.EncodeOptionFunc{json.EncodeRedact(nil)},
+			expected: `{"name":"a","password":"***","pin":"***","token":"***","ptrToken":"***","items":[{"secret":"***","n":1}],"map":{"k":{"secret":"***","n":2}},"any":{"secret":"***","n":3}}`,
+		},
+		{
+			name:     "path",
+			opts:     []json.EncodeOptionFunc{path, json.DisableHTMLEscape()},
+			expected: `{"name":"a","password":"<$.password>","pin":"<$.pin>","token":"<$.token>","ptrToken":"<$.ptrToken>","items":[{"secret":"<$.items[*].secret>","n":1}],"map":{"k":{"secret":"<$.map.*.secret>","n":2}},"any":{"secret":"<$.any.secret>","n":3}}`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := json.MarshalWithOption(v, test.opts...)
+			assertErr(t, err)
+			assertEq(t, "json", test.expected, string(b))
+
+			b, err = json.MarshalWithOption(&v, test.opts...)
+			assertErr(t, err)
+			assertEq(t, "pointer", test.expected, string(b))
+
+			b, err = json.MarshalIndentWithOption(v, "", "", test.opts...)
+			assertErr(t, err)
+			assertEq(t, "indent", test.expected, strings.NewReplacer("\n", "", ": ", ":").Replace(string(b)))
+
+			b, err = json.MarshalWithOption(v, append(test.opts, json.Colorize(json.DefaultColorScheme))...)
+			assertErr(t, err)
+			assertEq(t, "color", test.expected, colorCode.ReplaceAllString(string(b), ""))
+
+			b, err = json.MarshalIndentWithOption(v, "", "", append(test.opts, json.Colorize(json.DefaultColorScheme))...)
+			assertErr(t, err)
+			assertEq(t, "color indent", test.expected, strings.NewReplacer("\n", "", ": ", ":").Replace(colorCode.ReplaceAllString(string(b), "")))
+		})
+	}
+	t.Run("empty", func(t *testing.T) {
+		b, err := json.MarshalWithOption(T{}, json.EncodeRedact(nil))
+		assertErr(t, err)
+		assertEq(t, "json", `{"name":"","password":"***","pin":"***","token":"***","items":null,"map":null,"any":null}`, string(b))
+	})
+	t.Run("embedded", func(t *testing.T) {
+		type E struct {
+			redactCredentials `json:",sensitive"`
+			ID                int `json:"id"`
+		}
+		b, err := json.MarshalWithOption(E{redactCredentials{User: "u", Password: "p"}, 1}, json.EncodeRedact(nil))
+		assertErr(t, err)
+		assertEq(t, "json", `{"user":"***","password":"***","id":1}`, string(b))
+	})
+	t.Run("recursive", func(t *testing.T) {
+		type Node struct {
+			Secret   string `json:"secret,sensitive"`
+			Children []Node `json:"children"`
+		}
+		b, err := json.MarshalWithOption(Node{Secret: "a", Children: []Node{{Secret: "b"}}}, path, json.DisableHTMLEscape())
+		assertErr(t, err)
+		assertEq(t, "json", `{"secret":"<$.secret>","children":[{"secret":"<$.secret>","children":null}]}`, string(b))
+	})
+	t.Run("registered type values", func(t *testing.T) {
+		for _, test := range []struct {
+			name     string
+			v        interface{}
+			expected string
+		}{
+			{"top-level", redactToken("top"), `"<$>"`},
+			{"pointer", &token, `"<$>"`},
+			{"slice", []redactToken{"x", "y"}, `["<$[*]>","<$[*]>"]`},
+			{"array", [1]*redactToken{&token}, `["<$[*]>"]`},
+			{"map", map[string]redactToken{"k": "y"}, `{"k":"<$.*>"}`},
+			{"field slice", struct {
+				Tokens []redactToken `json:"tokens"`
+			}{[]redactToken{"x"}}, `{"tokens":["<$.tokens[*]>"]}`},
+			{"field map", struct {
+				Tokens map[string]redactToken `json:"tokens"`
+			}{map[string]redactToken{"k": "y"}}, `{"tokens":{"k":"<$.tokens.*>"}}`},
+			{"interface", []interface{}{redactToken("x"), 1}, `["<$[*]>",1]`},
+		} {
+			t.Run(test.name, func(t *testing.T) {
+				b, err := json.MarshalWithOption(test.v, path, json.DisableHTMLEscape())
+				assertErr(t, err)
+				assertEq(t, "json", test.expected, string(b))
+
+				b, err = json.MarshalIndentWithOption(test.v, "", "", path, json.DisableHTMLEscape())
+				assertErr(t, err)
+				assertEq(t, "indent", test.expected, strings.NewReplacer("\n", "", ": ", ":").Replace(string(b)))
+			})
+		}
+	})
+	t.Run("interface path", func(t *testing.T) {
+		type Cred struct {
+			Pass string `json:"pass,sensitive"`
+		}
+		type Outer struct {
+			I interface{} `json:"i"`
+		}
+		v := Outer{I: Outer{I: []interface{}{Cred{Pass: "p"}}}}
+		b, err := json.MarshalWithOption(v, path, json.DisableHTMLEscape())
+		assertErr(t, err)
+		assertEq(t, "json", `{"i":{"i":[{"pass":"<$.i.i[*].pass>"}]}}`, string(b))
+
+		b, err = json.MarshalWithOption(map[string]interface{}{"a": v, "b": Outer{I: redactToken("x")}}, path, json.DisableHTMLEscape())
+		assertErr(t, err)
+		assertEq(t, "json", `{"a":{"i":{"i":[{"pass":"<$.*.i.i[*].pass>"}]}},"b":{"i":"<$.*.i>"}}`, string(b))
+	})
+	t.Run("registered after first use", func(t *testing.T) {
+		type T struct {
+			Token  redactLateToken   `json:"token"`
+			Tokens []redactLateToken `json:"tokens"`
+		}
+		v := T{Token: "a", Tokens: []redactLateToken{"b"}}
+		b, err := json.MarshalWithOption(v, json.EncodeRedact(nil))
+		assertErr(t, err)
+		assertEq(t, "before", `{"token":"a","tokens":["b"]}`, string(b))
+
+		json.RegisterSensitiveType(reflect.TypeOf(redactLateToken("")))
+		for _, test := range []struct {
+			name string
+			v    interface{}
+			want string
+		}{
+			{name: "value", v: v, want: `{"token":"***","tokens":["***"]}`},
+			{name: "pointer", v: &v, want: `{"token":"***","tokens":["***"]}`},
+			{name: "top-level", v: redactLateToken("c"), want: `"***"`},
+		} {
+			b, err := json.MarshalWithOption(test.v, json.EncodeRedact(nil))
+			assertErr(t, err)
+			assertEq(t, test.name, test.want, string(b))
+		}
+	})
+}
+
+func TestTagKey(t *testing.T) {
+	type Inner struct {
+		Value int `api:"value" json:"v"`
+	}
+	type T struct {
+		ID       int         `api:"id" json:"identifier"`
+		Name     string      `json:"name"`
+		Internal string      `api:"-" json:"internal"`
+		Inner    Inner       `api:"inner"`
+		Any      interface{} `api:"any,omitempty"`
+	}
+	v := T{ID: 1, Name: "a", Internal: "b", Inner: Inner{Value: 2}, Any: &Inner{Value: 3}}
+	t.Run("encode", func(t *testing.T) {
+		b, err := json.Marshal(v)
+		assertErr(t, err)
+		assertEq(t, "json", `{"identifier":1,"name":"a","internal":"b","Inner":{"v":2},"Any":{"v":3}}`, string(b))
+
+		b, err = json.MarshalWithOption(v, json.EncodeTagKey("api"))
+		assertErr(t, err)
+		assertEq(t, "api", `{"id":1,"name":"a","inner":{"value":2},"any":{"value":3}}`, string(b))
+
+		b, err = json.Marshal(v)
+		assertErr(t, err)
+		assertEq(t, "json after api", `{"identifier":1,"name":"a","internal":"b","Inner":{"v":2},"Any":{"v":3}}`, string(b))
+	})
+	t.Run("decode", func(t *testing.T) {
+		src := `{"id":1,"identifier":2,"name":"a","internal":"b","inner":{"value":3,"v":4},"any":{"value":5}}`
+		var got T
+		got.Any = &Inner{}
+		assertErr(t, json.UnmarshalWithOption([]byte(src), &got, json.DecodeTagKey("api")))
+		assertEq(t, "api", true, reflect.DeepEqual(T{ID: 1, Name: "a", Inner: Inner{Value: 3}, Any: &Inner{Value: 5}}, got))
+
+		got = T{Any: &Inner{}}
+		assertErr(t, json.NewDecoder(strings.NewReader(src)).DecodeWithOption(&got, json.DecodeTagKey("api")))
+		assertEq(t, "stream", true, reflect.DeepEqual(T{ID: 1, Name: "a", Inner: Inner{Value: 3}, Any: &Inner{Value: 5}}, got))
+
+		got = T{}
+		assertErr(t, json.Unmarshal([]byte(src), &got))
+		assertEq(t, "json", true, reflect.DeepEqual(T{ID: 2, Name: "a", Internal: "b", Inner: Inner{Value: 4}, Any: map[string]interface{}{"value": float64(5)}}, got))
+	})
+}
+
+type registeredTagsT struct {
+	ID       int
+	Name     string `json:"name"`
+	Password string `api:"password"`
+}
+
+func TestRegisterStructTags(t *testing.T) {
+	json.RegisterStructTags(reflect.TypeOf(registeredTagsT{}), map[string]string{
+		"ID":       "id,string",
+		"Password": "-",
+	})
+	v := registeredTagsT{ID: 1, Name: "a", Password: "b"}
+	b, err := json.Marshal(v)
+	assertErr(t, err)
+	assertEq(t, "encode", `{"id":"1","name":"a"}`, string(b))
+
+	b, err = json.MarshalWithOption([]registeredTagsT{v}, json.EncodeTagKey("api"))
+	assertErr(t, err)
+	assertEq(t, "encode api", `[{"id":"1","name":"a"}]`, string(b))
+
+	var got registeredTagsT
+	assertErr(t, json.Unmarshal([]byte(`{"id":"2","name":"c","Password":"d"}`), &got))
+	assertEq(t, "decode", true, reflect.DeepEqual(registeredTagsT{ID: 2, Name: "c"}, got))
+
+	got = registeredTagsT{}
+	assertErr(t, json.UnmarshalWithOption([]byte(`{"id":"2","password":"d"}`), &got, json.DecodeTagKey("api")))
+	assertEq(t, "decode api", true, reflect.DeepEqual(registeredTagsT{ID: 2}, got))
+
+	t.Run("after first use", func(t *testing.T) {
+		type T struct {
+			A int
+			B string
+		}
+		type Outer struct {
+			T T
+		}
+		b, err := json.Marshal(Outer{T: T{A: 1, B: "x"}})
+		assertErr(t, err)
+		assertEq(t, "before", `{"T":{"A":1,"B":"x"}}`, string(b))
+		var got T
+		assertErr(t, json.Unmarshal([]byte(`{"A":1}`), &got))
+
+		json.RegisterStructTags(reflect.TypeOf(T{}), map[string]string{"A": "a_renamed"})
+		v := T{A: 1, B: "x"}
+		for _, test := range []struct {
+			name string
+			v    interface{}
+			want string
+		}{
+			{name: "value", v: v, want: `{"a_renamed":1,"B":"x"}`},
+			{name: "pointer", v: &v, want: `{"a_renamed":1,"B":"x"}`},
+			{name: "containing", v: Outer{T: v}, want: `{"T":{"a_renamed":1,"B":"x"}}`},
+		} {
+			b, err := json.Marshal(test.v)
+			assertErr(t, err)
+			assertEq(t, test.name, test.want, string(b))
+		}
+		got = T{}
+		assertErr(t, json.Unmarshal([]byte(`{"a_renamed":2,"A":3}`), &got))
+		assertEq(t, "decode", true, reflect.DeepEqual(T{A: 2}, got))
+	})
+
+	for _, test := range []struct {
+		name string
+		typ  reflect.Type
+		tags map[string]string
+	}{
+		{name: "non-struct", typ: reflect.TypeOf(1)},
+		{name: "unknown field", typ: reflect.TypeOf(registeredTagsT{}), tags: map[string]string{"Unknown": "x"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expected panic")
+				}
+			}()
+			json.RegisterStructTags(test.typ, test.tags)
+		})
+	}
+}
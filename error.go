@@ -20,6 +20,7 @@ type InvalidUnmarshalError = errors.InvalidUnmarshalError
 type MarshalerError = errors.MarshalerError
 
 // A SyntaxError is a description of a JSON syntax error.
+// Its embedded ErrorLocation reports the line, column and JSON path of the error.
 type SyntaxError = errors.SyntaxError
 
 // An UnmarshalFieldError describes a JSON object key that
@@ -32,8 +33,36 @@ type UnmarshalFieldError = errors.UnmarshalFieldError
 // not appropriate for a value of a specific Go type.
 type UnmarshalTypeError = errors.UnmarshalTypeError
 
+// ErrorLocation is embedded in decode errors and describes where in the input
+// the error occurred: line, column, JSON path of the failing value and
+// a Snippet of the offending line.
+type ErrorLocation = errors.Location
+
 // An UnsupportedTypeError is returned by Marshal when attempting
 // to encode an unsupported value type.
 type UnsupportedTypeError = errors.UnsupportedTypeError
 
 type UnsupportedValueError = errors.UnsupportedValueError
+
+// A DepthLimitError is returned when the nesting depth of the input exceeds DecodeLimits.MaxDepth.
+type DepthLimitError = errors.DepthLimitError
+
+// A StringLimitError is returned when a string value is longer than DecodeLimits.MaxStringLen.
+type StringLimitError = errors.StringLimitError
+
+// An ArrayLimitError is returned when an array has more elements than DecodeLimits.MaxArrayLen.
+type ArrayLimitError = errors.ArrayLimitError
+
+// An ObjectKeysLimitError is returned when an object has more keys than DecodeLimits.MaxObjectKeys.
+type ObjectKeysLimitError = errors.ObjectKeysLimitError
+
+// A BytesLimitError is returned when the input is larger than DecodeLimits.MaxBytes.
+type BytesLimitError = errors.BytesLimitError
+
+// A DuplicateKeyError is returned when an object contains the same key more than once
+// and DecodeRejectDuplicateKeys is used.
+type DuplicateKeyError = errors.DuplicateKeyError
+
+// DecodeErrors lists the type errors of the values skipped while decoding with DecodeCollectErrors.
+// Each error reports the JSON path of the skipped value and the expected Go type.
+type DecodeErrors = errors.DecodeErrors
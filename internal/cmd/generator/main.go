@@ -75,9 +75,9 @@ func (t OpType) CodeType() CodeType {
     return CodeArrayHead
   case OpArrayElem:
     return CodeArrayElem
-  case OpSlice, OpSlicePtr:
+  case OpSlice, OpSlicePtr, OpOrderedMap, OpOrderedMapPtr:
     return CodeSliceHead
-  case OpSliceElem:
+  case OpSliceElem, OpOrderedMapElem:
     return CodeSliceElem
   case OpMap, OpMapPtr:
     return CodeMapHead
@@ -201,6 +201,15 @@ func (t OpType) FieldToOmitEmptyField() OpType {
 		createOpType("RecursivePtr", "Op"),
 		createOpType("RecursiveEnd", "Op"),
 		createOpType("InterfaceEnd", "Op"),
+		createOpType("Value", "Op"),
+		createOpType("ValuePtr", "Op"),
+		createOpType("Optional", "Op"),
+		createOpType("OptionalPtr", "Op"),
+		createOpType("OrderedMap", "Op"),
+		createOpType("OrderedMapPtr", "Op"),
+		createOpType("OrderedMapElem", "Op"),
+		createOpType("OrderedMapEnd", "Op"),
+		createOpType("Redact", "Op"),
 	}
 	for _, typ := range primitiveTypesUpper {
 		typ := typ
@@ -35,6 +35,9 @@ func (d *arrayDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) er
 	if depth > maxDecodeNestingDepth {
 		return errors.ErrExceededMaxDepth(s.char(), s.cursor)
 	}
+	if err := s.Option.checkDepth(depth, s.totalOffset()); err != nil {
+		return err
+	}
 
 	for {
 		switch s.char() {
@@ -46,6 +49,7 @@ func (d *arrayDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) er
 			return nil
 		case '[':
 			idx := 0
+			start := s.cursor
 			s.cursor++
 			if s.skipWhiteSpace() == ']' {
 				for idx < d.alen {
@@ -55,16 +59,33 @@ func (d *arrayDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) er
 				s.cursor++
 				return nil
 			}
+			validation := s.Option.validation
+			if validation != nil {
+				validation.BeginArray(start)
+			}
 			for {
+				valueStart := s.cursor
+				if validation != nil {
+					s.validateString(validation, depth)
+				}
 				if idx < d.alen {
+					errNum, offset := len(s.Option.Errors), s.totalOffset()
 					if err := d.valueDecoder.DecodeStream(s, depth, unsafe.Pointer(uintptr(p)+uintptr(idx)*d.size)); err != nil {
-						return err
+						if err := s.collectError(err, offset, depth); err != nil {
+							return errors.PrependPathIndex(err, idx)
+						}
+					}
+					if len(s.Option.Errors) > errNum {
+						s.Option.prependPathIndex(errNum, idx)
 					}
 				} else {
-					if err := s.skipValue(depth); err != nil {
+					if err := s.skipUnusedValue(depth); err != nil {
 						return err
 					}
 				}
+				if validation != nil {
+					validation.Value(s.buf, valueStart, s.cursor)
+				}
 				idx++
 				switch s.skipWhiteSpace() {
 				case ']':
@@ -73,8 +94,14 @@ func (d *arrayDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) er
 						idx++
 					}
 					s.cursor++
+					if validation != nil {
+						validation.End()
+					}
 					return nil
 				case ',':
+					if err := s.Option.checkArrayLen(idx+1, s.totalOffset()); err != nil {
+						return err
+					}
 					s.cursor++
 					continue
 				case nul:
@@ -107,6 +134,9 @@ func (d *arrayDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe
 	if depth > maxDecodeNestingDepth {
 		return 0, errors.ErrExceededMaxDepth(buf[cursor], cursor)
 	}
+	if err := ctx.Option.checkDepth(depth, cursor); err != nil {
+		return 0, err
+	}
 
 	for {
 		switch buf[cursor] {
@@ -121,6 +151,7 @@ func (d *arrayDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe
 			return cursor, nil
 		case '[':
 			idx := 0
+			start := cursor
 			cursor++
 			cursor = skipWhiteSpace(buf, cursor)
 			if buf[cursor] == ']' {
@@ -131,20 +162,38 @@ func (d *arrayDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe
 				cursor++
 				return cursor, nil
 			}
+			validation := ctx.Option.validation
+			if validation != nil {
+				validation.BeginArray(start)
+			}
 			for {
+				valueStart := cursor
+				if validation != nil {
+					validateString(validation, buf, cursor, depth)
+				}
 				if idx < d.alen {
+					errNum := len(ctx.Option.Errors)
 					c, err := d.valueDecoder.Decode(ctx, cursor, depth, unsafe.Pointer(uintptr(p)+uintptr(idx)*d.size))
 					if err != nil {
-						return 0, err
+						c, err = collectError(ctx, err, cursor, depth)
+						if err != nil {
+							return 0, errors.PrependPathIndex(err, idx)
+						}
+					}
+					if len(ctx.Option.Errors) > errNum {
+						ctx.Option.prependPathIndex(errNum, idx)
 					}
 					cursor = c
 				} else {
-					c, err := skipValue(buf, cursor, depth)
+					c, err := skipUnusedValue(ctx, cursor, depth)
 					if err != nil {
 						return 0, err
 					}
 					cursor = c
 				}
+				if validation != nil {
+					validation.Value(buf, valueStart, cursor)
+				}
 				idx++
 				cursor = skipWhiteSpace(buf, cursor)
 				switch buf[cursor] {
@@ -154,8 +203,14 @@ func (d *arrayDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe
 						idx++
 					}
 					cursor++
+					if validation != nil {
+						validation.End()
+					}
 					return cursor, nil
 				case ',':
+					if err := ctx.Option.checkArrayLen(idx+1, cursor); err != nil {
+						return 0, err
+					}
 					cursor++
 					continue
 				default:
@@ -0,0 +1,164 @@
+package decoder
+
+import (
+	"math/big"
+	"reflect"
+	"unsafe"
+
+	"github.com/goccy/go-json/internal/errors"
+	"github.com/goccy/go-json/internal/runtime"
+)
+
+var (
+	bigIntType   = runtime.Type2RType(reflect.TypeOf(big.Int{}))
+	bigFloatType = runtime.Type2RType(reflect.TypeOf(big.Float{}))
+	bigRatType   = runtime.Type2RType(reflect.TypeOf(big.Rat{}))
+)
+
+func isBigNumberType(typ *runtime.Type) bool {
+	return typ == bigIntType || typ == bigFloatType || typ == bigRatType
+}
+
+// bigNumberDecoder decodes JSON numbers into big.Int, big.Float and big.Rat without going through float64.
+// big.Float and big.Rat also accept the string form produced by their MarshalText methods.
+type bigNumberDecoder struct {
+	typ           *runtime.Type
+	stringDecoder *stringDecoder
+	structName    string
+	fieldName     string
+}
+
+func newBigNumberDecoder(typ *runtime.Type, structName, fieldName string) *bigNumberDecoder {
+	return &bigNumberDecoder{
+		typ:           typ,
+		stringDecoder: newStringDecoder(structName, fieldName),
+		structName:    structName,
+		fieldName:     fieldName,
+	}
+}
+
+func (d *bigNumberDecoder) errUnmarshalType(value string, offset int64) *errors.UnmarshalTypeError {
+	return &errors.UnmarshalTypeError{
+		Value:  value,
+		Type:   runtime.RType2Type(d.typ),
+		Offset: offset,
+		Struct: d.structName,
+		Field:  d.fieldName,
+	}
+}
+
+// set assigns the number literal or string value b to the big number at p.
+func (d *bigNumberDecoder) set(b []byte, isString bool, offset int64, p unsafe.Pointer) error {
+	s := *(*string)(unsafe.Pointer(&b))
+	switch d.typ {
+	case bigIntType:
+		if isString || !isNumberLiteral(b, integerLiteral) {
+			return d.errUnmarshalType("number "+s, offset)
+		}
+		if _, ok := (*big.Int)(p).SetString(s, 10); !ok {
+			return d.errUnmarshalType("number "+s, offset)
+		}
+	case bigFloatType:
+		if !isString && !isNumberLiteral(b, floatLiteral) {
+			return d.errUnmarshalType("number "+s, offset)
+		}
+		f := (*big.Float)(p)
+		if f.Prec() == 0 {
+			// keep every digit of the literal: each decimal digit needs less than 4 bits.
+			prec := uint(len(b)) * 4
+			if prec < 64 {
+				prec = 64
+			}
+			f.SetPrec(prec)
+		}
+		if _, ok := f.SetString(s); !ok {
+			return d.errUnmarshalType("string "+s, offset)
+		}
+	case bigRatType:
+		if !isString && !isNumberLiteral(b, floatLiteral) {
+			return d.errUnmarshalType("number "+s, offset)
+		}
+		if _, ok := (*big.Rat)(p).SetString(s); !ok {
+			return d.errUnmarshalType("string "+s, offset)
+		}
+	}
+	return nil
+}
+
+func (d *bigNumberDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) error {
+	for {
+		switch s.char() {
+		case ' ', '\n', '\t', '\r':
+			s.cursor++
+			continue
+		case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			offset := s.totalOffset()
+			return d.set(floatBytes(s), false, offset, p)
+		case '"':
+			if d.typ == bigIntType {
+				return d.errUnmarshalType("string", s.totalOffset())
+			}
+			offset := s.totalOffset()
+			b, err := stringBytes(s)
+			if err != nil {
+				return err
+			}
+			return d.set(b, true, offset, p)
+		case 'n':
+			return nullBytes(s)
+		case '[':
+			return d.errUnmarshalType("array", s.totalOffset())
+		case '{':
+			return d.errUnmarshalType("object", s.totalOffset())
+		case 't', 'f':
+			return d.errUnmarshalType("bool", s.totalOffset())
+		case nul:
+			if s.read() {
+				continue
+			}
+		}
+		break
+	}
+	return errors.ErrInvalidBeginningOfValue(s.char(), s.totalOffset())
+}
+
+func (d *bigNumberDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.Pointer) (int64, error) {
+	buf := ctx.Buf
+	cursor = skipWhiteSpace(buf, cursor)
+	switch buf[cursor] {
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		start := cursor
+		cursor++
+		for floatTable[buf[cursor]] {
+			cursor++
+		}
+		if err := d.set(buf[start:cursor], false, start, p); err != nil {
+			return 0, err
+		}
+		return cursor, nil
+	case '"':
+		if d.typ == bigIntType {
+			return 0, d.errUnmarshalType("string", cursor)
+		}
+		b, c, err := d.stringDecoder.decodeByte(buf, cursor)
+		if err != nil {
+			return 0, err
+		}
+		if err := d.set(b, true, cursor, p); err != nil {
+			return 0, err
+		}
+		return c, nil
+	case 'n':
+		if err := validateNull(buf, cursor); err != nil {
+			return 0, err
+		}
+		return cursor + 4, nil
+	case '[':
+		return 0, d.errUnmarshalType("array", cursor)
+	case '{':
+		return 0, d.errUnmarshalType("object", cursor)
+	case 't', 'f':
+		return 0, d.errUnmarshalType("bool", cursor)
+	}
+	return 0, errors.ErrInvalidBeginningOfValue(buf[cursor], cursor)
+}
@@ -4,15 +4,27 @@ import (
 	"unsafe"
 
 	"github.com/goccy/go-json/internal/errors"
+	"github.com/goccy/go-json/internal/runtime"
 )
 
 type boolDecoder struct {
+	typ        *runtime.Type
 	structName string
 	fieldName  string
 }
 
-func newBoolDecoder(structName, fieldName string) *boolDecoder {
-	return &boolDecoder{structName: structName, fieldName: fieldName}
+func newBoolDecoder(typ *runtime.Type, structName, fieldName string) *boolDecoder {
+	return &boolDecoder{typ: typ, structName: structName, fieldName: fieldName}
+}
+
+func (d *boolDecoder) typeError(typeName string, offset int64) *errors.UnmarshalTypeError {
+	return &errors.UnmarshalTypeError{
+		Value:  typeName,
+		Type:   runtime.RType2Type(d.typ),
+		Struct: d.structName,
+		Field:  d.fieldName,
+		Offset: offset,
+	}
 }
 
 func (d *boolDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) error {
@@ -45,10 +57,34 @@ func (d *boolDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) err
 		}
 		break
 	}
+	if (s.Option.Flags&LenientOption) != 0 && d.decodeLenientStream(s, p) {
+		return nil
+	}
+	if name := typeName(s.char()); name != "" {
+		return d.typeError(name, s.totalOffset())
+	}
 ERROR:
 	return errors.ErrUnexpectedEndOfJSON("bool", s.totalOffset())
 }
 
+// decodeLenientStream accepts 0, 1 and "" according to the lenient rules.
+func (d *boolDecoder) decodeLenientStream(s *Stream, p unsafe.Pointer) bool {
+	c := s.char()
+	if (c == '0' || c == '1') && s.Option.lenient(LenientNumberToBool) {
+		s.cursor++
+		if s.char() == nul {
+			s.read()
+		}
+		if !floatTable[s.char()] {
+			**(**bool)(unsafe.Pointer(&p)) = c == '1'
+			return true
+		}
+		s.cursor--
+		return false
+	}
+	return s.Option.lenient(LenientEmptyStringToNull) && emptyStringStream(s)
+}
+
 func (d *boolDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.Pointer) (int64, error) {
 	buf := ctx.Buf
 	cursor = skipWhiteSpace(buf, cursor)
@@ -73,6 +109,21 @@ func (d *boolDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.
 		}
 		cursor += 4
 		return cursor, nil
+	case '0', '1':
+		if ctx.Option.lenient(LenientNumberToBool) && !floatTable[buf[cursor+1]] {
+			**(**bool)(unsafe.Pointer(&p)) = buf[cursor] == '1'
+			cursor++
+			return cursor, nil
+		}
+	case '"':
+		if ctx.Option.lenient(LenientEmptyStringToNull) {
+			if c, ok := emptyStringByte(buf, cursor); ok {
+				return c, nil
+			}
+		}
+	}
+	if name := typeName(buf[cursor]); name != "" {
+		return 0, d.typeError(name, cursor)
 	}
 	return 0, errors.ErrUnexpectedEndOfJSON("bool", cursor)
 }
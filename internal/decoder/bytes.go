@@ -1,7 +1,9 @@
 package decoder
 
 import (
+	"bytes"
 	"encoding/base64"
+	"encoding/hex"
 	"unsafe"
 
 	"github.com/goccy/go-json/internal/errors"
@@ -12,6 +14,7 @@ type bytesDecoder struct {
 	typ           *runtime.Type
 	sliceDecoder  Decoder
 	stringDecoder *stringDecoder
+	format        runtime.BytesFormat // format set by the field tag
 	structName    string
 	fieldName     string
 }
@@ -48,13 +51,14 @@ func (d *bytesDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) er
 		s.reset()
 		return nil
 	}
-	decodedLen := base64.StdEncoding.DecodedLen(len(bytes))
-	buf := make([]byte, decodedLen)
-	n, err := base64.StdEncoding.Decode(buf, bytes)
+	if err := s.Option.checkStringLen(len(bytes), s.totalOffset()); err != nil {
+		return err
+	}
+	buf, err := d.decodeString(s.Option, bytes)
 	if err != nil {
 		return err
 	}
-	*(*[]byte)(p) = buf[:n]
+	*(*[]byte)(p) = buf
 	s.reset()
 	return nil
 }
@@ -67,17 +71,64 @@ func (d *bytesDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe
 	if bytes == nil {
 		return c, nil
 	}
+	if err := ctx.Option.checkStringLen(len(bytes), cursor); err != nil {
+		return 0, err
+	}
 	cursor = c
-	decodedLen := base64.StdEncoding.DecodedLen(len(bytes))
-	b := make([]byte, decodedLen)
-	n, err := base64.StdEncoding.Decode(b, bytes)
+	b, err := d.decodeString(ctx.Option, bytes)
 	if err != nil {
 		return 0, err
 	}
-	*(*[]byte)(p) = b[:n]
+	*(*[]byte)(p) = b
 	return cursor, nil
 }
 
+// decodeString decodes the contents of a JSON string in the format set by the field tag, or else by opt.
+func (d *bytesDecoder) decodeString(opt *Option, src []byte) ([]byte, error) {
+	format := d.format
+	if format == runtime.BytesFormatDefault && opt.Flags&BytesFormatOption != 0 {
+		format = opt.BytesFormat
+	}
+	switch format {
+	case runtime.BytesFormatBase64URL:
+		return decodeBase64(base64.RawURLEncoding, bytes.TrimRight(src, "="))
+	case runtime.BytesFormatBase64Raw:
+		return decodeBase64(base64.RawStdEncoding, bytes.TrimRight(src, "="))
+	case runtime.BytesFormatHex:
+		b := make([]byte, hex.DecodedLen(len(src)))
+		n, err := hex.Decode(b, src)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+	return decodeBase64(base64.StdEncoding, src)
+}
+
+func decodeBase64(enc *base64.Encoding, src []byte) ([]byte, error) {
+	b := make([]byte, enc.DecodedLen(len(src)))
+	n, err := enc.Decode(b, src)
+	if err != nil {
+		return nil, err
+	}
+	return b[:n], nil
+}
+
+// withBytesFormat returns dec decoding []byte values in format, if it is the decoder of a []byte field.
+func withBytesFormat(dec Decoder, format runtime.BytesFormat) Decoder {
+	switch d := dec.(type) {
+	case *bytesDecoder:
+		copied := *d
+		copied.format = format
+		return &copied
+	case *ptrDecoder:
+		copied := *d
+		copied.dec = withBytesFormat(d.dec, format)
+		return &copied
+	}
+	return dec
+}
+
 func (d *bytesDecoder) decodeStreamBinary(s *Stream, depth int64, p unsafe.Pointer) ([]byte, error) {
 	c := s.skipWhiteSpace()
 	if c == '[' {
@@ -0,0 +1,65 @@
+package decoder
+
+import (
+	"github.com/goccy/go-json/internal/errors"
+)
+
+// collectError records err and skips the value starting at cursor
+// if err is a type error and CollectErrorsOption is set.
+// Otherwise, err is returned as it is.
+func collectError(ctx *RuntimeContext, err error, cursor, depth int64) (int64, error) {
+	typeErr, ok := err.(*errors.UnmarshalTypeError)
+	if !ok || (ctx.Option.Flags&CollectErrorsOption) == 0 {
+		return 0, err
+	}
+	ctx.Option.Errors = append(ctx.Option.Errors, typeErr)
+	return skipValue(ctx.Buf, cursor, depth)
+}
+
+// collectError records err and skips the value starting at offset
+// if err is a type error and CollectErrorsOption is set.
+// Otherwise, err is returned as it is.
+func (s *Stream) collectError(err error, offset, depth int64) error {
+	typeErr, ok := err.(*errors.UnmarshalTypeError)
+	if !ok || (s.Option.Flags&CollectErrorsOption) == 0 || offset < s.offset {
+		return err
+	}
+	// the input before the error may be discarded before decoding finishes,
+	// so the position is resolved now.
+	errors.LocatePosition(typeErr, s.buf[:s.length], s.offset, s.lines+1, s.lineStart)
+	s.Option.Errors = append(s.Option.Errors, typeErr)
+	s.cursor = offset - s.offset
+	return s.skipValue(depth)
+}
+
+// typeName returns the JSON type of the value starting with c as it is named in type errors,
+// or "" if c does not start a value.
+func typeName(c byte) string {
+	switch c {
+	case '"':
+		return "string"
+	case '[':
+		return "array"
+	case '{':
+		return "object"
+	case 't', 'f':
+		return "bool"
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return "number"
+	}
+	return ""
+}
+
+// prependPathKey adds key to the path of the errors collected since the errNum-th error.
+func (o *Option) prependPathKey(errNum int, key string) {
+	for _, err := range o.Errors[errNum:] {
+		errors.PrependPathKey(err, key)
+	}
+}
+
+// prependPathIndex adds idx to the path of the errors collected since the errNum-th error.
+func (o *Option) prependPathIndex(errNum int, idx int) {
+	for _, err := range o.Errors[errNum:] {
+		errors.PrependPathIndex(err, idx)
+	}
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"unicode"
 	"unsafe"
 
+	"github.com/goccy/go-json/internal/errors"
 	"github.com/goccy/go-json/internal/runtime"
 )
 
@@ -17,6 +19,11 @@ var (
 	typeAddr         *runtime.TypeAddr
 	cachedDecoderMap unsafe.Pointer // map[uintptr]decoder
 	cachedDecoder    []Decoder
+
+	// tagKeyDecoders caches the decoders compiled with TagKeyOption for each tag key,
+	// apart from the decoders of the json tags.
+	tagKeyDecoders   = map[string]map[uintptr]Decoder{}
+	tagKeyDecodersMu sync.RWMutex
 )
 
 func init() {
@@ -27,6 +34,15 @@ func init() {
 	cachedDecoder = make([]Decoder, typeAddr.AddrRange>>typeAddr.AddrShift)
 }
 
+// ClearCache discards the compiled decoders, so that the types are compiled again on their next use.
+func ClearCache() {
+	clearCachedDecoder()
+	atomic.StorePointer(&cachedDecoderMap, nil)
+	tagKeyDecodersMu.Lock()
+	tagKeyDecoders = map[string]map[uintptr]Decoder{}
+	tagKeyDecodersMu.Unlock()
+}
+
 func loadDecoderMap() map[uintptr]Decoder {
 	p := atomic.LoadPointer(&cachedDecoderMap)
 	return *(*map[uintptr]Decoder)(unsafe.Pointer(&p))
@@ -49,7 +65,7 @@ func compileToGetDecoderSlowPath(typeptr uintptr, typ *runtime.Type) (Decoder, e
 		return dec, nil
 	}
 
-	dec, err := compileHead(typ, map[uintptr]Decoder{})
+	dec, err := compileHead(typ, map[uintptr]Decoder{}, "")
 	if err != nil {
 		return nil, err
 	}
@@ -57,18 +73,51 @@ func compileToGetDecoderSlowPath(typeptr uintptr, typ *runtime.Type) (Decoder, e
 	return dec, nil
 }
 
-func compileHead(typ *runtime.Type, structTypeToDecoder map[uintptr]Decoder) (Decoder, error) {
+// CompileToGetDecoderWithOption returns the decoder of typ for the struct tags selected by opt.
+func CompileToGetDecoderWithOption(typ *runtime.Type, opt *Option) (Decoder, error) {
+	if (opt.Flags & TagKeyOption) == 0 {
+		return CompileToGetDecoder(typ)
+	}
+	typeptr := uintptr(unsafe.Pointer(typ))
+	tagKeyDecodersMu.RLock()
+	dec, exists := tagKeyDecoders[opt.TagKey][typeptr]
+	tagKeyDecodersMu.RUnlock()
+	if exists {
+		return dec, nil
+	}
+	dec, err := compileHead(typ, map[uintptr]Decoder{}, opt.TagKey)
+	if err != nil {
+		return nil, err
+	}
+	tagKeyDecodersMu.Lock()
+	if tagKeyDecoders[opt.TagKey] == nil {
+		tagKeyDecoders[opt.TagKey] = map[uintptr]Decoder{}
+	}
+	tagKeyDecoders[opt.TagKey][typeptr] = dec
+	tagKeyDecodersMu.Unlock()
+	return dec, nil
+}
+
+func compileHead(typ *runtime.Type, structTypeToDecoder map[uintptr]Decoder, tagKey string) (Decoder, error) {
 	switch {
 	case implementsUnmarshalJSONType(runtime.PtrTo(typ)):
 		return newUnmarshalJSONDecoder(runtime.PtrTo(typ), "", ""), nil
 	case runtime.PtrTo(typ).Implements(unmarshalTextType):
 		return newUnmarshalTextDecoder(runtime.PtrTo(typ), "", ""), nil
 	}
-	return compile(typ.Elem(), "", "", structTypeToDecoder)
+	return compile(typ.Elem(), "", "", structTypeToDecoder, tagKey)
 }
 
-func compile(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder) (Decoder, error) {
+func compile(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder, tagKey string) (Decoder, error) {
 	switch {
+	case typ == valueType:
+		return newValueDecoder(structName, fieldName), nil
+	case runtime.IsOptional(typ):
+		return compileOptional(typ, structName, fieldName, structTypeToDecoder, tagKey)
+	case runtime.IsOrderedMap(typ):
+		return compileOrderedMap(typ, structName, fieldName, structTypeToDecoder, tagKey)
+	case isBigNumberType(typ):
+		return newBigNumberDecoder(typ, structName, fieldName), nil
 	case implementsUnmarshalJSONType(runtime.PtrTo(typ)):
 		return newUnmarshalJSONDecoder(runtime.PtrTo(typ), structName, fieldName), nil
 	case runtime.PtrTo(typ).Implements(unmarshalTextType):
@@ -77,19 +126,19 @@ func compile(typ *runtime.Type, structName, fieldName string, structTypeToDecode
 
 	switch typ.Kind() {
 	case reflect.Ptr:
-		return compilePtr(typ, structName, fieldName, structTypeToDecoder)
+		return compilePtr(typ, structName, fieldName, structTypeToDecoder, tagKey)
 	case reflect.Struct:
-		return compileStruct(typ, structName, fieldName, structTypeToDecoder)
+		return compileStruct(typ, structName, fieldName, structTypeToDecoder, tagKey)
 	case reflect.Slice:
 		elem := typ.Elem()
 		if elem.Kind() == reflect.Uint8 {
 			return compileBytes(elem, structName, fieldName)
 		}
-		return compileSlice(typ, structName, fieldName, structTypeToDecoder)
+		return compileSlice(typ, structName, fieldName, structTypeToDecoder, tagKey)
 	case reflect.Array:
-		return compileArray(typ, structName, fieldName, structTypeToDecoder)
+		return compileArray(typ, structName, fieldName, structTypeToDecoder, tagKey)
 	case reflect.Map:
-		return compileMap(typ, structName, fieldName, structTypeToDecoder)
+		return compileMap(typ, structName, fieldName, structTypeToDecoder, tagKey)
 	case reflect.Interface:
 		return compileInterface(typ, structName, fieldName)
 	case reflect.Uintptr:
@@ -117,11 +166,11 @@ func compile(typ *runtime.Type, structName, fieldName string, structTypeToDecode
 	case reflect.String:
 		return compileString(typ, structName, fieldName)
 	case reflect.Bool:
-		return compileBool(structName, fieldName)
+		return compileBool(typ, structName, fieldName)
 	case reflect.Float32:
-		return compileFloat32(structName, fieldName)
+		return compileFloat32(typ, structName, fieldName)
 	case reflect.Float64:
-		return compileFloat64(structName, fieldName)
+		return compileFloat64(typ, structName, fieldName)
 	case reflect.Func:
 		return compileFunc(typ, structName, fieldName)
 	}
@@ -150,14 +199,14 @@ func isStringTagSupportedType(typ *runtime.Type) bool {
 	return true
 }
 
-func compileMapKey(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder) (Decoder, error) {
+func compileMapKey(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder, tagKey string) (Decoder, error) {
 	if runtime.PtrTo(typ).Implements(unmarshalTextType) {
 		return newUnmarshalTextDecoder(runtime.PtrTo(typ), structName, fieldName), nil
 	}
 	if typ.Kind() == reflect.String {
-		return newStringDecoder(structName, fieldName), nil
+		return newMapKeyStringDecoder(structName, fieldName), nil
 	}
-	dec, err := compile(typ, structName, fieldName, structTypeToDecoder)
+	dec, err := compile(typ, structName, fieldName, structTypeToDecoder, tagKey)
 	if err != nil {
 		return nil, err
 	}
@@ -175,8 +224,8 @@ func compileMapKey(typ *runtime.Type, structName, fieldName string, structTypeTo
 	}
 }
 
-func compilePtr(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder) (Decoder, error) {
-	dec, err := compile(typ.Elem(), structName, fieldName, structTypeToDecoder)
+func compilePtr(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder, tagKey string) (Decoder, error) {
+	dec, err := compile(typ.Elem(), structName, fieldName, structTypeToDecoder, tagKey)
 	if err != nil {
 		return nil, err
 	}
@@ -243,14 +292,14 @@ func compileUint64(typ *runtime.Type, structName, fieldName string) (Decoder, er
 	}), nil
 }
 
-func compileFloat32(structName, fieldName string) (Decoder, error) {
-	return newFloatDecoder(structName, fieldName, func(p unsafe.Pointer, v float64) {
+func compileFloat32(typ *runtime.Type, structName, fieldName string) (Decoder, error) {
+	return newFloatDecoder(typ, structName, fieldName, func(p unsafe.Pointer, v float64) {
 		*(*float32)(p) = float32(v)
 	}), nil
 }
 
-func compileFloat64(structName, fieldName string) (Decoder, error) {
-	return newFloatDecoder(structName, fieldName, func(p unsafe.Pointer, v float64) {
+func compileFloat64(typ *runtime.Type, structName, fieldName string) (Decoder, error) {
+	return newFloatDecoder(typ, structName, fieldName, func(p unsafe.Pointer, v float64) {
 		*(*float64)(p) = v
 	}), nil
 }
@@ -264,38 +313,58 @@ func compileString(typ *runtime.Type, structName, fieldName string) (Decoder, er
 	return newStringDecoder(structName, fieldName), nil
 }
 
-func compileBool(structName, fieldName string) (Decoder, error) {
-	return newBoolDecoder(structName, fieldName), nil
+func compileBool(typ *runtime.Type, structName, fieldName string) (Decoder, error) {
+	return newBoolDecoder(typ, structName, fieldName), nil
 }
 
 func compileBytes(typ *runtime.Type, structName, fieldName string) (Decoder, error) {
 	return newBytesDecoder(typ, structName, fieldName), nil
 }
 
-func compileSlice(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder) (Decoder, error) {
+func compileSlice(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder, tagKey string) (Decoder, error) {
 	elem := typ.Elem()
-	decoder, err := compile(elem, structName, fieldName, structTypeToDecoder)
+	decoder, err := compile(elem, structName, fieldName, structTypeToDecoder, tagKey)
 	if err != nil {
 		return nil, err
 	}
 	return newSliceDecoder(decoder, elem, elem.Size(), structName, fieldName), nil
 }
 
-func compileArray(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder) (Decoder, error) {
+func compileArray(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder, tagKey string) (Decoder, error) {
 	elem := typ.Elem()
-	decoder, err := compile(elem, structName, fieldName, structTypeToDecoder)
+	decoder, err := compile(elem, structName, fieldName, structTypeToDecoder, tagKey)
 	if err != nil {
 		return nil, err
 	}
 	return newArrayDecoder(decoder, elem, typ.Len(), structName, fieldName), nil
 }
 
-func compileMap(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder) (Decoder, error) {
-	keyDec, err := compileMapKey(typ.Key(), structName, fieldName, structTypeToDecoder)
+func compileOptional(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder, tagKey string) (Decoder, error) {
+	field := typ.Field(1)
+	valueType := runtime.Type2RType(field.Type)
+	dec, err := compile(valueType, structName, fieldName, structTypeToDecoder, tagKey)
 	if err != nil {
 		return nil, err
 	}
-	valueDec, err := compile(typ.Elem(), structName, fieldName, structTypeToDecoder)
+	return newOptionalDecoder(dec, valueType, field.Offset), nil
+}
+
+func compileOrderedMap(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder, tagKey string) (Decoder, error) {
+	values := typ.Field(1)
+	valueType := runtime.Type2RType(values.Type.Elem())
+	dec, err := compile(valueType, structName, fieldName, structTypeToDecoder, tagKey)
+	if err != nil {
+		return nil, err
+	}
+	return newOrderedMapDecoder(typ, valueType, dec, values.Offset, typ.Field(2).Offset, structName, fieldName), nil
+}
+
+func compileMap(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder, tagKey string) (Decoder, error) {
+	keyDec, err := compileMapKey(typ.Key(), structName, fieldName, structTypeToDecoder, tagKey)
+	if err != nil {
+		return nil, err
+	}
+	valueDec, err := compile(typ.Elem(), structName, fieldName, structTypeToDecoder, tagKey)
 	if err != nil {
 		return nil, err
 	}
@@ -303,6 +372,9 @@ func compileMap(typ *runtime.Type, structName, fieldName string, structTypeToDec
 }
 
 func compileInterface(typ *runtime.Type, structName, fieldName string) (Decoder, error) {
+	if union := runtime.UnionOf(typ); union != nil {
+		return newUnionDecoder(typ, union, structName, fieldName), nil
+	}
 	return newInterfaceDecoder(typ, structName, fieldName), nil
 }
 
@@ -310,39 +382,24 @@ func compileFunc(typ *runtime.Type, strutName, fieldName string) (Decoder, error
 	return newFuncDecoder(typ, strutName, fieldName), nil
 }
 
-func typeToStructTags(typ *runtime.Type) runtime.StructTags {
-	tags := runtime.StructTags{}
-	fieldNum := typ.NumField()
-	for i := 0; i < fieldNum; i++ {
-		field := typ.Field(i)
-		if runtime.IsIgnoredStructField(field) {
-			continue
-		}
-		tags = append(tags, runtime.StructTagFromField(field))
-	}
-	return tags
-}
-
-func compileStruct(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder) (Decoder, error) {
-	fieldNum := typ.NumField()
+func compileStruct(typ *runtime.Type, structName, fieldName string, structTypeToDecoder map[uintptr]Decoder, tagKey string) (Decoder, error) {
 	fieldMap := map[string]*structFieldSet{}
 	typeptr := uintptr(unsafe.Pointer(typ))
 	if dec, exists := structTypeToDecoder[typeptr]; exists {
 		return dec, nil
 	}
-	structDec := newStructDecoder(structName, fieldName, fieldMap)
+	structDec := newStructDecoder(typ, structName, fieldName, fieldMap)
 	structTypeToDecoder[typeptr] = structDec
 	structName = typ.Name()
-	tags := typeToStructTags(typ)
+	tags := runtime.StructFieldTags(typ, tagKey)
 	allFields := []*structFieldSet{}
-	for i := 0; i < fieldNum; i++ {
-		field := typ.Field(i)
-		if runtime.IsIgnoredStructField(field) {
-			continue
+	for _, tag := range tags {
+		if tag.Err != nil {
+			return nil, tag.Err
 		}
+		field := tag.Field
 		isUnexportedField := unicode.IsLower([]rune(field.Name)[0])
-		tag := runtime.StructTagFromField(field)
-		dec, err := compile(runtime.Type2RType(field.Type), structName, field.Name, structTypeToDecoder)
+		dec, err := compile(runtime.Type2RType(field.Type), structName, field.Name, structTypeToDecoder, tagKey)
 		if err != nil {
 			return nil, err
 		}
@@ -353,7 +410,7 @@ func compileStruct(typ *runtime.Type, structName, fieldName string, structTypeTo
 					continue
 				}
 				for k, v := range stDec.fieldMap {
-					if tags.ExistsKey(k) {
+					if tags.ExistsKey(k) || v.aliasOf != "" {
 						continue
 					}
 					fieldSet := &structFieldSet{
@@ -362,6 +419,8 @@ func compileStruct(typ *runtime.Type, structName, fieldName string, structTypeTo
 						isTaggedKey: v.isTaggedKey,
 						key:         k,
 						keyLen:      int64(len(k)),
+						nullErr:     v.nullErr,
+						aliases:     v.aliases,
 					}
 					allFields = append(allFields, fieldSet)
 				}
@@ -380,7 +439,7 @@ func compileStruct(typ *runtime.Type, structName, fieldName string, structTypeTo
 				}
 				if dec, ok := contentDec.(*structDecoder); ok {
 					for k, v := range dec.fieldMap {
-						if tags.ExistsKey(k) {
+						if tags.ExistsKey(k) || v.aliasOf != "" {
 							continue
 						}
 						fieldSet := &structFieldSet{
@@ -390,6 +449,8 @@ func compileStruct(typ *runtime.Type, structName, fieldName string, structTypeTo
 							key:         k,
 							keyLen:      int64(len(k)),
 							err:         fieldSetErr,
+							nullErr:     v.nullErr,
+							aliases:     v.aliases,
 						}
 						allFields = append(allFields, fieldSet)
 					}
@@ -405,8 +466,15 @@ func compileStruct(typ *runtime.Type, structName, fieldName string, structTypeTo
 				allFields = append(allFields, fieldSet)
 			}
 		} else {
-			if tag.IsString && isStringTagSupportedType(runtime.Type2RType(field.Type)) {
-				dec = newWrappedStringDecoder(runtime.Type2RType(field.Type), dec, structName, field.Name)
+			if tag.IsString {
+				if optDec, ok := dec.(*optionalDecoder); ok {
+					dec = optDec.withString(structName, field.Name)
+				} else if isStringTagSupportedType(runtime.Type2RType(field.Type)) {
+					dec = newWrappedStringDecoder(runtime.Type2RType(field.Type), dec, structName, field.Name)
+				}
+			}
+			if tag.BytesFormat != runtime.BytesFormatDefault {
+				dec = withBytesFormat(dec, tag.BytesFormat)
 			}
 			var key string
 			if tag.Key != "" {
@@ -420,11 +488,21 @@ func compileStruct(typ *runtime.Type, structName, fieldName string, structTypeTo
 				isTaggedKey: tag.IsTaggedKey,
 				key:         key,
 				keyLen:      int64(len(key)),
+				aliases:     tag.Aliases,
+			}
+			if !tag.IsNullable && !acceptsNull(runtime.Type2RType(field.Type)) {
+				fieldSet.nullErr = &errors.UnmarshalTypeError{
+					Value:  "null",
+					Type:   field.Type,
+					Struct: structName,
+					Field:  field.Name,
+				}
 			}
 			allFields = append(allFields, fieldSet)
 		}
 	}
-	for _, set := range filterDuplicatedFields(allFields) {
+	fieldSets := filterDuplicatedFields(allFields)
+	for _, set := range fieldSets {
 		fieldMap[set.key] = set
 		lower := strings.ToLower(set.key)
 		if _, exists := fieldMap[lower]; !exists {
@@ -432,11 +510,34 @@ func compileStruct(typ *runtime.Type, structName, fieldName string, structTypeTo
 			fieldMap[lower] = set
 		}
 	}
+	for _, set := range fieldSets {
+		addAliasFieldSets(fieldMap, set)
+	}
 	delete(structTypeToDecoder, typeptr)
 	structDec.tryOptimize()
 	return structDec, nil
 }
 
+// addAliasFieldSets adds the aliases of the field of set to fieldMap as keys that decode into the same field.
+// Keys of other fields take precedence over aliases.
+func addAliasFieldSets(fieldMap map[string]*structFieldSet, set *structFieldSet) {
+	for _, alias := range set.aliases {
+		if _, exists := fieldMap[alias]; exists {
+			continue
+		}
+		aliasSet := *set
+		aliasSet.key = alias
+		aliasSet.keyLen = int64(len(alias))
+		aliasSet.aliases = nil
+		aliasSet.aliasOf = set.key
+		fieldMap[alias] = &aliasSet
+		lower := strings.ToLower(alias)
+		if _, exists := fieldMap[lower]; !exists {
+			fieldMap[lower] = &aliasSet
+		}
+	}
+}
+
 func filterDuplicatedFields(allFields []*structFieldSet) []*structFieldSet {
 	fieldMap := map[string][]*structFieldSet{}
 	for _, field := range allFields {
@@ -473,6 +574,12 @@ func filterFieldSets(sets []*structFieldSet) []*structFieldSet {
 	return filtered
 }
 
+// acceptsNull reports whether values of typ can be null with RejectNullOption without the nullable tag option:
+// interfaces, which are set to nil, Optional values, which record it, and types whose UnmarshalJSON method receives null.
+func acceptsNull(typ *runtime.Type) bool {
+	return typ.Kind() == reflect.Interface || runtime.IsOptional(typ) || implementsUnmarshalJSONType(runtime.PtrTo(typ))
+}
+
 func implementsUnmarshalJSONType(typ *runtime.Type) bool {
 	return typ.Implements(unmarshalJSONType) || typ.Implements(unmarshalJSONContextType)
 }
@@ -20,10 +20,16 @@ func CompileToGetDecoder(typ *runtime.Type) (Decoder, error) {
 		return dec, nil
 	}
 
-	dec, err := compileHead(typ, map[uintptr]Decoder{})
+	dec, err := compileHead(typ, map[uintptr]Decoder{}, "")
 	if err != nil {
 		return nil, err
 	}
 	cachedDecoder[index] = dec
 	return dec, nil
 }
+
+func clearCachedDecoder() {
+	for i := range cachedDecoder {
+		cachedDecoder[i] = nil
+	}
+}
@@ -26,7 +26,7 @@ func CompileToGetDecoder(typ *runtime.Type) (Decoder, error) {
 	}
 	decMu.RUnlock()
 
-	dec, err := compileHead(typ, map[uintptr]Decoder{})
+	dec, err := compileHead(typ, map[uintptr]Decoder{}, "")
 	if err != nil {
 		return nil, err
 	}
@@ -35,3 +35,11 @@ func CompileToGetDecoder(typ *runtime.Type) (Decoder, error) {
 	decMu.Unlock()
 	return dec, nil
 }
+
+func clearCachedDecoder() {
+	decMu.Lock()
+	for i := range cachedDecoder {
+		cachedDecoder[i] = nil
+	}
+	decMu.Unlock()
+}
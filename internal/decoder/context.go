@@ -77,7 +77,7 @@ func skipObject(buf []byte, cursor, depth int64) (int64, error) {
 			depth--
 		case '"':
 			for {
-				cursor++
+				cursor = scanString(buf, cursor+1)
 				switch buf[cursor] {
 				case '\\':
 					cursor++
@@ -92,6 +92,11 @@ func skipObject(buf []byte, cursor, depth int64) (int64, error) {
 			}
 		case nul:
 			return 0, errors.ErrUnexpectedEndOfJSON("object of object", cursor)
+		default:
+			if cursor++; !structural[buf[cursor]] {
+				cursor = scanStructural(buf, cursor)
+			}
+			continue
 		}
 	SWITCH_OUT:
 		cursor++
@@ -123,7 +128,7 @@ func skipArray(buf []byte, cursor, depth int64) (int64, error) {
 			depth--
 		case '"':
 			for {
-				cursor++
+				cursor = scanString(buf, cursor+1)
 				switch buf[cursor] {
 				case '\\':
 					cursor++
@@ -138,6 +143,11 @@ func skipArray(buf []byte, cursor, depth int64) (int64, error) {
 			}
 		case nul:
 			return 0, errors.ErrUnexpectedEndOfJSON("array of object", cursor)
+		default:
+			if cursor++; !structural[buf[cursor]] {
+				cursor = scanStructural(buf, cursor)
+			}
+			continue
 		}
 	SWITCH_OUT:
 		cursor++
@@ -156,7 +166,7 @@ func skipValue(buf []byte, cursor, depth int64) (int64, error) {
 			return skipArray(buf, cursor+1, depth+1)
 		case '"':
 			for {
-				cursor++
+				cursor = scanString(buf, cursor+1)
 				switch buf[cursor] {
 				case '\\':
 					cursor++
@@ -0,0 +1,124 @@
+package decoder
+
+import (
+	"github.com/goccy/go-json/internal/errors"
+)
+
+// keySet remembers the keys already seen in a single JSON object
+// to detect duplicated keys.
+// Keys that resolve to a struct field are identified by the field,
+// so that keys differing only in case are also treated as duplicates.
+type keySet struct {
+	fields map[int]struct{}
+	keys   map[string]struct{}
+}
+
+func (s *keySet) addField(field *structFieldSet, key string, offset int64) error {
+	if s.fields == nil {
+		s.fields = map[int]struct{}{}
+	}
+	if _, exists := s.fields[field.fieldIdx]; exists {
+		return &errors.DuplicateKeyError{Key: key, Offset: offset}
+	}
+	s.fields[field.fieldIdx] = struct{}{}
+	return nil
+}
+
+func (s *keySet) addKey(key []byte, offset int64) error {
+	if s.keys == nil {
+		s.keys = map[string]struct{}{}
+	}
+	if _, exists := s.keys[string(key)]; exists {
+		return &errors.DuplicateKeyError{Key: string(key), Offset: offset}
+	}
+	s.keys[string(key)] = struct{}{}
+	return nil
+}
+
+// keyBytes returns the unescaped content of the object key
+// that starts at cursor and ends just before end,
+// so that keys spelled with different escapes compare equal.
+func keyBytes(buf []byte, cursor, end int64) []byte {
+	if end-cursor < 2 {
+		return nil
+	}
+	if key, ok := unquoteBytes(buf[cursor:end]); ok {
+		return key
+	}
+	return buf[cursor+1 : end-1]
+}
+
+// skipUnusedValue skips the value at cursor that is not decoded into any Go value.
+// With RejectDuplicateKeysOption, the objects inside it are still checked for duplicated keys.
+func skipUnusedValue(ctx *RuntimeContext, cursor, depth int64) (int64, error) {
+	if (ctx.Option.Flags & RejectDuplicateKeysOption) == 0 {
+		return skipValue(ctx.Buf, cursor, depth)
+	}
+	s := &Scanner{Buf: ctx.Buf, Cursor: cursor, RejectDuplicateKeys: true}
+	if err := s.Skip(depth); err != nil {
+		return 0, err
+	}
+	return s.Cursor, nil
+}
+
+// skipUnusedValue skips the value at the cursor that is not decoded into any Go value.
+// With RejectDuplicateKeysOption, the objects inside it are still checked for duplicated keys.
+func (s *Stream) skipUnusedValue(depth int64) error {
+	if (s.Option.Flags & RejectDuplicateKeysOption) == 0 {
+		return s.skipValue(depth)
+	}
+	return s.skipValueRejectingDuplicateKeys(depth)
+}
+
+func (s *Stream) skipValueRejectingDuplicateKeys(depth int64) error {
+	begin := s.skipWhiteSpace()
+	if begin != '{' && begin != '[' {
+		return s.skipValue(depth)
+	}
+	depth++
+	if depth > maxDecodeNestingDepth {
+		return errors.ErrExceededMaxDepth(begin, s.cursor)
+	}
+	end := byte(']')
+	if begin == '{' {
+		end = '}'
+	}
+	s.cursor++
+	if s.skipWhiteSpace() == end {
+		s.cursor++
+		return nil
+	}
+	var seenKeys keySet
+	for {
+		if begin == '{' {
+			if s.skipWhiteSpace() != '"' {
+				return errors.ErrExpected("object key", s.totalOffset())
+			}
+			keyStart, keyOffset := s.cursor, s.totalOffset()
+			if err := s.skipValue(depth); err != nil {
+				return err
+			}
+			if err := seenKeys.addKey(keyBytes(s.buf, keyStart, s.cursor), keyOffset); err != nil {
+				return err
+			}
+			if s.skipWhiteSpace() != ':' {
+				return errors.ErrExpected("colon after object key", s.totalOffset())
+			}
+			s.cursor++
+		}
+		if err := s.skipValueRejectingDuplicateKeys(depth); err != nil {
+			return err
+		}
+		switch s.skipWhiteSpace() {
+		case end:
+			s.cursor++
+			return nil
+		case ',':
+			s.cursor++
+		case nul:
+			return errors.ErrUnexpectedEndOfJSON(scannerContext(end), s.totalOffset())
+		default:
+			return errors.ErrExpected("comma after "+scannerContext(end)+" element", s.totalOffset())
+		}
+	}
+}
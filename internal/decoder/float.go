@@ -5,16 +5,28 @@ import (
 	"unsafe"
 
 	"github.com/goccy/go-json/internal/errors"
+	"github.com/goccy/go-json/internal/runtime"
 )
 
 type floatDecoder struct {
+	typ        *runtime.Type
 	op         func(unsafe.Pointer, float64)
 	structName string
 	fieldName  string
 }
 
-func newFloatDecoder(structName, fieldName string, op func(unsafe.Pointer, float64)) *floatDecoder {
-	return &floatDecoder{op: op, structName: structName, fieldName: fieldName}
+func newFloatDecoder(typ *runtime.Type, structName, fieldName string, op func(unsafe.Pointer, float64)) *floatDecoder {
+	return &floatDecoder{typ: typ, op: op, structName: structName, fieldName: fieldName}
+}
+
+func (d *floatDecoder) typeError(typeName string, offset int64) *errors.UnmarshalTypeError {
+	return &errors.UnmarshalTypeError{
+		Value:  typeName,
+		Type:   runtime.RType2Type(d.typ),
+		Struct: d.structName,
+		Field:  d.fieldName,
+		Offset: offset,
+	}
 }
 
 var (
@@ -79,6 +91,8 @@ func (d *floatDecoder) decodeStreamByte(s *Stream) ([]byte, error) {
 				return nil, err
 			}
 			return nil, nil
+		case '"', '[', '{', 't', 'f':
+			return nil, d.typeError(typeName(s.char()), s.totalOffset())
 		case nul:
 			if s.read() {
 				continue
@@ -112,6 +126,8 @@ func (d *floatDecoder) decodeByte(buf []byte, cursor int64) ([]byte, int64, erro
 			}
 			cursor += 4
 			return nil, cursor, nil
+		case '"', '[', '{', 't', 'f':
+			return nil, 0, d.typeError(typeName(buf[cursor]), cursor)
 		default:
 			return nil, 0, errors.ErrUnexpectedEndOfJSON("float", cursor)
 		}
@@ -121,7 +137,10 @@ func (d *floatDecoder) decodeByte(buf []byte, cursor int64) ([]byte, int64, erro
 func (d *floatDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) error {
 	bytes, err := d.decodeStreamByte(s)
 	if err != nil {
-		return err
+		bytes, err = lenientNumberStream(s, floatLiteral, err)
+		if err != nil {
+			return err
+		}
 	}
 	if bytes == nil {
 		return nil
@@ -139,7 +158,10 @@ func (d *floatDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe
 	buf := ctx.Buf
 	bytes, c, err := d.decodeByte(buf, cursor)
 	if err != nil {
-		return 0, err
+		bytes, c, err = lenientNumberByte(ctx, cursor, floatLiteral, err)
+		if err != nil {
+			return 0, err
+		}
 	}
 	if bytes == nil {
 		return c, nil
@@ -0,0 +1,404 @@
+package decoder
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/goccy/go-json/internal/errors"
+)
+
+// formatBufSize is the size of the input buffer and of the output collected before it is written.
+const formatBufSize = 32 * 1024
+
+// formatter rewrites the JSON values read from a Stream compacted or indented.
+// It works like the compact and indent functions of the encoder, but consumes the
+// input while writing the output, so memory use does not depend on the size of the values.
+type formatter struct {
+	s        *Stream
+	r        *formatReader
+	w        io.Writer
+	dst      []byte
+	prefix   []byte
+	indent   []byte
+	indented bool
+	err      error // error returned by w
+
+	rejectDuplicateKeys bool
+	inKey               bool // the key at the end of dst must not be flushed yet
+}
+
+// formatReader records the error that the Stream does not report when reading fails.
+type formatReader struct {
+	r   io.Reader
+	err error
+}
+
+func (r *formatReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+// FormatStream writes the JSON values read from r to w, each followed by a newline.
+// Values are compacted, or indented with prefix and indent if indented is true.
+func FormatStream(w io.Writer, r io.Reader, prefix, indent string, indented bool) error {
+	f := newFormatter(w, r, prefix, indent, indented)
+	for f.skipWhiteSpace() != nul {
+		if err := f.value(0, 0); err != nil {
+			return f.error(err)
+		}
+		f.dst = append(f.dst, '\n')
+	}
+	return f.finish()
+}
+
+// ValidateStream checks that r holds a single JSON value, optionally surrounded by space characters.
+// Like FormatStream, it reads r in chunks, so memory use does not depend on the size of the value.
+// If rejectDuplicateKeys is true, objects must not repeat a key.
+func ValidateStream(r io.Reader, rejectDuplicateKeys bool) error {
+	f := newFormatter(ioutil.Discard, r, "", "", false)
+	f.rejectDuplicateKeys = rejectDuplicateKeys
+	if err := f.value(0, 0); err != nil {
+		return f.error(err)
+	}
+	if c := f.skipWhiteSpace(); c != nul || f.s.cursor < f.s.length {
+		return f.error(errors.ErrAfterTopLevelValue(c, f.s.totalOffset()))
+	}
+	return f.finish()
+}
+
+func newFormatter(w io.Writer, r io.Reader, prefix, indent string, indented bool) *formatter {
+	fr := &formatReader{r: r}
+	s := NewStream(fr)
+	s.buf = make([]byte, formatBufSize)
+	s.bufSize = formatBufSize
+	return &formatter{
+		s:        s,
+		r:        fr,
+		w:        w,
+		dst:      make([]byte, 0, formatBufSize),
+		prefix:   []byte(prefix),
+		indent:   []byte(indent),
+		indented: indented,
+	}
+}
+
+// finish checks that the whole input was consumed and writes the remaining output.
+func (f *formatter) finish() error {
+	if err := f.error(nil); err != nil {
+		return err
+	}
+	if f.s.totalOffset() < f.s.offset+f.s.length {
+		return f.error(errors.ErrInvalidCharacter(nul, "value", f.s.totalOffset()))
+	}
+	f.flush()
+	return f.err
+}
+
+// error returns the error that stopped formatting: a read or write error, or err located in the input.
+func (f *formatter) error(err error) error {
+	switch {
+	case f.r.err != nil:
+		return f.r.err
+	case f.err != nil:
+		return f.err
+	case f.s.LimitError() != nil:
+		return f.s.LimitError()
+	case err != nil:
+		return f.s.LocateError(err)
+	}
+	return nil
+}
+
+func (f *formatter) flush() {
+	if f.err == nil && len(f.dst) > 0 {
+		_, f.err = f.w.Write(f.dst)
+	}
+	f.dst = f.dst[:0]
+}
+
+// char returns the byte at the cursor. When the buffered input is consumed, it discards it
+// and reads more into the same buffer, so memory use stays constant.
+// It returns nul at the end of the input or if writing the output failed.
+func (f *formatter) char() byte {
+	s := f.s
+	for {
+		if c := s.buf[s.cursor]; c != nul || s.cursor < s.length {
+			return c
+		}
+		if len(f.dst) >= formatBufSize && !f.inKey {
+			f.flush()
+		}
+		if f.err != nil {
+			return nul
+		}
+		s.discard()
+		if !s.read() {
+			return nul
+		}
+		// a short read leaves the bytes of the previous chunk after the new ones
+		s.buf[s.length] = nul
+	}
+}
+
+func (f *formatter) skipWhiteSpace() byte {
+	for {
+		switch c := f.char(); c {
+		case ' ', '\t', '\n', '\r':
+			f.s.cursor++
+		default:
+			return c
+		}
+	}
+}
+
+func (f *formatter) newline(indentNum int) {
+	f.dst = append(append(f.dst, '\n'), f.prefix...)
+	for i := 0; i < indentNum; i++ {
+		f.dst = append(f.dst, f.indent...)
+	}
+}
+
+func (f *formatter) value(depth int64, indentNum int) error {
+	s := f.s
+	switch c := f.skipWhiteSpace(); c {
+	case '{', '[':
+		return f.container(c, depth+1, indentNum)
+	case '"':
+		return f.string()
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return f.number()
+	case 't':
+		return f.literal("true")
+	case 'f':
+		return f.literal("false")
+	case 'n':
+		return f.literal("null")
+	case nul:
+		return errors.ErrUnexpectedEndOfJSON("value", s.totalOffset())
+	default:
+		return errors.ErrInvalidBeginningOfValue(c, s.totalOffset())
+	}
+}
+
+// container formats the object or array opened by begin.
+func (f *formatter) container(begin byte, depth int64, indentNum int) error {
+	s := f.s
+	if depth > maxDecodeNestingDepth {
+		return errors.ErrExceededMaxDepth(begin, s.totalOffset())
+	}
+	end := byte(']')
+	if begin == '{' {
+		end = '}'
+	}
+	f.dst = append(f.dst, begin)
+	s.cursor++
+	if f.skipWhiteSpace() == end {
+		f.dst = append(f.dst, end)
+		s.cursor++
+		return nil
+	}
+	var seenKeys keySet
+	for {
+		if f.indented {
+			f.newline(indentNum + 1)
+		}
+		if c := f.skipWhiteSpace(); !isElementBeginning(c, end) {
+			return errElement(c, end, s.totalOffset())
+		}
+		if begin == '{' {
+			if err := f.key(&seenKeys); err != nil {
+				return err
+			}
+			if f.skipWhiteSpace() != ':' {
+				return errors.ErrExpected("colon after object key", s.totalOffset())
+			}
+			s.cursor++
+			f.dst = append(f.dst, ':')
+			if f.indented {
+				f.dst = append(f.dst, ' ')
+			}
+		}
+		if err := f.value(depth, indentNum+1); err != nil {
+			return err
+		}
+		switch c := f.skipWhiteSpace(); c {
+		case end:
+			if f.indented {
+				f.newline(indentNum)
+			}
+			f.dst = append(f.dst, end)
+			s.cursor++
+			return nil
+		case ',':
+			f.dst = append(f.dst, ',')
+			s.cursor++
+		default:
+			return errAfterElement(c, end, s.totalOffset())
+		}
+	}
+}
+
+// key copies the object key at the cursor like string.
+// If duplicated keys are rejected, it also adds the key to seenKeys.
+func (f *formatter) key(seenKeys *keySet) error {
+	if !f.rejectDuplicateKeys {
+		return f.string()
+	}
+	start, offset := len(f.dst), f.s.totalOffset()
+	f.inKey = true
+	err := f.string()
+	f.inKey = false
+	if err != nil {
+		return err
+	}
+	return seenKeys.addKey(keyBytes(f.dst, int64(start), int64(len(f.dst))), offset)
+}
+
+// string copies the string at the cursor, reading it in chunks.
+func (f *formatter) string() error {
+	s := f.s
+	start := s.cursor
+	s.cursor++
+	for {
+		switch c := s.buf[s.cursor]; c {
+		case '\\':
+			s.cursor++
+			if !f.stringChunk(&start) {
+				return f.errEscape()
+			}
+			switch s.buf[s.cursor] {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+				s.cursor++
+			case 'u':
+				s.cursor++
+				for i := 0; i < 4; i++ {
+					if !f.stringChunk(&start) || !isHexDigit(s.buf[s.cursor]) {
+						return f.errEscape()
+					}
+					s.cursor++
+				}
+			default:
+				return f.errEscape()
+			}
+		case '"':
+			s.cursor++
+			f.dst = append(f.dst, s.buf[start:s.cursor]...)
+			return nil
+		case nul:
+			if !f.stringChunk(&start) {
+				return f.errInvalidCharacter("string")
+			}
+		default:
+			if c < 0x20 {
+				return f.errInvalidCharacter("string")
+			}
+			s.cursor++
+		}
+	}
+}
+
+// stringChunk makes the byte at the cursor available inside a string starting at start.
+// At the end of the buffered input, it copies the part of the string read so far and reads more.
+// It reports false at the end of the input or at a nul byte in it.
+func (f *formatter) stringChunk(start *int64) bool {
+	s := f.s
+	if s.buf[s.cursor] != nul {
+		return true
+	}
+	f.dst = append(f.dst, s.buf[*start:s.cursor]...)
+	c := f.char()
+	*start = s.cursor
+	return c != nul
+}
+
+func (f *formatter) literal(literal string) error {
+	for i := 0; i < len(literal); i++ {
+		if f.char() != literal[i] {
+			return f.errInvalidCharacter(literal)
+		}
+		f.s.cursor++
+	}
+	f.dst = append(f.dst, literal...)
+	return nil
+}
+
+// number copies the number at the cursor. Like Scanner.Number, it stops at the first byte
+// that cannot continue the number, so both report the same errors for invalid numbers.
+func (f *formatter) number() error {
+	c := f.char()
+	if c == '-' {
+		c = f.next(c)
+	}
+	switch {
+	case c == '0':
+		c = f.next(c)
+	case '1' <= c && c <= '9':
+		c = f.digits()
+	default:
+		return f.errInvalidCharacter("number")
+	}
+	if c == '.' {
+		if !isDigit(f.next(c)) {
+			return f.errInvalidCharacter("number")
+		}
+		c = f.digits()
+	}
+	if c == 'e' || c == 'E' {
+		c = f.next(c)
+		if c == '+' || c == '-' {
+			c = f.next(c)
+		}
+		if !isDigit(c) {
+			return f.errInvalidCharacter("number")
+		}
+		f.digits()
+	}
+	return nil
+}
+
+// next copies c at the cursor and returns the byte after it.
+func (f *formatter) next(c byte) byte {
+	f.dst = append(f.dst, c)
+	f.s.cursor++
+	return f.char()
+}
+
+// digits copies the digits at the cursor and returns the byte after them.
+func (f *formatter) digits() byte {
+	c := f.char()
+	for isDigit(c) {
+		c = f.next(c)
+	}
+	return c
+}
+
+// errInvalidCharacter reports the byte at the cursor, which cannot continue the value described by context,
+// or the end of the input if the cursor reached it.
+func (f *formatter) errInvalidCharacter(context string) error {
+	return errInvalidCharacter(f.char(), f.isEnd(), context, f.s.totalOffset())
+}
+
+// errEscape reports the byte at the cursor, which cannot continue an escape sequence,
+// or the end of the input if the cursor reached it.
+func (f *formatter) errEscape() error {
+	return errEscape(f.char(), f.isEnd(), f.s.totalOffset())
+}
+
+// isEnd reports whether the whole input was consumed.
+func (f *formatter) isEnd() bool {
+	return f.char() == nul && f.s.cursor >= f.s.length
+}
+
+// discard drops the buffered input, which must have been consumed,
+// so that the next read fills the buffer from its beginning without growing it.
+func (s *Stream) discard() {
+	s.countLines()
+	s.offset += s.cursor
+	s.cursor = 0
+	s.length = 0
+	s.buf[0] = nul
+	s.filledBuffer = false
+}
@@ -181,7 +181,10 @@ func (d *intDecoder) decodeByte(buf []byte, cursor int64) ([]byte, int64, error)
 func (d *intDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) error {
 	bytes, err := d.decodeStreamByte(s)
 	if err != nil {
-		return err
+		bytes, err = lenientNumberStream(s, integerLiteral, err)
+		if err != nil {
+			return err
+		}
 	}
 	if bytes == nil {
 		return nil
@@ -212,7 +215,10 @@ func (d *intDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) erro
 func (d *intDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.Pointer) (int64, error) {
 	bytes, c, err := d.decodeByte(ctx.Buf, cursor)
 	if err != nil {
-		return 0, err
+		bytes, c, err = lenientNumberByte(ctx, cursor, integerLiteral, err)
+		if err != nil {
+			return 0, err
+		}
 	}
 	if bytes == nil {
 		return c, nil
@@ -5,6 +5,7 @@ import (
 	"encoding"
 	"encoding/json"
 	"reflect"
+	"strconv"
 	"unsafe"
 
 	"github.com/goccy/go-json/internal/errors"
@@ -27,13 +28,13 @@ func newEmptyInterfaceDecoder(structName, fieldName string) *interfaceDecoder {
 		typ:        emptyInterfaceType,
 		structName: structName,
 		fieldName:  fieldName,
-		floatDecoder: newFloatDecoder(structName, fieldName, func(p unsafe.Pointer, v float64) {
+		floatDecoder: newFloatDecoder(emptyInterfaceType, structName, fieldName, func(p unsafe.Pointer, v float64) {
 			*(*interface{})(p) = v
 		}),
 		numberDecoder: newNumberDecoder(structName, fieldName, func(p unsafe.Pointer, v json.Number) {
 			*(*interface{})(p) = v
 		}),
-		stringDecoder: newStringDecoder(structName, fieldName),
+		stringDecoder: newMapKeyStringDecoder(structName, fieldName),
 	}
 	ifaceDecoder.sliceDecoder = newSliceDecoder(
 		ifaceDecoder,
@@ -55,7 +56,7 @@ func newEmptyInterfaceDecoder(structName, fieldName string) *interfaceDecoder {
 
 func newInterfaceDecoder(typ *runtime.Type, structName, fieldName string) *interfaceDecoder {
 	emptyIfaceDecoder := newEmptyInterfaceDecoder(structName, fieldName)
-	stringDecoder := newStringDecoder(structName, fieldName)
+	stringDecoder := newMapKeyStringDecoder(structName, fieldName)
 	return &interfaceDecoder{
 		typ:        typ,
 		structName: structName,
@@ -75,7 +76,7 @@ func newInterfaceDecoder(typ *runtime.Type, structName, fieldName string) *inter
 			structName,
 			fieldName,
 		),
-		floatDecoder: newFloatDecoder(structName, fieldName, func(p unsafe.Pointer, v float64) {
+		floatDecoder: newFloatDecoder(typ, structName, fieldName, func(p unsafe.Pointer, v float64) {
 			*(*interface{})(p) = v
 		}),
 		numberDecoder: newNumberDecoder(structName, fieldName, func(p unsafe.Pointer, v json.Number) {
@@ -86,12 +87,61 @@ func newInterfaceDecoder(typ *runtime.Type, structName, fieldName string) *inter
 }
 
 func (d *interfaceDecoder) numDecoder(s *Stream) Decoder {
-	if s.UseNumber {
+	if s.UseNumber || (s.Option.Flags&UseNumberOption) != 0 {
 		return d.numberDecoder
 	}
 	return d.floatDecoder
 }
 
+// decodeStreamNumber decodes a number into an empty interface value.
+// With IntegersAsInt64Option, integral numbers that fit into int64 become int64.
+func (d *interfaceDecoder) decodeStreamNumber(s *Stream, depth int64, p unsafe.Pointer) error {
+	if (s.Option.Flags & IntegersAsInt64Option) != 0 {
+		start := s.cursor
+		if i64, ok := parseInt64Literal(floatBytes(s)); ok {
+			*(*interface{})(p) = i64
+			return nil
+		}
+		s.cursor = start
+	}
+	return d.numDecoder(s).DecodeStream(s, depth, p)
+}
+
+// decodeNumber is the byte version of decodeStreamNumber.
+// It also handles UseNumberOption, which is the only way to get json.Number values from Unmarshal.
+func (d *interfaceDecoder) decodeNumber(ctx *RuntimeContext, cursor, depth int64, p unsafe.Pointer) (int64, error) {
+	if (ctx.Option.Flags & IntegersAsInt64Option) != 0 {
+		buf := ctx.Buf
+		start := cursor
+		cursor++
+		for floatTable[buf[cursor]] {
+			cursor++
+		}
+		if validEndNumberChar[buf[cursor]] {
+			if i64, ok := parseInt64Literal(buf[start:cursor]); ok {
+				*(*interface{})(p) = i64
+				return cursor, nil
+			}
+		}
+		cursor = start
+	}
+	if (ctx.Option.Flags & UseNumberOption) != 0 {
+		return d.numberDecoder.Decode(ctx, cursor, depth, p)
+	}
+	return d.floatDecoder.Decode(ctx, cursor, depth, p)
+}
+
+func parseInt64Literal(b []byte) (int64, bool) {
+	if !isNumberLiteral(b, integerLiteral) {
+		return 0, false
+	}
+	i64, err := strconv.ParseInt(*(*string)(unsafe.Pointer(&b)), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return i64, true
+}
+
 var (
 	emptyInterfaceType = runtime.Type2RType(reflect.TypeOf((*interface{})(nil)).Elem())
 	interfaceMapType   = runtime.Type2RType(
@@ -212,6 +262,9 @@ func (d *interfaceDecoder) decodeStreamEmptyInterface(s *Stream, depth int64, p
 	for {
 		switch c {
 		case '{':
+			if (s.Option.Flags & OrderedObjectsOption) != 0 {
+				return d.decodeStreamOrderedObject(s, depth, p)
+			}
 			var v map[string]interface{}
 			ptr := unsafe.Pointer(&v)
 			if err := d.mapDecoder.DecodeStream(s, depth, ptr); err != nil {
@@ -228,7 +281,7 @@ func (d *interfaceDecoder) decodeStreamEmptyInterface(s *Stream, depth int64, p
 			*(*interface{})(p) = v
 			return nil
 		case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-			return d.numDecoder(s).DecodeStream(s, depth, p)
+			return d.decodeStreamNumber(s, depth, p)
 		case '"':
 			s.cursor++
 			start := s.cursor
@@ -240,6 +293,9 @@ func (d *interfaceDecoder) decodeStreamEmptyInterface(s *Stream, depth int64, p
 					}
 				case '"':
 					literal := s.buf[start:s.cursor]
+					if err := s.Option.checkStringLen(len(literal), s.totalOffset()); err != nil {
+						return err
+					}
 					s.cursor++
 					*(*interface{})(p) = string(literal)
 					return nil
@@ -327,7 +383,7 @@ func (d *interfaceDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer
 		*(*interface{})(p) = nil
 		return nil
 	}
-	decoder, err := CompileToGetDecoder(typ)
+	decoder, err := CompileToGetDecoderWithOption(typ, s.Option)
 	if err != nil {
 		return err
 	}
@@ -392,7 +448,7 @@ func (d *interfaceDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p un
 		**(**interface{})(unsafe.Pointer(&p)) = nil
 		return cursor, nil
 	}
-	decoder, err := CompileToGetDecoder(typ)
+	decoder, err := CompileToGetDecoderWithOption(typ, ctx.Option)
 	if err != nil {
 		return 0, err
 	}
@@ -404,6 +460,9 @@ func (d *interfaceDecoder) decodeEmptyInterface(ctx *RuntimeContext, cursor, dep
 	cursor = skipWhiteSpace(buf, cursor)
 	switch buf[cursor] {
 	case '{':
+		if (ctx.Option.Flags & OrderedObjectsOption) != 0 {
+			return d.decodeOrderedObject(ctx, cursor, depth, p)
+		}
 		var v map[string]interface{}
 		ptr := unsafe.Pointer(&v)
 		cursor, err := d.mapDecoder.Decode(ctx, cursor, depth, ptr)
@@ -422,6 +481,9 @@ func (d *interfaceDecoder) decodeEmptyInterface(ctx *RuntimeContext, cursor, dep
 		**(**interface{})(unsafe.Pointer(&p)) = v
 		return cursor, nil
 	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		if (ctx.Option.Flags & (UseNumberOption | IntegersAsInt64Option)) != 0 {
+			return d.decodeNumber(ctx, cursor, depth, p)
+		}
 		return d.floatDecoder.Decode(ctx, cursor, depth, p)
 	case '"':
 		var v string
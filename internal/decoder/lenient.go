@@ -0,0 +1,234 @@
+package decoder
+
+import (
+	"reflect"
+)
+
+// LenientRules selects the alternate forms of scalar values accepted with LenientOption.
+type LenientRules uint8
+
+const (
+	// LenientStringToNumber accepts a string containing a number for integer and float types, e.g. "42".
+	LenientStringToNumber LenientRules = 1 << iota
+	// LenientNumberToBool accepts 0 and 1 for bool types.
+	LenientNumberToBool
+	// LenientEmptyStringToNull treats "" like null for number and bool types.
+	LenientEmptyStringToNull
+	// LenientNumberToString accepts a number for string types and keeps its text, e.g. 42 as "42".
+	LenientNumberToString
+)
+
+func (o *Option) lenient(rule LenientRules) bool {
+	return (o.Flags&LenientOption) != 0 && (o.Lenient&rule) != 0
+}
+
+// isLenientNullKind reports whether "" is treated like null for kind with LenientEmptyStringToNull.
+func isLenientNullKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+type numberLiteralKind int
+
+const (
+	unsignedLiteral numberLiteralKind = iota
+	integerLiteral
+	floatLiteral
+)
+
+// isNumberLiteral reports whether b is a JSON number of the given kind.
+func isNumberLiteral(b []byte, kind numberLiteralKind) bool {
+	i := 0
+	if i < len(b) && b[i] == '-' {
+		if kind == unsignedLiteral {
+			return false
+		}
+		i++
+	}
+	switch {
+	case i < len(b) && b[i] == '0':
+		i++
+	case i < len(b) && '1' <= b[i] && b[i] <= '9':
+		for i < len(b) && numTable[b[i]] {
+			i++
+		}
+	default:
+		return false
+	}
+	if kind != floatLiteral {
+		return i == len(b)
+	}
+	if i < len(b) && b[i] == '.' {
+		i++
+		if i == len(b) || !numTable[b[i]] {
+			return false
+		}
+		for i < len(b) && numTable[b[i]] {
+			i++
+		}
+	}
+	if i < len(b) && (b[i] == 'e' || b[i] == 'E') {
+		i++
+		if i < len(b) && (b[i] == '+' || b[i] == '-') {
+			i++
+		}
+		if i == len(b) || !numTable[b[i]] {
+			return false
+		}
+		for i < len(b) && numTable[b[i]] {
+			i++
+		}
+	}
+	return i == len(b)
+}
+
+// lenientStringByte returns the contents of the JSON string at cursor and the cursor after it.
+// Strings with escape sequences are never coerced, so ok is false for them.
+func lenientStringByte(buf []byte, cursor int64) ([]byte, int64, bool) {
+	cursor = skipWhiteSpace(buf, cursor)
+	if buf[cursor] != '"' {
+		return nil, 0, false
+	}
+	start := cursor + 1
+	for cursor = start; ; cursor++ {
+		switch buf[cursor] {
+		case '"':
+			return buf[start:cursor], cursor + 1, true
+		case '\\', nul:
+			return nil, 0, false
+		}
+	}
+}
+
+// lenientStringStream is the Stream version of lenientStringByte.
+// The cursor is left unchanged when ok is false.
+func lenientStringStream(s *Stream) ([]byte, bool) {
+	if s.skipWhiteSpace() != '"' {
+		return nil, false
+	}
+	origin := s.cursor
+	start := s.cursor + 1
+	for {
+		s.cursor++
+		switch s.char() {
+		case '"':
+			str := s.buf[start:s.cursor]
+			s.cursor++
+			return str, true
+		case '\\':
+			s.cursor = origin
+			return nil, false
+		case nul:
+			if s.read() {
+				s.cursor--
+				continue
+			}
+			s.cursor = origin
+			return nil, false
+		}
+	}
+}
+
+// lenientNumberByte coerces the value at cursor into a number literal of the given kind
+// after the number decoder failed with err. A nil literal means that the value is treated as null.
+// err is returned if no lenient rule applies.
+func lenientNumberByte(ctx *RuntimeContext, cursor int64, kind numberLiteralKind, err error) ([]byte, int64, error) {
+	if (ctx.Option.Flags & LenientOption) == 0 {
+		return nil, 0, err
+	}
+	str, c, ok := lenientStringByte(ctx.Buf, cursor)
+	if !ok {
+		return nil, 0, err
+	}
+	if len(str) == 0 {
+		if ctx.Option.lenient(LenientEmptyStringToNull) {
+			return nil, c, nil
+		}
+		return nil, 0, err
+	}
+	if ctx.Option.lenient(LenientStringToNumber) && isNumberLiteral(str, kind) {
+		return str, c, nil
+	}
+	return nil, 0, err
+}
+
+// lenientNumberStream is the Stream version of lenientNumberByte.
+func lenientNumberStream(s *Stream, kind numberLiteralKind, err error) ([]byte, error) {
+	if (s.Option.Flags & LenientOption) == 0 {
+		return nil, err
+	}
+	origin := s.cursor
+	str, ok := lenientStringStream(s)
+	if !ok {
+		return nil, err
+	}
+	if len(str) == 0 {
+		if s.Option.lenient(LenientEmptyStringToNull) {
+			return nil, nil
+		}
+	} else if s.Option.lenient(LenientStringToNumber) && isNumberLiteral(str, kind) {
+		return str, nil
+	}
+	s.cursor = origin
+	return nil, err
+}
+
+// lenientNumberLiteralByte returns the JSON number at cursor and the cursor after it.
+func lenientNumberLiteralByte(buf []byte, cursor int64) ([]byte, int64, bool) {
+	cursor = skipWhiteSpace(buf, cursor)
+	start := cursor
+	for floatTable[buf[cursor]] {
+		cursor++
+	}
+	num := buf[start:cursor]
+	if !isNumberLiteral(num, floatLiteral) {
+		return nil, 0, false
+	}
+	return num, cursor, true
+}
+
+// lenientNumberLiteralStream is the Stream version of lenientNumberLiteralByte.
+// The cursor is left unchanged when ok is false.
+func lenientNumberLiteralStream(s *Stream) ([]byte, bool) {
+	s.skipWhiteSpace()
+	origin := s.cursor
+	if !floatTable[s.char()] {
+		return nil, false
+	}
+	num := floatBytes(s)
+	if !isNumberLiteral(num, floatLiteral) {
+		s.cursor = origin
+		return nil, false
+	}
+	return num, true
+}
+
+// emptyStringByte reports whether the value at cursor is "" and returns the cursor after it.
+func emptyStringByte(buf []byte, cursor int64) (int64, bool) {
+	cursor = skipWhiteSpace(buf, cursor)
+	if buf[cursor] == '"' && buf[cursor+1] == '"' {
+		return cursor + 2, true
+	}
+	return 0, false
+}
+
+// emptyStringStream reports whether the value at the cursor is "" and moves past it if so.
+func emptyStringStream(s *Stream) bool {
+	if s.skipWhiteSpace() != '"' {
+		return false
+	}
+	if s.cursor+1 >= s.length && !s.read() {
+		return false
+	}
+	if s.buf[s.cursor+1] == '"' {
+		s.cursor += 2
+		return true
+	}
+	return false
+}
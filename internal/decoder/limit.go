@@ -0,0 +1,66 @@
+package decoder
+
+import (
+	"github.com/goccy/go-json/internal/errors"
+)
+
+// Limits bounds the resources used while decoding untrusted input.
+// A zero value for a field means that the corresponding limit is disabled.
+type Limits struct {
+	MaxDepth      int64 // maximum nesting depth of objects and arrays
+	MaxStringLen  int64 // maximum length of a string value in bytes
+	MaxArrayLen   int64 // maximum number of elements in an array
+	MaxObjectKeys int64 // maximum number of keys in an object
+	MaxBytes      int64 // maximum number of bytes read from the input
+}
+
+func (o *Option) checkDepth(depth, cursor int64) error {
+	if (o.Flags&LimitsOption) == 0 || o.Limits.MaxDepth <= 0 {
+		return nil
+	}
+	if depth > o.Limits.MaxDepth {
+		return &errors.DepthLimitError{Max: o.Limits.MaxDepth, Offset: cursor}
+	}
+	return nil
+}
+
+func (o *Option) checkStringLen(length int, cursor int64) error {
+	if (o.Flags&LimitsOption) == 0 || o.Limits.MaxStringLen <= 0 {
+		return nil
+	}
+	if int64(length) > o.Limits.MaxStringLen {
+		return &errors.StringLimitError{Max: o.Limits.MaxStringLen, Len: int64(length), Offset: cursor}
+	}
+	return nil
+}
+
+func (o *Option) checkArrayLen(length int, cursor int64) error {
+	if (o.Flags&LimitsOption) == 0 || o.Limits.MaxArrayLen <= 0 {
+		return nil
+	}
+	if int64(length) > o.Limits.MaxArrayLen {
+		return &errors.ArrayLimitError{Max: o.Limits.MaxArrayLen, Offset: cursor}
+	}
+	return nil
+}
+
+func (o *Option) checkObjectKeys(num int, cursor int64) error {
+	if (o.Flags&LimitsOption) == 0 || o.Limits.MaxObjectKeys <= 0 {
+		return nil
+	}
+	if int64(num) > o.Limits.MaxObjectKeys {
+		return &errors.ObjectKeysLimitError{Max: o.Limits.MaxObjectKeys, Offset: cursor}
+	}
+	return nil
+}
+
+// CheckBytes reports an error if an input of n bytes exceeds the MaxBytes limit.
+func (o *Option) CheckBytes(n int64) error {
+	if (o.Flags&LimitsOption) == 0 || o.Limits.MaxBytes <= 0 {
+		return nil
+	}
+	if n > o.Limits.MaxBytes {
+		return &errors.BytesLimitError{Max: o.Limits.MaxBytes}
+	}
+	return nil
+}
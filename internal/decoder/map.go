@@ -1,6 +1,7 @@
 package decoder
 
 import (
+	"fmt"
 	"reflect"
 	"unsafe"
 
@@ -66,11 +67,31 @@ func (d *mapDecoder) mapassign(t *runtime.Type, m, k, v unsafe.Pointer) {
 	}
 }
 
+// duplicateKey returns the bytes used to identify the decoded key k.
+// String keys are compared by their unescaped value, other keys by their raw text.
+func (d *mapDecoder) duplicateKey(k unsafe.Pointer, raw []byte) []byte {
+	if d.keyType.Kind() == reflect.String {
+		return []byte(*(*string)(k))
+	}
+	return raw
+}
+
+// pathKey returns the decoded key k as it is shown in the JSON path of decode errors.
+func (d *mapDecoder) pathKey(k unsafe.Pointer) string {
+	if d.keyType.Kind() == reflect.String {
+		return *(*string)(k)
+	}
+	return fmt.Sprint(reflect.NewAt(runtime.RType2Type(d.keyType), k).Elem().Interface())
+}
+
 func (d *mapDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) error {
 	depth++
 	if depth > maxDecodeNestingDepth {
 		return errors.ErrExceededMaxDepth(s.char(), s.cursor)
 	}
+	if err := s.Option.checkDepth(depth, s.totalOffset()); err != nil {
+		return err
+	}
 
 	switch s.skipWhiteSpace() {
 	case 'n':
@@ -87,31 +108,75 @@ func (d *mapDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) erro
 	if mapValue == nil {
 		mapValue = makemap(d.mapType, 0)
 	}
+	start := s.cursor
 	s.cursor++
 	if s.equalChar('}') {
 		*(*unsafe.Pointer)(p) = mapValue
 		s.cursor++
 		return nil
 	}
+	validation := s.Option.validation
+	if validation != nil {
+		validation.BeginObject(start)
+	}
+	hasLimits := (s.Option.Flags & LimitsOption) != 0
+	keyNum := 0
+	var seenKeys *keySet
+	if (s.Option.Flags & RejectDuplicateKeysOption) != 0 {
+		seenKeys = &keySet{}
+	}
 	for {
+		if hasLimits {
+			keyNum++
+			if err := s.Option.checkObjectKeys(keyNum, s.totalOffset()); err != nil {
+				return err
+			}
+		}
+		s.skipWhiteSpace()
+		keyStart := s.cursor
+		keyOffset := s.totalOffset()
 		k := unsafe_New(d.keyType)
+		if validation != nil {
+			s.validateKey(validation, depth)
+		}
 		if err := d.keyDecoder.DecodeStream(s, depth, k); err != nil {
 			return err
 		}
+		if seenKeys != nil {
+			if err := seenKeys.addKey(d.duplicateKey(k, keyBytes(s.buf, keyStart, s.cursor)), keyOffset); err != nil {
+				return err
+			}
+		}
 		s.skipWhiteSpace()
 		if !s.equalChar(':') {
 			return errors.ErrExpected("colon after object key", s.totalOffset())
 		}
 		s.cursor++
 		v := unsafe_New(d.valueType)
+		valueStart := s.cursor
+		if validation != nil {
+			s.validateString(validation, depth)
+		}
+		errNum, offset := len(s.Option.Errors), s.totalOffset()
 		if err := d.valueDecoder.DecodeStream(s, depth, v); err != nil {
-			return err
+			if err := s.collectError(err, offset, depth); err != nil {
+				return errors.PrependPathKey(err, d.pathKey(k))
+			}
+		}
+		if validation != nil {
+			validation.Value(s.buf, valueStart, s.cursor)
+		}
+		if len(s.Option.Errors) > errNum {
+			s.Option.prependPathKey(errNum, d.pathKey(k))
 		}
 		d.mapassign(d.mapType, mapValue, k, v)
 		s.skipWhiteSpace()
 		if s.equalChar('}') {
 			**(**unsafe.Pointer)(unsafe.Pointer(&p)) = mapValue
 			s.cursor++
+			if validation != nil {
+				validation.End()
+			}
 			return nil
 		}
 		if !s.equalChar(',') {
@@ -127,6 +192,9 @@ func (d *mapDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.P
 	if depth > maxDecodeNestingDepth {
 		return 0, errors.ErrExceededMaxDepth(buf[cursor], cursor)
 	}
+	if err := ctx.Option.checkDepth(depth, cursor); err != nil {
+		return 0, err
+	}
 
 	cursor = skipWhiteSpace(buf, cursor)
 	buflen := int64(len(buf))
@@ -145,6 +213,7 @@ func (d *mapDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.P
 	default:
 		return 0, errors.ErrExpected("{ character for map value", cursor)
 	}
+	start := cursor
 	cursor++
 	cursor = skipWhiteSpace(buf, cursor)
 	mapValue := *(*unsafe.Pointer)(p)
@@ -156,27 +225,68 @@ func (d *mapDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.P
 		cursor++
 		return cursor, nil
 	}
+	validation := ctx.Option.validation
+	if validation != nil {
+		validation.BeginObject(start)
+	}
+	hasLimits := (ctx.Option.Flags & LimitsOption) != 0
+	keyNum := 0
+	var seenKeys *keySet
+	if (ctx.Option.Flags & RejectDuplicateKeysOption) != 0 {
+		seenKeys = &keySet{}
+	}
 	for {
+		if hasLimits {
+			keyNum++
+			if err := ctx.Option.checkObjectKeys(keyNum, cursor); err != nil {
+				return 0, err
+			}
+		}
 		k := unsafe_New(d.keyType)
+		if validation != nil {
+			validateKey(validation, buf, cursor, depth)
+		}
 		keyCursor, err := d.keyDecoder.Decode(ctx, cursor, depth, k)
 		if err != nil {
 			return 0, err
 		}
+		if seenKeys != nil {
+			keyStart := skipWhiteSpace(buf, cursor)
+			if err := seenKeys.addKey(d.duplicateKey(k, keyBytes(buf, keyStart, keyCursor)), keyStart); err != nil {
+				return 0, err
+			}
+		}
 		cursor = skipWhiteSpace(buf, keyCursor)
 		if buf[cursor] != ':' {
 			return 0, errors.ErrExpected("colon after object key", cursor)
 		}
 		cursor++
 		v := unsafe_New(d.valueType)
+		if validation != nil {
+			validateString(validation, buf, cursor, depth)
+		}
+		errNum := len(ctx.Option.Errors)
 		valueCursor, err := d.valueDecoder.Decode(ctx, cursor, depth, v)
 		if err != nil {
-			return 0, err
+			valueCursor, err = collectError(ctx, err, cursor, depth)
+			if err != nil {
+				return 0, errors.PrependPathKey(err, d.pathKey(k))
+			}
+		}
+		if len(ctx.Option.Errors) > errNum {
+			ctx.Option.prependPathKey(errNum, d.pathKey(k))
+		}
+		if validation != nil {
+			validation.Value(buf, cursor, valueCursor)
 		}
 		d.mapassign(d.mapType, mapValue, k, v)
 		cursor = skipWhiteSpace(buf, valueCursor)
 		if buf[cursor] == '}' {
 			**(**unsafe.Pointer)(unsafe.Pointer(&p)) = mapValue
 			cursor++
+			if validation != nil {
+				validation.End()
+			}
 			return cursor, nil
 		}
 		if buf[cursor] != ',' {
@@ -0,0 +1,179 @@
+package decoder
+
+import (
+	"unsafe"
+
+	"github.com/goccy/go-json/internal/errors"
+)
+
+// OrderedObject is implemented by the object type that keeps the order of the keys of
+// JSON objects decoded into interface{} with OrderedObjectsOption.
+type OrderedObject interface {
+	Set(key string, value interface{})
+}
+
+// NewOrderedObject creates the ordered objects stored in interface{} values of OrderedMap values,
+// whose objects are ordered without OrderedObjectsOption.
+var NewOrderedObject func() OrderedObject
+
+// decodeStreamOrderedObject decodes the JSON object at the cursor into a value created by Option.NewObject.
+func (d *interfaceDecoder) decodeStreamOrderedObject(s *Stream, depth int64, p unsafe.Pointer) error {
+	depth++
+	if depth > maxDecodeNestingDepth {
+		return errors.ErrExceededMaxDepth(s.char(), s.cursor)
+	}
+	if err := s.Option.checkDepth(depth, s.totalOffset()); err != nil {
+		return err
+	}
+	obj := s.Option.NewObject()
+	start := s.cursor
+	s.cursor++
+	if s.skipWhiteSpace() == '}' {
+		*(*interface{})(p) = obj
+		s.cursor++
+		return nil
+	}
+	validation := s.Option.validation
+	if validation != nil {
+		validation.BeginObject(start)
+	}
+	hasLimits := (s.Option.Flags & LimitsOption) != 0
+	keyNum := 0
+	var seenKeys *keySet
+	if (s.Option.Flags & RejectDuplicateKeysOption) != 0 {
+		seenKeys = &keySet{}
+	}
+	for {
+		if hasLimits {
+			keyNum++
+			if err := s.Option.checkObjectKeys(keyNum, s.totalOffset()); err != nil {
+				return err
+			}
+		}
+		s.skipWhiteSpace()
+		keyOffset := s.totalOffset()
+		var key string
+		if validation != nil {
+			s.validateKey(validation, depth)
+		}
+		if err := d.mapDecoder.keyDecoder.DecodeStream(s, depth, unsafe.Pointer(&key)); err != nil {
+			return err
+		}
+		if seenKeys != nil {
+			if err := seenKeys.addKey([]byte(key), keyOffset); err != nil {
+				return err
+			}
+		}
+		if s.skipWhiteSpace() != ':' {
+			return errors.ErrExpected("colon after object key", s.totalOffset())
+		}
+		s.cursor++
+		var v interface{}
+		valueStart := s.cursor
+		if validation != nil {
+			s.validateString(validation, depth)
+		}
+		if err := d.mapDecoder.valueDecoder.DecodeStream(s, depth, unsafe.Pointer(&v)); err != nil {
+			return errors.PrependPathKey(err, key)
+		}
+		if validation != nil {
+			validation.Value(s.buf, valueStart, s.cursor)
+		}
+		obj.Set(key, v)
+		switch s.skipWhiteSpace() {
+		case '}':
+			*(*interface{})(p) = obj
+			s.cursor++
+			if validation != nil {
+				validation.End()
+			}
+			return nil
+		case ',':
+			s.cursor++
+		default:
+			return errors.ErrExpected("comma after object value", s.totalOffset())
+		}
+	}
+}
+
+// decodeOrderedObject is the byte version of decodeStreamOrderedObject.
+func (d *interfaceDecoder) decodeOrderedObject(ctx *RuntimeContext, cursor, depth int64, p unsafe.Pointer) (int64, error) {
+	buf := ctx.Buf
+	depth++
+	if depth > maxDecodeNestingDepth {
+		return 0, errors.ErrExceededMaxDepth(buf[cursor], cursor)
+	}
+	if err := ctx.Option.checkDepth(depth, cursor); err != nil {
+		return 0, err
+	}
+	obj := ctx.Option.NewObject()
+	start := cursor
+	cursor = skipWhiteSpace(buf, cursor+1)
+	if buf[cursor] == '}' {
+		*(*interface{})(p) = obj
+		cursor++
+		return cursor, nil
+	}
+	validation := ctx.Option.validation
+	if validation != nil {
+		validation.BeginObject(start)
+	}
+	hasLimits := (ctx.Option.Flags & LimitsOption) != 0
+	keyNum := 0
+	var seenKeys *keySet
+	if (ctx.Option.Flags & RejectDuplicateKeysOption) != 0 {
+		seenKeys = &keySet{}
+	}
+	for {
+		if hasLimits {
+			keyNum++
+			if err := ctx.Option.checkObjectKeys(keyNum, cursor); err != nil {
+				return 0, err
+			}
+		}
+		var key string
+		if validation != nil {
+			validateKey(validation, buf, cursor, depth)
+		}
+		keyCursor, err := d.mapDecoder.keyDecoder.Decode(ctx, cursor, depth, unsafe.Pointer(&key))
+		if err != nil {
+			return 0, err
+		}
+		if seenKeys != nil {
+			if err := seenKeys.addKey([]byte(key), skipWhiteSpace(buf, cursor)); err != nil {
+				return 0, err
+			}
+		}
+		cursor = skipWhiteSpace(buf, keyCursor)
+		if buf[cursor] != ':' {
+			return 0, errors.ErrExpected("colon after object key", cursor)
+		}
+		cursor++
+		var v interface{}
+		if validation != nil {
+			validateString(validation, buf, cursor, depth)
+		}
+		valueCursor, err := d.mapDecoder.valueDecoder.Decode(ctx, cursor, depth, unsafe.Pointer(&v))
+		if err != nil {
+			return 0, errors.PrependPathKey(err, key)
+		}
+		if validation != nil {
+			validation.Value(buf, cursor, valueCursor)
+		}
+		obj.Set(key, v)
+		cursor = skipWhiteSpace(buf, valueCursor)
+		switch buf[cursor] {
+		case '}':
+			*(*interface{})(p) = obj
+			cursor++
+			if validation != nil {
+				validation.End()
+			}
+			return cursor, nil
+		case ',':
+			cursor++
+		default:
+			return 0, errors.ErrExpected("comma after object value", cursor)
+		}
+	}
+}
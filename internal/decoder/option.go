@@ -1,15 +1,158 @@
 package decoder
 
-import "context"
+import (
+	"context"
 
-type OptionFlags uint8
+	"github.com/goccy/go-json/internal/errors"
+	"github.com/goccy/go-json/internal/runtime"
+)
+
+type OptionFlags uint16
 
 const (
 	FirstWinOption OptionFlags = 1 << iota
 	ContextOption
+	LimitsOption
+	RejectDuplicateKeysOption
+	CollectErrorsOption
+	LenientOption
+	UseNumberOption
+	IntegersAsInt64Option
+	OrderedObjectsOption
+	SchemaOption
+	BytesFormatOption
+	RejectNullOption
+	TagKeyOption
 )
 
 type Option struct {
 	Flags   OptionFlags
 	Context context.Context
+	Limits  Limits
+	Lenient LenientRules
+	Errors  []*errors.UnmarshalTypeError // type errors skipped with CollectErrorsOption
+
+	// NewObject creates the values stored for JSON objects decoded into interface{} with OrderedObjectsOption.
+	NewObject func() OrderedObject
+
+	// Schema validates the values decoded with SchemaOption.
+	Schema SchemaValidator
+
+	// BytesFormat is the format of []byte values without a format tag with BytesFormatOption.
+	BytesFormat runtime.BytesFormat
+
+	// TagKey is the key of the struct tags read before json with TagKeyOption.
+	TagKey string
+
+	// validation validates the value being decoded with SchemaOption.
+	// The decoders report what they read to it if it is not nil.
+	validation SchemaValidation
+}
+
+// SchemaValidator validates JSON values against a schema.
+type SchemaValidator interface {
+	// NewValidation returns the validation of a value, which is reported to it by the decoders while they read the value.
+	NewValidation() SchemaValidation
+
+	// ValidateBuffer validates the JSON value at cursor in the nul terminated buf
+	// and returns the cursor after the value. The offsets of the returned errors are positions in buf.
+	ValidateBuffer(buf []byte, cursor int64) (int64, error)
+}
+
+// SchemaValidation validates a JSON value from what the decoders read while decoding it,
+// so that the value is not read again. The object and array decoders report their structure
+// with BeginObject, Key and End or BeginArray and End, and every member, element and
+// top-level value with Value once it is read. Values not reported as objects or arrays,
+// e.g. numbers, nulls or the values read by an Unmarshaler, are validated from their bytes.
+// Keys and strings are reported before they are decoded, since decoding unescapes them in the buffer.
+// Cursors are positions in the buffer being decoded.
+type SchemaValidation interface {
+	BeginObject(cursor int64)
+	BeginArray(cursor int64)
+	// Key reports the key of the next member of the current object; key is the quoted JSON string.
+	Key(key []byte, cursor int64)
+	// End reports the end of the current object or array.
+	End()
+	// Value reports the value between cursor and end in buf, which is the top-level value
+	// or the current member or element of the current object or array.
+	// A value reported again, e.g. a string reported before it is decoded, is validated once.
+	Value(buf []byte, cursor, end int64)
+	// Err returns the schema failures of the value or the error that stopped its validation.
+	Err() error
+}
+
+// BeginSchema starts the validation of the value decoded next from the nul terminated buf when SchemaOption is set.
+func (o *Option) BeginSchema(buf []byte) {
+	o.validation = nil
+	if o.Flags&SchemaOption != 0 {
+		o.validation = o.Schema.NewValidation()
+		validateString(o.validation, buf, 0, 0)
+	}
+}
+
+// EndSchema ends the validation of the value decoded between cursor and end in the nul terminated buf.
+// The returned errors are located in buf.
+func (o *Option) EndSchema(buf []byte, cursor, end int64) error {
+	v := o.validation
+	if v == nil {
+		return nil
+	}
+	o.validation = nil
+	v.Value(buf, cursor, end)
+	return locateSchemaErrors(v.Err(), buf[:len(buf)-1], 0, 1, 0)
+}
+
+// SchemaFailures validates the JSON value data when decoding it failed and SchemaOption is set.
+// Values that do not conform to the schema often fail to decode, so the schema failures are reported instead of the decode error.
+// It returns nil if the value conforms to the schema or cannot be validated.
+// data is the input as it was before decoding, which unescapes strings in the decoded buffer.
+func (o *Option) SchemaFailures(data []byte) error {
+	o.validation = nil
+	if o.Flags&SchemaOption == 0 {
+		return nil
+	}
+	buf := make([]byte, len(data)+1) // append nul byte to the end
+	copy(buf, data)
+	_, err := o.Schema.ValidateBuffer(buf, 0)
+	if _, ok := err.(errors.SchemaErrors); !ok {
+		return nil
+	}
+	return locateSchemaErrors(err, data, 0, 1, 0)
+}
+
+// validateKey reports the key at cursor in buf to v before it is decoded.
+func validateKey(v SchemaValidation, buf []byte, cursor, depth int64) {
+	cursor = skipWhiteSpace(buf, cursor)
+	if buf[cursor] != '"' {
+		return
+	}
+	if end, err := skipValue(buf, cursor, depth); err == nil {
+		v.Key(buf[cursor:end], cursor)
+	}
+}
+
+// validateString reports the value at cursor in buf to v before it is decoded if it is a string.
+func validateString(v SchemaValidation, buf []byte, cursor, depth int64) {
+	cursor = skipWhiteSpace(buf, cursor)
+	if buf[cursor] != '"' {
+		return
+	}
+	if end, err := skipValue(buf, cursor, depth); err == nil {
+		v.Value(buf, cursor, end)
+	}
+}
+
+// locateSchemaErrors fills in the locations of the errors returned by a SchemaValidator.
+func locateSchemaErrors(err error, buf []byte, base, line, lineStart int64) error {
+	if errs, ok := err.(errors.SchemaErrors); ok {
+		for _, e := range errs {
+			e.Offset += base
+			errors.Locate(e, buf, base, line, lineStart)
+		}
+		return errs
+	}
+	if e, ok := err.(*errors.SyntaxError); ok {
+		e.Offset += base
+	}
+	return errors.Locate(err, buf, base, line, lineStart)
 }
@@ -0,0 +1,76 @@
+package decoder
+
+import (
+	"reflect"
+	"unsafe"
+
+	"github.com/goccy/go-json/internal/runtime"
+)
+
+// optionalDecoder decodes into an Optional, recording whether the value was null.
+// It is only called when the value is present in the input, so Optional struct fields
+// whose key does not appear keep their state.
+type optionalDecoder struct {
+	dec       Decoder
+	valueType *runtime.Type
+	offset    uintptr // offset of the value in the Optional
+	zeroValue unsafe.Pointer
+}
+
+func newOptionalDecoder(dec Decoder, valueType *runtime.Type, offset uintptr) *optionalDecoder {
+	return &optionalDecoder{
+		dec:       dec,
+		valueType: valueType,
+		offset:    offset,
+		zeroValue: unsafe_New(valueType),
+	}
+}
+
+// withString returns a decoder that decodes the value of the Optional from a JSON string,
+// like the string tag option does for fields of string, number and bool types.
+// Values of other types are decoded as they are.
+func (d *optionalDecoder) withString(structName, fieldName string) *optionalDecoder {
+	if d.valueType.Kind() == reflect.Ptr || !isStringTagSupportedType(d.valueType) {
+		return d
+	}
+	dec := newWrappedStringDecoder(d.valueType, d.dec, structName, fieldName)
+	return newOptionalDecoder(dec, d.valueType, d.offset)
+}
+
+func (d *optionalDecoder) setNull(p unsafe.Pointer) {
+	*(*runtime.OptionalState)(p) = runtime.OptionalNull
+	typedmemmove(d.valueType, unsafe.Pointer(uintptr(p)+d.offset), d.zeroValue)
+}
+
+func (d *optionalDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) error {
+	if s.skipWhiteSpace() == 'n' {
+		if err := nullBytes(s); err != nil {
+			return err
+		}
+		d.setNull(p)
+		return nil
+	}
+	if err := d.dec.DecodeStream(s, depth, unsafe.Pointer(uintptr(p)+d.offset)); err != nil {
+		return err
+	}
+	*(*runtime.OptionalState)(p) = runtime.OptionalSet
+	return nil
+}
+
+func (d *optionalDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.Pointer) (int64, error) {
+	buf := ctx.Buf
+	cursor = skipWhiteSpace(buf, cursor)
+	if buf[cursor] == 'n' {
+		if err := validateNull(buf, cursor); err != nil {
+			return 0, err
+		}
+		d.setNull(p)
+		return cursor + 4, nil
+	}
+	c, err := d.dec.Decode(ctx, cursor, depth, unsafe.Pointer(uintptr(p)+d.offset))
+	if err != nil {
+		return 0, err
+	}
+	*(*runtime.OptionalState)(p) = runtime.OptionalSet
+	return c, nil
+}
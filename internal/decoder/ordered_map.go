@@ -0,0 +1,280 @@
+package decoder
+
+import (
+	"unsafe"
+
+	"github.com/goccy/go-json/internal/errors"
+	"github.com/goccy/go-json/internal/runtime"
+)
+
+// orderedMapDecoder decodes a JSON object into an OrderedMap, adding its keys in order.
+// Objects decoded into interface{} values of the OrderedMap are ordered as well.
+type orderedMapDecoder struct {
+	typ          *runtime.Type
+	valueType    *runtime.Type
+	keyDecoder   Decoder
+	valueDecoder Decoder
+	valuesOffset uintptr // offset of the values in the OrderedMap
+	indexOffset  uintptr // offset of the index of the keys in the OrderedMap
+	structName   string
+	fieldName    string
+}
+
+func newOrderedMapDecoder(typ, valueType *runtime.Type, valueDec Decoder, valuesOffset, indexOffset uintptr, structName, fieldName string) *orderedMapDecoder {
+	return &orderedMapDecoder{
+		typ:          typ,
+		valueType:    valueType,
+		keyDecoder:   newStringDecoder(structName, fieldName),
+		valueDecoder: valueDec,
+		valuesOffset: valuesOffset,
+		indexOffset:  indexOffset,
+		structName:   structName,
+		fieldName:    fieldName,
+	}
+}
+
+func (d *orderedMapDecoder) errUnmarshalType(value string, offset int64) *errors.UnmarshalTypeError {
+	return &errors.UnmarshalTypeError{
+		Value:  value,
+		Type:   runtime.RType2Type(d.typ),
+		Offset: offset,
+		Struct: d.structName,
+		Field:  d.fieldName,
+	}
+}
+
+// orderObjects makes the objects decoded into interface{} values become ordered objects,
+// and returns the function restoring opt.
+func orderObjects(opt *Option) func() {
+	if (opt.Flags&OrderedObjectsOption) != 0 || NewOrderedObject == nil {
+		return func() {}
+	}
+	newObject := opt.NewObject
+	opt.Flags |= OrderedObjectsOption
+	opt.NewObject = NewOrderedObject
+	return func() {
+		opt.Flags &^= OrderedObjectsOption
+		opt.NewObject = newObject
+	}
+}
+
+// set sets the value v for key in the OrderedMap at p like its Set method:
+// a new key is added after all existing keys, an existing key keeps its position.
+func (d *orderedMapDecoder) set(p unsafe.Pointer, key string, v unsafe.Pointer) {
+	index := (*map[string]int)(unsafe.Pointer(uintptr(p) + d.indexOffset))
+	values := (*sliceHeader)(unsafe.Pointer(uintptr(p) + d.valuesOffset))
+	size := d.valueType.Size()
+	if i, exists := (*index)[key]; exists {
+		typedmemmove(d.valueType, unsafe.Pointer(uintptr(values.data)+uintptr(i)*size), v)
+		return
+	}
+	if *index == nil {
+		*index = map[string]int{}
+	}
+	keys := (*runtime.OrderedMapKeys)(p)
+	(*index)[key] = len(*keys)
+	*keys = append(*keys, key)
+	if values.len == values.cap {
+		capacity := values.cap * 2
+		if capacity < defaultSliceCapacity {
+			capacity = defaultSliceCapacity
+		}
+		data := newArray(d.valueType, capacity)
+		copySlice(d.valueType, sliceHeader{data: data, len: values.len, cap: capacity}, *values)
+		values.data = data
+		values.cap = capacity
+	}
+	typedmemmove(d.valueType, unsafe.Pointer(uintptr(values.data)+uintptr(values.len)*size), v)
+	values.len++
+}
+
+func (d *orderedMapDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) error {
+	depth++
+	if depth > maxDecodeNestingDepth {
+		return errors.ErrExceededMaxDepth(s.char(), s.cursor)
+	}
+	if err := s.Option.checkDepth(depth, s.totalOffset()); err != nil {
+		return err
+	}
+
+	switch c := s.skipWhiteSpace(); c {
+	case 'n':
+		return nullBytes(s)
+	case '{':
+	case nul:
+		return errors.ErrUnexpectedEndOfJSON("object", s.totalOffset())
+	default:
+		return d.errUnmarshalType(jsonValueKind(c), s.totalOffset())
+	}
+	start := s.cursor
+	s.cursor++
+	if s.skipWhiteSpace() == '}' {
+		s.cursor++
+		return nil
+	}
+	defer orderObjects(s.Option)()
+	validation := s.Option.validation
+	if validation != nil {
+		validation.BeginObject(start)
+	}
+	hasLimits := (s.Option.Flags & LimitsOption) != 0
+	keyNum := 0
+	var seenKeys *keySet
+	if (s.Option.Flags & RejectDuplicateKeysOption) != 0 {
+		seenKeys = &keySet{}
+	}
+	for {
+		if hasLimits {
+			keyNum++
+			if err := s.Option.checkObjectKeys(keyNum, s.totalOffset()); err != nil {
+				return err
+			}
+		}
+		s.skipWhiteSpace()
+		keyOffset := s.totalOffset()
+		var key string
+		if validation != nil {
+			s.validateKey(validation, depth)
+		}
+		if err := d.keyDecoder.DecodeStream(s, depth, unsafe.Pointer(&key)); err != nil {
+			return err
+		}
+		if seenKeys != nil {
+			if err := seenKeys.addKey([]byte(key), keyOffset); err != nil {
+				return err
+			}
+		}
+		if s.skipWhiteSpace() != ':' {
+			return errors.ErrExpected("colon after object key", s.totalOffset())
+		}
+		s.cursor++
+		v := unsafe_New(d.valueType)
+		valueStart := s.cursor
+		if validation != nil {
+			s.validateString(validation, depth)
+		}
+		errNum, offset := len(s.Option.Errors), s.totalOffset()
+		if err := d.valueDecoder.DecodeStream(s, depth, v); err != nil {
+			if err := s.collectError(err, offset, depth); err != nil {
+				return errors.PrependPathKey(err, key)
+			}
+		}
+		if validation != nil {
+			validation.Value(s.buf, valueStart, s.cursor)
+		}
+		if len(s.Option.Errors) > errNum {
+			s.Option.prependPathKey(errNum, key)
+		}
+		d.set(p, key, v)
+		switch s.skipWhiteSpace() {
+		case '}':
+			s.cursor++
+			if validation != nil {
+				validation.End()
+			}
+			return nil
+		case ',':
+			s.cursor++
+		default:
+			return errors.ErrExpected("comma after object value", s.totalOffset())
+		}
+	}
+}
+
+func (d *orderedMapDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.Pointer) (int64, error) {
+	buf := ctx.Buf
+	depth++
+	if depth > maxDecodeNestingDepth {
+		return 0, errors.ErrExceededMaxDepth(buf[cursor], cursor)
+	}
+	if err := ctx.Option.checkDepth(depth, cursor); err != nil {
+		return 0, err
+	}
+
+	cursor = skipWhiteSpace(buf, cursor)
+	switch c := buf[cursor]; c {
+	case 'n':
+		if err := validateNull(buf, cursor); err != nil {
+			return 0, err
+		}
+		return cursor + 4, nil
+	case '{':
+	case nul:
+		return 0, errors.ErrUnexpectedEndOfJSON("object", cursor)
+	default:
+		return 0, d.errUnmarshalType(jsonValueKind(c), cursor)
+	}
+	start := cursor
+	cursor = skipWhiteSpace(buf, cursor+1)
+	if buf[cursor] == '}' {
+		return cursor + 1, nil
+	}
+	defer orderObjects(ctx.Option)()
+	validation := ctx.Option.validation
+	if validation != nil {
+		validation.BeginObject(start)
+	}
+	hasLimits := (ctx.Option.Flags & LimitsOption) != 0
+	keyNum := 0
+	var seenKeys *keySet
+	if (ctx.Option.Flags & RejectDuplicateKeysOption) != 0 {
+		seenKeys = &keySet{}
+	}
+	for {
+		if hasLimits {
+			keyNum++
+			if err := ctx.Option.checkObjectKeys(keyNum, cursor); err != nil {
+				return 0, err
+			}
+		}
+		var key string
+		if validation != nil {
+			validateKey(validation, buf, cursor, depth)
+		}
+		keyCursor, err := d.keyDecoder.Decode(ctx, cursor, depth, unsafe.Pointer(&key))
+		if err != nil {
+			return 0, err
+		}
+		if seenKeys != nil {
+			if err := seenKeys.addKey([]byte(key), skipWhiteSpace(buf, cursor)); err != nil {
+				return 0, err
+			}
+		}
+		cursor = skipWhiteSpace(buf, keyCursor)
+		if buf[cursor] != ':' {
+			return 0, errors.ErrExpected("colon after object key", cursor)
+		}
+		cursor++
+		v := unsafe_New(d.valueType)
+		if validation != nil {
+			validateString(validation, buf, cursor, depth)
+		}
+		errNum := len(ctx.Option.Errors)
+		valueCursor, err := d.valueDecoder.Decode(ctx, cursor, depth, v)
+		if err != nil {
+			valueCursor, err = collectError(ctx, err, cursor, depth)
+			if err != nil {
+				return 0, errors.PrependPathKey(err, key)
+			}
+		}
+		if len(ctx.Option.Errors) > errNum {
+			ctx.Option.prependPathKey(errNum, key)
+		}
+		if validation != nil {
+			validation.Value(buf, cursor, valueCursor)
+		}
+		d.set(p, key, v)
+		cursor = skipWhiteSpace(buf, valueCursor)
+		switch buf[cursor] {
+		case '}':
+			if validation != nil {
+				validation.End()
+			}
+			return cursor + 1, nil
+		case ',':
+			cursor++
+		default:
+			return 0, errors.ErrExpected("comma after object value", cursor)
+		}
+	}
+}
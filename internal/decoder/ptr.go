@@ -45,6 +45,10 @@ func (d *ptrDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) erro
 		*(*unsafe.Pointer)(p) = nil
 		return nil
 	}
+	if s.char() == '"' && s.Option.lenient(LenientEmptyStringToNull) && isLenientNullKind(d.typ.Kind()) && emptyStringStream(s) {
+		*(*unsafe.Pointer)(p) = nil
+		return nil
+	}
 	var newptr unsafe.Pointer
 	if *(*unsafe.Pointer)(p) == nil {
 		newptr = unsafe_New(d.typ)
@@ -71,6 +75,14 @@ func (d *ptrDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.P
 		cursor += 4
 		return cursor, nil
 	}
+	if buf[cursor] == '"' && ctx.Option.lenient(LenientEmptyStringToNull) && isLenientNullKind(d.typ.Kind()) {
+		if c, ok := emptyStringByte(buf, cursor); ok {
+			if p != nil {
+				*(*unsafe.Pointer)(p) = nil
+			}
+			return c, nil
+		}
+	}
 	var newptr unsafe.Pointer
 	if *(*unsafe.Pointer)(p) == nil {
 		newptr = unsafe_New(d.typ)
@@ -0,0 +1,341 @@
+package decoder
+
+import (
+	"github.com/goccy/go-json/internal/errors"
+)
+
+// Scanner reads the tokens of a JSON value from a nul terminated buffer
+// without decoding them into Go values. Unlike the skip functions used by the decoders,
+// it checks the full JSON grammar, so it can be used to inspect untrusted input.
+// Offsets in the returned errors are positions in Buf.
+type Scanner struct {
+	Buf    []byte
+	Cursor int64
+	// RejectDuplicateKeys makes Skip fail with a DuplicateKeyError
+	// when an object repeats a key, compared after unescaping.
+	RejectDuplicateKeys bool
+}
+
+// Next skips white space and returns the byte at the cursor.
+func (s *Scanner) Next() byte {
+	s.Cursor = skipWhiteSpace(s.Buf, s.Cursor)
+	return s.Buf[s.Cursor]
+}
+
+// Begin consumes the opening character c of an object or array.
+func (s *Scanner) Begin(c byte, depth int64) error {
+	if s.Next() != c {
+		return errors.ErrExpected(string(c)+" character", s.Cursor)
+	}
+	if depth > maxDecodeNestingDepth {
+		return errors.ErrExceededMaxDepth(c, s.Cursor)
+	}
+	s.Cursor++
+	return nil
+}
+
+// Element moves to the next element of the object or array closed by end and reports whether there is one.
+// It validates and consumes the comma before every element but the first, and the closing character at the end.
+func (s *Scanner) Element(first bool, end byte) (bool, error) {
+	c := s.Next()
+	if c == end && first {
+		s.Cursor++
+		return false, nil
+	}
+	if !first {
+		switch c {
+		case ',':
+			s.Cursor++
+			c = s.Next()
+		case end:
+			s.Cursor++
+			return false, nil
+		default:
+			return false, errAfterElement(c, end, s.Cursor)
+		}
+	}
+	if !isElementBeginning(c, end) {
+		return false, errElement(c, end, s.Cursor)
+	}
+	return true, nil
+}
+
+// The errors below are reported by both Scanner and the formatter of ValidateStream,
+// so that Validate and ValidReader describe the same problem with the same error.
+
+func scannerContext(end byte) string {
+	if end == '}' {
+		return "object"
+	}
+	return "array"
+}
+
+// isElementBeginning reports whether c can begin an element of the object or array closed by end.
+// Bytes that cannot begin any value are left to the value itself.
+func isElementBeginning(c, end byte) bool {
+	switch c {
+	case nul:
+		return false
+	case '"':
+		return true
+	}
+	return end == ']' && c != ']' && c != '}' && c != ','
+}
+
+// errElement reports c at offset, where an element of the object or array closed by end must begin.
+func errElement(c, end byte, offset int64) error {
+	switch {
+	case c == nul:
+		return errors.ErrUnexpectedEndOfJSON(scannerContext(end), offset)
+	case end == '}':
+		return errors.ErrExpected("object key", offset)
+	}
+	return errors.ErrInvalidBeginningOfValue(c, offset)
+}
+
+// errAfterElement reports c at offset, where a comma or end must follow an element of the object or array closed by end.
+func errAfterElement(c, end byte, offset int64) error {
+	if c == nul {
+		return errors.ErrUnexpectedEndOfJSON(scannerContext(end), offset)
+	}
+	return errors.ErrExpected("comma after "+scannerContext(end)+" element", offset)
+}
+
+// errInvalidCharacter reports c at offset, which cannot continue the value described by context,
+// or the end of the input if isEnd is true.
+func errInvalidCharacter(c byte, isEnd bool, context string, offset int64) error {
+	if isEnd {
+		return errors.ErrUnexpectedEndOfJSON(context, offset)
+	}
+	return errors.ErrInvalidCharacter(c, context, offset)
+}
+
+// errEscape reports c at offset, which cannot continue an escape sequence in a string,
+// or the end of the input if isEnd is true.
+func errEscape(c byte, isEnd bool, offset int64) error {
+	if isEnd {
+		return errors.ErrUnexpectedEndOfJSON("string", offset)
+	}
+	return errors.ErrInvalidCharacter(c, "escape sequence", offset)
+}
+
+// Key reads an object key and the colon after it.
+// It returns the unescaped key, which may share memory with Buf.
+func (s *Scanner) Key() ([]byte, error) {
+	if s.Next() != '"' {
+		return nil, errors.ErrExpected("object key", s.Cursor)
+	}
+	key, err := s.String()
+	if err != nil {
+		return nil, err
+	}
+	if s.Next() != ':' {
+		return nil, errors.ErrExpected("colon after object key", s.Cursor)
+	}
+	s.Cursor++
+	return key, nil
+}
+
+// String reads the string at the cursor and returns its unescaped content,
+// which may share memory with Buf.
+func (s *Scanner) String() ([]byte, error) {
+	buf := s.Buf
+	start := s.Next()
+	if start != '"' {
+		return nil, errors.ErrExpected("string", s.Cursor)
+	}
+	begin := s.Cursor
+	cursor := begin + 1
+	escaped := false
+	for {
+		switch c := buf[cursor]; {
+		case c == '"':
+			s.Cursor = cursor + 1
+			if !escaped {
+				return buf[begin+1 : cursor], nil
+			}
+			str, ok := unquoteBytes(buf[begin:s.Cursor])
+			if !ok {
+				return nil, errors.ErrSyntax("json: invalid escape sequence in string", begin)
+			}
+			return str, nil
+		case c == '\\':
+			escaped = true
+			cursor++
+			switch buf[cursor] {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+			case 'u':
+				for i := int64(1); i <= 4; i++ {
+					if !isHexDigit(buf[cursor+i]) {
+						return nil, errEscape(buf[cursor+i], s.isEnd(cursor+i), cursor+i)
+					}
+				}
+				cursor += 4
+			default:
+				return nil, errEscape(buf[cursor], s.isEnd(cursor), cursor)
+			}
+		case c < ' ':
+			return nil, errInvalidCharacter(c, s.isEnd(cursor), "string", cursor)
+		}
+		cursor++
+	}
+}
+
+// Number reads the number at the cursor and returns its literal.
+func (s *Scanner) Number() ([]byte, error) {
+	buf := s.Buf
+	start := skipWhiteSpace(buf, s.Cursor)
+	cursor := start
+	if buf[cursor] == '-' {
+		cursor++
+	}
+	switch {
+	case buf[cursor] == '0':
+		cursor++
+	case '1' <= buf[cursor] && buf[cursor] <= '9':
+		cursor = skipDigits(buf, cursor)
+	default:
+		return nil, s.errInvalidCharacter("number", cursor)
+	}
+	if buf[cursor] == '.' {
+		cursor++
+		if !isDigit(buf[cursor]) {
+			return nil, s.errInvalidCharacter("number", cursor)
+		}
+		cursor = skipDigits(buf, cursor)
+	}
+	if buf[cursor] == 'e' || buf[cursor] == 'E' {
+		cursor++
+		if buf[cursor] == '+' || buf[cursor] == '-' {
+			cursor++
+		}
+		if !isDigit(buf[cursor]) {
+			return nil, s.errInvalidCharacter("number", cursor)
+		}
+		cursor = skipDigits(buf, cursor)
+	}
+	s.Cursor = cursor
+	return buf[start:cursor], nil
+}
+
+// errInvalidCharacter reports the byte at cursor, which cannot continue the value described by context,
+// or the end of the input if the cursor reached it.
+func (s *Scanner) errInvalidCharacter(context string, cursor int64) error {
+	return errInvalidCharacter(s.Buf[cursor], s.isEnd(cursor), context, cursor)
+}
+
+// isEnd reports whether cursor is at the nul byte that terminates Buf.
+func (s *Scanner) isEnd(cursor int64) bool {
+	return cursor >= int64(len(s.Buf))-1
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func skipDigits(buf []byte, cursor int64) int64 {
+	for isDigit(buf[cursor]) {
+		cursor++
+	}
+	return cursor
+}
+
+// Literal reads the true, false or null literal at the cursor and returns its first byte.
+func (s *Scanner) Literal() (byte, error) {
+	c := s.Next()
+	var err error
+	switch c {
+	case 't':
+		err = s.literal("true")
+	case 'f':
+		err = s.literal("false")
+	case 'n':
+		err = s.literal("null")
+	case nul:
+		return 0, errors.ErrUnexpectedEndOfJSON("value", s.Cursor)
+	default:
+		return 0, errors.ErrInvalidBeginningOfValue(c, s.Cursor)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return c, nil
+}
+
+func (s *Scanner) literal(literal string) error {
+	for i := 0; i < len(literal); i++ {
+		if s.Buf[s.Cursor] != literal[i] {
+			return s.errInvalidCharacter(literal, s.Cursor)
+		}
+		s.Cursor++
+	}
+	return nil
+}
+
+// Skip reads the value at the cursor without returning it.
+func (s *Scanner) Skip(depth int64) error {
+	switch s.Next() {
+	case '{':
+		if err := s.Begin('{', depth+1); err != nil {
+			return err
+		}
+		var seenKeys keySet
+		for first := true; ; first = false {
+			more, err := s.Element(first, '}')
+			if err != nil {
+				return err
+			}
+			if !more {
+				return nil
+			}
+			keyOffset := s.Cursor
+			key, err := s.Key()
+			if err != nil {
+				return err
+			}
+			if s.RejectDuplicateKeys {
+				if err := seenKeys.addKey(key, keyOffset); err != nil {
+					return err
+				}
+			}
+			if err := s.Skip(depth + 1); err != nil {
+				return err
+			}
+		}
+	case '[':
+		if err := s.Begin('[', depth+1); err != nil {
+			return err
+		}
+		for first := true; ; first = false {
+			more, err := s.Element(first, ']')
+			if err != nil {
+				return err
+			}
+			if !more {
+				return nil
+			}
+			if err := s.Skip(depth + 1); err != nil {
+				return err
+			}
+		}
+	case '"':
+		_, err := s.String()
+		return err
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		_, err := s.Number()
+		return err
+	}
+	_, err := s.Literal()
+	return err
+}
+
+// SkipValue skips the value at cursor in the nul terminated buf the same way the decoders skip
+// unknown fields, and returns the cursor after it. Unlike Scanner.Skip,
+// it only checks as much of the grammar as is needed to find the end of the value.
+func SkipValue(buf []byte, cursor, depth int64) (int64, error) {
+	return skipValue(buf, cursor, depth)
+}
@@ -89,9 +89,9 @@ func newArray(*runtime.Type, int) unsafe.Pointer
 //go:linkname typedmemmove reflect.typedmemmove
 func typedmemmove(t *runtime.Type, dst, src unsafe.Pointer)
 
-func (d *sliceDecoder) errNumber(offset int64) *errors.UnmarshalTypeError {
+func (d *sliceDecoder) typeError(typeName string, offset int64) *errors.UnmarshalTypeError {
 	return &errors.UnmarshalTypeError{
-		Value:  "number",
+		Value:  typeName,
 		Type:   reflect.SliceOf(runtime.RType2Type(d.elemType)),
 		Struct: d.structName,
 		Field:  d.fieldName,
@@ -104,6 +104,9 @@ func (d *sliceDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) er
 	if depth > maxDecodeNestingDepth {
 		return errors.ErrExceededMaxDepth(s.char(), s.cursor)
 	}
+	if err := s.Option.checkDepth(depth, s.totalOffset()); err != nil {
+		return err
+	}
 
 	for {
 		switch s.char() {
@@ -117,6 +120,7 @@ func (d *sliceDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) er
 			typedmemmove(sliceType, p, nilSlice)
 			return nil
 		case '[':
+			start := s.cursor
 			s.cursor++
 			if s.skipWhiteSpace() == ']' {
 				dst := (*sliceHeader)(p)
@@ -128,6 +132,10 @@ func (d *sliceDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) er
 				s.cursor++
 				return nil
 			}
+			validation := s.Option.validation
+			if validation != nil {
+				validation.BeginArray(start)
+			}
 			idx := 0
 			slice := d.newSlice((*sliceHeader)(p))
 			srcLen := slice.len
@@ -153,8 +161,21 @@ func (d *sliceDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) er
 					}
 				}
 
+				valueStart := s.cursor
+				if validation != nil {
+					s.validateString(validation, depth)
+				}
+				errNum, offset := len(s.Option.Errors), s.totalOffset()
 				if err := d.valueDecoder.DecodeStream(s, depth, ep); err != nil {
-					return err
+					if err := s.collectError(err, offset, depth); err != nil {
+						return errors.PrependPathIndex(err, idx)
+					}
+				}
+				if len(s.Option.Errors) > errNum {
+					s.Option.prependPathIndex(errNum, idx)
+				}
+				if validation != nil {
+					validation.Value(s.buf, valueStart, s.cursor)
 				}
 				s.skipWhiteSpace()
 			RETRY:
@@ -172,9 +193,18 @@ func (d *sliceDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) er
 					copySlice(d.elemType, *dst, *slice)
 					d.releaseSlice(slice)
 					s.cursor++
+					if validation != nil {
+						validation.End()
+					}
 					return nil
 				case ',':
 					idx++
+					if err := s.Option.checkArrayLen(idx+1, s.totalOffset()); err != nil {
+						slice.cap = capacity
+						slice.data = data
+						d.releaseSlice(slice)
+						return err
+					}
 				case nul:
 					if s.read() {
 						goto RETRY
@@ -191,8 +221,8 @@ func (d *sliceDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) er
 				}
 				s.cursor++
 			}
-		case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-			return d.errNumber(s.totalOffset())
+		case '"', '{', 't', 'f', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			return d.typeError(typeName(s.char()), s.totalOffset())
 		case nul:
 			if s.read() {
 				continue
@@ -212,6 +242,9 @@ func (d *sliceDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe
 	if depth > maxDecodeNestingDepth {
 		return 0, errors.ErrExceededMaxDepth(buf[cursor], cursor)
 	}
+	if err := ctx.Option.checkDepth(depth, cursor); err != nil {
+		return 0, err
+	}
 
 	for {
 		switch buf[cursor] {
@@ -226,6 +259,7 @@ func (d *sliceDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe
 			typedmemmove(sliceType, p, nilSlice)
 			return cursor, nil
 		case '[':
+			start := cursor
 			cursor++
 			cursor = skipWhiteSpace(buf, cursor)
 			if buf[cursor] == ']' {
@@ -238,6 +272,10 @@ func (d *sliceDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe
 				cursor++
 				return cursor, nil
 			}
+			validation := ctx.Option.validation
+			if validation != nil {
+				validation.BeginArray(start)
+			}
 			idx := 0
 			slice := d.newSlice((*sliceHeader)(p))
 			srcLen := slice.len
@@ -261,9 +299,22 @@ func (d *sliceDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe
 						typedmemmove(d.elemType, ep, unsafe_New(d.elemType))
 					}
 				}
+				if validation != nil {
+					validateString(validation, buf, cursor, depth)
+				}
+				errNum := len(ctx.Option.Errors)
 				c, err := d.valueDecoder.Decode(ctx, cursor, depth, ep)
 				if err != nil {
-					return 0, err
+					c, err = collectError(ctx, err, cursor, depth)
+					if err != nil {
+						return 0, errors.PrependPathIndex(err, idx)
+					}
+				}
+				if len(ctx.Option.Errors) > errNum {
+					ctx.Option.prependPathIndex(errNum, idx)
+				}
+				if validation != nil {
+					validation.Value(buf, cursor, c)
 				}
 				cursor = c
 				cursor = skipWhiteSpace(buf, cursor)
@@ -281,9 +332,18 @@ func (d *sliceDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe
 					copySlice(d.elemType, *dst, *slice)
 					d.releaseSlice(slice)
 					cursor++
+					if validation != nil {
+						validation.End()
+					}
 					return cursor, nil
 				case ',':
 					idx++
+					if err := ctx.Option.checkArrayLen(idx+1, cursor); err != nil {
+						slice.cap = capacity
+						slice.data = data
+						d.releaseSlice(slice)
+						return 0, err
+					}
 				default:
 					slice.cap = capacity
 					slice.data = data
@@ -292,8 +352,8 @@ func (d *sliceDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe
 				}
 				cursor++
 			}
-		case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
-			return 0, d.errNumber(cursor)
+		case '"', '{', 't', 'f', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			return 0, d.typeError(typeName(buf[cursor]), cursor)
 		default:
 			return 0, errors.ErrUnexpectedEndOfJSON("slice", cursor)
 		}
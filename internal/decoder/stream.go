@@ -23,6 +23,11 @@ type Stream struct {
 	cursor                int64
 	filledBuffer          bool
 	allRead               bool
+	limitErr              error
+	lines                 int64  // number of newlines before offset
+	lineStart             int64  // offset of the beginning of the line containing offset
+	unionKey              string // discriminator key that the next struct decoded accepts without a field for it
+	schemaCursor          int64  // cursor of the value validated against a schema
 	UseNumber             bool
 	DisallowUnknownFields bool
 	Option                *Option
@@ -41,6 +46,18 @@ func (s *Stream) TotalOffset() int64 {
 	return s.totalOffset()
 }
 
+// LimitError returns the error recorded when reading stopped
+// because the input exceeded the MaxBytes decode limit.
+func (s *Stream) LimitError() error {
+	return s.limitErr
+}
+
+// LocateError fills in the line, column and snippet of a decode error
+// using the part of the input that is still buffered.
+func (s *Stream) LocateError(err error) error {
+	return errors.Locate(err, s.buf[:s.length], s.offset, s.lines+1, s.lineStart)
+}
+
 func (s *Stream) Buffered() io.Reader {
 	buflen := int64(len(s.buf))
 	for i := s.cursor; i < buflen; i++ {
@@ -71,6 +88,71 @@ func (s *Stream) PrepareForDecode() error {
 	return nil
 }
 
+// BeginSchema starts the validation of the value decoded next when SchemaOption is set.
+func (s *Stream) BeginSchema() {
+	s.Option.validation = nil
+	s.schemaCursor = s.cursor
+	if s.Option.Flags&SchemaOption != 0 {
+		s.Option.validation = s.Option.Schema.NewValidation()
+		s.validateString(s.Option.validation, 0)
+	}
+}
+
+// EndSchema ends the validation of the decoded value.
+// The returned errors are located in the input.
+func (s *Stream) EndSchema() error {
+	v := s.Option.validation
+	if v == nil {
+		return nil
+	}
+	s.Option.validation = nil
+	v.Value(s.buf, s.schemaCursor, s.cursor)
+	return locateSchemaErrors(v.Err(), s.buf[:s.length], s.offset, s.lines+1, s.lineStart)
+}
+
+// SchemaFailures validates the value that failed to decode when SchemaOption is set,
+// like Option.SchemaFailures. The rest of the value is read into the buffer to validate it,
+// unless decoding failed because the input exceeded a limit.
+func (s *Stream) SchemaFailures() error {
+	s.Option.validation = nil
+	if s.Option.Flags&SchemaOption == 0 || s.limitErr != nil {
+		return nil
+	}
+	s.cursor = s.schemaCursor
+	if err := s.skipValue(0); err != nil {
+		return nil
+	}
+	_, err := s.Option.Schema.ValidateBuffer(s.buf, s.schemaCursor)
+	if _, ok := err.(errors.SchemaErrors); !ok {
+		return nil
+	}
+	return locateSchemaErrors(err, s.buf[:s.length], s.offset, s.lines+1, s.lineStart)
+}
+
+// validateKey reports the key at the cursor to v before it is decoded, like validateKey.
+func (s *Stream) validateKey(v SchemaValidation, depth int64) {
+	if s.skipWhiteSpace() != '"' {
+		return
+	}
+	start := s.cursor
+	if err := s.skipValue(depth); err == nil {
+		v.Key(s.buf[start:s.cursor], start)
+	}
+	s.cursor = start
+}
+
+// validateString reports the value at the cursor to v before it is decoded if it is a string, like validateString.
+func (s *Stream) validateString(v SchemaValidation, depth int64) {
+	if s.skipWhiteSpace() != '"' {
+		return
+	}
+	start := s.cursor
+	if err := s.skipValue(depth); err == nil {
+		v.Value(s.buf, start, s.cursor)
+	}
+	s.cursor = start
+}
+
 func (s *Stream) totalOffset() int64 {
 	return s.offset + s.cursor
 }
@@ -125,6 +207,57 @@ func (s *Stream) More() bool {
 	return true
 }
 
+// ArrayBegin consumes the opening bracket of the array at the cursor.
+// Like PrepareForDecode, it skips a preceding comma or colon and returns io.EOF at the end of the input.
+func (s *Stream) ArrayBegin() error {
+	if err := s.PrepareForDecode(); err != nil {
+		return err
+	}
+	if s.skipWhiteSpace() != '[' {
+		return errors.ErrExpected("[ character for array", s.totalOffset())
+	}
+	s.cursor++
+	return nil
+}
+
+// ArrayElement moves to the next element of the array opened by ArrayBegin and reports whether there is one.
+// It validates and consumes the comma before every element but the first, and the closing bracket at the end.
+func (s *Stream) ArrayElement(first bool) (bool, error) {
+	c := s.skipWhiteSpace()
+	if c == ']' {
+		s.cursor++
+		return false, nil
+	}
+	if !first {
+		switch c {
+		case ',':
+			s.cursor++
+			c = s.skipWhiteSpace()
+		case nul:
+			return false, errors.ErrUnexpectedEndOfJSON("array", s.totalOffset())
+		default:
+			return false, errors.ErrExpected("comma after array element", s.totalOffset())
+		}
+	}
+	switch c {
+	case nul:
+		return false, errors.ErrUnexpectedEndOfJSON("array", s.totalOffset())
+	case ']', ',':
+		return false, errors.ErrInvalidBeginningOfValue(c, s.totalOffset())
+	}
+	return true, nil
+}
+
+// SkipValue skips the value at the cursor and discards the consumed input.
+func (s *Stream) SkipValue() error {
+	s.skipWhiteSpace()
+	if err := s.skipValue(0); err != nil {
+		return err
+	}
+	s.reset()
+	return nil
+}
+
 func (s *Stream) Token() (interface{}, error) {
 	for {
 		c := s.char()
@@ -139,7 +272,7 @@ func (s *Stream) Token() (interface{}, error) {
 		case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
 			bytes := floatBytes(s)
 			str := *(*string)(unsafe.Pointer(&bytes))
-			if s.UseNumber {
+			if s.UseNumber || (s.Option.Flags&UseNumberOption) != 0 {
 				return json.Number(str), nil
 			}
 			f64, err := strconv.ParseFloat(str, 64)
@@ -181,13 +314,28 @@ END:
 	return nil, io.EOF
 }
 
+// reset discards the input before the cursor.
+// The value being validated against a schema is kept, so that the schema failures can be located in it.
 func (s *Stream) reset() {
+	if s.Option.validation != nil {
+		return
+	}
+	s.countLines()
 	s.offset += s.cursor
 	s.buf = s.buf[s.cursor:]
 	s.length -= s.cursor
 	s.cursor = 0
 }
 
+// countLines records the newlines of the consumed input before the cursor.
+func (s *Stream) countLines() {
+	consumed := s.buf[:s.cursor]
+	if n := bytes.Count(consumed, []byte{'\n'}); n > 0 {
+		s.lines += int64(n)
+		s.lineStart = s.offset + int64(bytes.LastIndexByte(consumed, '\n')) + 1
+	}
+}
+
 func (s *Stream) readBuf() []byte {
 	if s.filledBuffer {
 		s.bufSize *= 2
@@ -216,6 +364,11 @@ func (s *Stream) read() bool {
 	buf[last] = nul
 	n, err := s.r.Read(buf[:last])
 	s.length += int64(n)
+	if limitErr := s.Option.CheckBytes(s.offset + s.length); limitErr != nil {
+		s.limitErr = limitErr
+		s.allRead = true
+		return false
+	}
 	if n == last {
 		s.filledBuffer = true
 	} else {
@@ -273,14 +426,15 @@ func (s *Stream) skipObject(depth int64) error {
 			depth--
 		case '"':
 			for {
-				cursor++
+				cursor = scanString(s.buf, cursor+1)
 				switch char(p, cursor) {
 				case '\\':
 					cursor++
 					if char(p, cursor) == nul {
 						s.cursor = cursor
 						if s.read() {
-							_, cursor, p = s.statForRetry()
+							// the next scan starts after the escaped character at the cursor
+							_, cursor, p = s.stat()
 							continue
 						}
 						return errors.ErrUnexpectedEndOfJSON("string of object", cursor)
@@ -303,6 +457,11 @@ func (s *Stream) skipObject(depth int64) error {
 				continue
 			}
 			return errors.ErrUnexpectedEndOfJSON("object of object", cursor)
+		default:
+			if cursor++; !structural[char(p, cursor)] {
+				cursor = scanStructural(s.buf, cursor)
+			}
+			continue
 		}
 	SWITCH_OUT:
 		cursor++
@@ -336,14 +495,15 @@ func (s *Stream) skipArray(depth int64) error {
 			depth--
 		case '"':
 			for {
-				cursor++
+				cursor = scanString(s.buf, cursor+1)
 				switch char(p, cursor) {
 				case '\\':
 					cursor++
 					if char(p, cursor) == nul {
 						s.cursor = cursor
 						if s.read() {
-							_, cursor, p = s.statForRetry()
+							// the next scan starts after the escaped character at the cursor
+							_, cursor, p = s.stat()
 							continue
 						}
 						return errors.ErrUnexpectedEndOfJSON("string of object", cursor)
@@ -366,6 +526,11 @@ func (s *Stream) skipArray(depth int64) error {
 				continue
 			}
 			return errors.ErrUnexpectedEndOfJSON("array of object", cursor)
+		default:
+			if cursor++; !structural[char(p, cursor)] {
+				cursor = scanStructural(s.buf, cursor)
+			}
+			continue
 		}
 	SWITCH_OUT:
 		cursor++
@@ -394,14 +559,15 @@ func (s *Stream) skipValue(depth int64) error {
 			return s.skipArray(depth + 1)
 		case '"':
 			for {
-				cursor++
+				cursor = scanString(s.buf, cursor+1)
 				switch char(p, cursor) {
 				case '\\':
 					cursor++
 					if char(p, cursor) == nul {
 						s.cursor = cursor
 						if s.read() {
-							_, cursor, p = s.statForRetry()
+							// the next scan starts after the escaped character at the cursor
+							_, cursor, p = s.stat()
 							continue
 						}
 						return errors.ErrUnexpectedEndOfJSON("value of string", s.totalOffset())
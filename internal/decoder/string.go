@@ -15,6 +15,7 @@ import (
 type stringDecoder struct {
 	structName string
 	fieldName  string
+	isMapKey   bool
 }
 
 func newStringDecoder(structName, fieldName string) *stringDecoder {
@@ -24,6 +25,14 @@ func newStringDecoder(structName, fieldName string) *stringDecoder {
 	}
 }
 
+// newMapKeyStringDecoder creates a stringDecoder for object keys,
+// which are never coerced from other types.
+func newMapKeyStringDecoder(structName, fieldName string) *stringDecoder {
+	dec := newStringDecoder(structName, fieldName)
+	dec.isMapKey = true
+	return dec
+}
+
 func (d *stringDecoder) errUnmarshalType(typeName string, offset int64) *errors.UnmarshalTypeError {
 	return &errors.UnmarshalTypeError{
 		Value:  typeName,
@@ -37,11 +46,21 @@ func (d *stringDecoder) errUnmarshalType(typeName string, offset int64) *errors.
 func (d *stringDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) error {
 	bytes, err := d.decodeStreamByte(s)
 	if err != nil {
-		return err
+		if d.isMapKey || !s.Option.lenient(LenientNumberToString) {
+			return err
+		}
+		num, ok := lenientNumberLiteralStream(s)
+		if !ok {
+			return err
+		}
+		bytes = num
 	}
 	if bytes == nil {
 		return nil
 	}
+	if err := s.Option.checkStringLen(len(bytes), s.totalOffset()); err != nil {
+		return err
+	}
 	**(**string)(unsafe.Pointer(&p)) = *(*string)(unsafe.Pointer(&bytes))
 	s.reset()
 	return nil
@@ -50,11 +69,21 @@ func (d *stringDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) e
 func (d *stringDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.Pointer) (int64, error) {
 	bytes, c, err := d.decodeByte(ctx.Buf, cursor)
 	if err != nil {
-		return 0, err
+		if d.isMapKey || !ctx.Option.lenient(LenientNumberToString) {
+			return 0, err
+		}
+		num, numCursor, ok := lenientNumberLiteralByte(ctx.Buf, cursor)
+		if !ok {
+			return 0, err
+		}
+		bytes, c = num, numCursor
 	}
 	if bytes == nil {
 		return c, nil
 	}
+	if err := ctx.Option.checkStringLen(len(bytes), cursor); err != nil {
+		return 0, err
+	}
 	cursor = c
 	**(**string)(unsafe.Pointer(&p)) = *(*string)(unsafe.Pointer(&bytes))
 	return cursor, nil
@@ -99,7 +128,7 @@ func decodeUnicodeRune(s *Stream, p unsafe.Pointer) (rune, int64, unsafe.Pointer
 	const defaultOffset = 5
 	const surrogateOffset = 11
 
-	if s.cursor+defaultOffset >= s.length {
+	for s.cursor+defaultOffset >= s.length {
 		if !s.read() {
 			return rune(0), 0, nil, errors.ErrInvalidCharacter(s.char(), "escaped string", s.totalOffset())
 		}
@@ -108,8 +137,7 @@ func decodeUnicodeRune(s *Stream, p unsafe.Pointer) (rune, int64, unsafe.Pointer
 
 	r := unicodeToRune(s.buf[s.cursor+1 : s.cursor+defaultOffset])
 	if utf16.IsSurrogate(r) {
-		if s.cursor+surrogateOffset >= s.length {
-			s.read()
+		for s.cursor+surrogateOffset >= s.length && s.read() {
 			p = s.bufptr()
 		}
 		if s.cursor+surrogateOffset >= s.length || s.buf[s.cursor+defaultOffset] != '\\' || s.buf[s.cursor+defaultOffset+1] != 'u' {
@@ -210,7 +238,9 @@ func stringBytes(s *Stream) ([]byte, error) {
 			0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5A, 0x5B /*0x5C,*/, 0x5D, 0x5E, 0x5F, // 0x50-0x5F
 			0x60, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69, 0x6A, 0x6B, 0x6C, 0x6D, 0x6E, 0x6F, // 0x60-0x6F
 			0x70, 0x71, 0x72, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79, 0x7A, 0x7B, 0x7C, 0x7D, 0x7E, 0x7F: // 0x70-0x7F
-			// character is ASCII. skip to next char
+			// character is ASCII. skip to next char that is not
+			cursor = scanStringASCII(s.buf, cursor+1)
+			continue
 		case
 			0x80, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89, 0x8A, 0x8B, 0x8C, 0x8D, 0x8E, 0x8F, // 0x80-0x8F
 			0x90, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98, 0x99, 0x9A, 0x9B, 0x9C, 0x9D, 0x9E, 0x9F, // 0x90-0x9F
@@ -241,7 +271,7 @@ func stringBytes(s *Stream) ([]byte, error) {
 			fallthrough
 		default:
 			// multi bytes character
-			if !utf8.FullRune(s.buf[cursor : len(s.buf)-1]) {
+			if !utf8.FullRune(s.buf[cursor:s.length]) {
 				s.cursor = cursor
 				if s.read() {
 					_, cursor, p = s.stat()
@@ -312,6 +342,7 @@ func (d *stringDecoder) decodeByte(buf []byte, cursor int64) ([]byte, int64, err
 			b := (*sliceHeader)(unsafe.Pointer(&buf)).data
 			escaped := 0
 			for {
+				cursor = scanString(buf, cursor)
 				switch char(b, cursor) {
 				case '\\':
 					escaped++
@@ -11,6 +11,7 @@ import (
 	"unsafe"
 
 	"github.com/goccy/go-json/internal/errors"
+	"github.com/goccy/go-json/internal/runtime"
 )
 
 type structFieldSet struct {
@@ -21,9 +22,43 @@ type structFieldSet struct {
 	key         string
 	keyLen      int64
 	err         error
+	nullErr     *errors.UnmarshalTypeError // returned for null with RejectNullOption, nil if the field accepts null
+	aliases     []string                   // alternative keys of the field
+	aliasOf     string                     // key of the field if key is one of its aliases
+}
+
+// decode decodes the value at cursor into the field of the struct at p.
+func (f *structFieldSet) decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.Pointer) (int64, error) {
+	if f.nullErr != nil && (ctx.Option.Flags&RejectNullOption) != 0 {
+		if c := skipWhiteSpace(ctx.Buf, cursor); ctx.Buf[c] == 'n' {
+			if err := validateNull(ctx.Buf, c); err != nil {
+				return 0, err
+			}
+			return 0, f.nullError(c)
+		}
+	}
+	return f.dec.Decode(ctx, cursor, depth, unsafe.Pointer(uintptr(p)+f.offset))
+}
+
+func (f *structFieldSet) decodeStream(s *Stream, depth int64, p unsafe.Pointer) error {
+	if f.nullErr != nil && (s.Option.Flags&RejectNullOption) != 0 && s.skipWhiteSpace() == 'n' {
+		offset := s.totalOffset()
+		if err := nullBytes(s); err != nil {
+			return err
+		}
+		return f.nullError(offset)
+	}
+	return f.dec.DecodeStream(s, depth, unsafe.Pointer(uintptr(p)+f.offset))
+}
+
+func (f *structFieldSet) nullError(offset int64) error {
+	err := *f.nullErr
+	err.Offset = offset
+	return &err
 }
 
 type structDecoder struct {
+	typ                *runtime.Type
 	fieldMap           map[string]*structFieldSet
 	fieldUniqueNameNum int
 	stringDecoder      *stringDecoder
@@ -51,8 +86,9 @@ func init() {
 	}
 }
 
-func newStructDecoder(structName, fieldName string, fieldMap map[string]*structFieldSet) *structDecoder {
+func newStructDecoder(typ *runtime.Type, structName, fieldName string, fieldMap map[string]*structFieldSet) *structDecoder {
 	return &structDecoder{
+		typ:              typ,
 		fieldMap:         fieldMap,
 		stringDecoder:    newStringDecoder(structName, fieldName),
 		structName:       structName,
@@ -72,14 +108,18 @@ func (d *structDecoder) tryOptimize() {
 	fieldIdx := -1
 	for k, v := range d.fieldMap {
 		lower := strings.ToLower(k)
+		if v.aliasOf != "" {
+			// an alias selects the same field as its primary key.
+			lower = strings.ToLower(v.aliasOf)
+		}
 		idx, exists := fieldUniqueNameMap[lower]
 		if exists {
 			v.fieldIdx = idx
 		} else {
 			fieldIdx++
 			v.fieldIdx = fieldIdx
+			fieldUniqueNameMap[lower] = fieldIdx
 		}
-		fieldUniqueNameMap[lower] = fieldIdx
 	}
 	d.fieldUniqueNameNum = len(fieldUniqueNameMap)
 
@@ -407,7 +447,8 @@ func decodeKeyByBitmapUint8Stream(d *structDecoder, s *Stream) (*structFieldSet,
 					s.cursor = cursor
 					if keyLen < field.keyLen {
 						// early match
-						return nil, field.key, nil
+						b := s.buf[start : cursor-1]
+						return nil, *(*string)(unsafe.Pointer(&b)), nil
 					}
 					return field, field.key, nil
 				case nul:
@@ -494,7 +535,8 @@ func decodeKeyByBitmapUint16Stream(d *structDecoder, s *Stream) (*structFieldSet
 					s.cursor = cursor
 					if keyLen < field.keyLen {
 						// early match
-						return nil, field.key, nil
+						b := s.buf[start : cursor-1]
+						return nil, *(*string)(unsafe.Pointer(&b)), nil
 					}
 					return field, field.key, nil
 				case nul:
@@ -638,11 +680,26 @@ func decodeKeyStream(d *structDecoder, s *Stream) (*structFieldSet, string, erro
 	return d.fieldMap[k], k, nil
 }
 
+func (d *structDecoder) typeError(typeName string, offset int64) *errors.UnmarshalTypeError {
+	return &errors.UnmarshalTypeError{
+		Value:  typeName,
+		Type:   runtime.RType2Type(d.typ),
+		Struct: d.structName,
+		Field:  d.fieldName,
+		Offset: offset,
+	}
+}
+
 func (d *structDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) error {
+	unionKey := s.unionKey
+	s.unionKey = ""
 	depth++
 	if depth > maxDecodeNestingDepth {
 		return errors.ErrExceededMaxDepth(s.char(), s.cursor)
 	}
+	if err := s.Option.checkDepth(depth, s.totalOffset()); err != nil {
+		return err
+	}
 
 	c := s.skipWhiteSpace()
 	switch c {
@@ -653,14 +710,22 @@ func (d *structDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) e
 		return nil
 	default:
 		if s.char() != '{' {
+			if name := typeName(s.char()); name != "" {
+				return d.typeError(name, s.totalOffset())
+			}
 			return errors.ErrInvalidBeginningOfValue(s.char(), s.totalOffset())
 		}
 	}
+	start := s.cursor
 	s.cursor++
 	if s.skipWhiteSpace() == '}' {
 		s.cursor++
 		return nil
 	}
+	validation := s.Option.validation
+	if validation != nil {
+		validation.BeginObject(start)
+	}
 	var (
 		seenFields   map[int]struct{}
 		seenFieldNum int
@@ -669,50 +734,104 @@ func (d *structDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) e
 	if firstWin {
 		seenFields = make(map[int]struct{}, d.fieldUniqueNameNum)
 	}
+	hasLimits := (s.Option.Flags & LimitsOption) != 0
+	keyNum := 0
+	var seenKeys *keySet
+	if (s.Option.Flags & RejectDuplicateKeysOption) != 0 {
+		seenKeys = &keySet{}
+	}
 	for {
+		if hasLimits {
+			keyNum++
+			if err := s.Option.checkObjectKeys(keyNum, s.totalOffset()); err != nil {
+				return err
+			}
+		}
 		s.reset()
+		var keyStart, keyOffset int64
+		if seenKeys != nil || unionKey != "" {
+			s.skipWhiteSpace()
+			keyStart, keyOffset = s.cursor, s.totalOffset()
+		}
+		if validation != nil {
+			s.validateKey(validation, depth)
+		}
 		field, key, err := d.keyStreamDecoder(d, s)
 		if err != nil {
 			return err
 		}
+		keyEnd := s.cursor
+		if seenKeys != nil {
+			seenKey := keyBytes(s.buf, keyStart, keyEnd)
+			if field != nil {
+				err = seenKeys.addField(field, string(seenKey), keyOffset)
+			} else {
+				err = seenKeys.addKey(seenKey, keyOffset)
+			}
+			if err != nil {
+				return err
+			}
+		}
 		if s.skipWhiteSpace() != ':' {
 			return errors.ErrExpected("colon after object key", s.totalOffset())
 		}
 		s.cursor++
+		valueStart := s.cursor
+		if validation != nil {
+			s.validateString(validation, depth)
+		}
 		if field != nil {
 			if field.err != nil {
 				return field.err
 			}
 			if firstWin {
 				if _, exists := seenFields[field.fieldIdx]; exists {
-					if err := s.skipValue(depth); err != nil {
+					if err := s.skipUnusedValue(depth); err != nil {
 						return err
 					}
 				} else {
-					if err := field.dec.DecodeStream(s, depth, unsafe.Pointer(uintptr(p)+field.offset)); err != nil {
-						return err
+					errNum, offset := len(s.Option.Errors), s.totalOffset()
+					if err := field.decodeStream(s, depth, p); err != nil {
+						if err := s.collectError(err, offset, depth); err != nil {
+							return errors.PrependPathKey(err, field.key)
+						}
+					}
+					if len(s.Option.Errors) > errNum {
+						s.Option.prependPathKey(errNum, field.key)
 					}
 					seenFieldNum++
-					if d.fieldUniqueNameNum <= seenFieldNum {
+					if d.fieldUniqueNameNum <= seenFieldNum && seenKeys == nil && validation == nil {
 						return s.skipObject(depth)
 					}
 					seenFields[field.fieldIdx] = struct{}{}
 				}
 			} else {
-				if err := field.dec.DecodeStream(s, depth, unsafe.Pointer(uintptr(p)+field.offset)); err != nil {
-					return err
+				errNum, offset := len(s.Option.Errors), s.totalOffset()
+				if err := field.decodeStream(s, depth, p); err != nil {
+					if err := s.collectError(err, offset, depth); err != nil {
+						return errors.PrependPathKey(err, field.key)
+					}
+				}
+				if len(s.Option.Errors) > errNum {
+					s.Option.prependPathKey(errNum, field.key)
 				}
 			}
-		} else if s.DisallowUnknownFields {
+		} else if s.DisallowUnknownFields && (unionKey == "" || string(keyBytes(s.buf, keyStart, keyEnd)) != unionKey) {
 			return fmt.Errorf("json: unknown field %q", key)
 		} else {
-			if err := s.skipValue(depth); err != nil {
+			if err := s.skipUnusedValue(depth); err != nil {
 				return err
 			}
 		}
+		if validation != nil {
+			validation.Value(s.buf, valueStart, s.cursor)
+		}
 		c := s.skipWhiteSpace()
 		if c == '}' {
 			s.cursor++
+			if validation != nil {
+				validation.End()
+			}
 			return nil
 		}
 		if c != ',' {
@@ -728,6 +847,9 @@ func (d *structDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsaf
 	if depth > maxDecodeNestingDepth {
 		return 0, errors.ErrExceededMaxDepth(buf[cursor], cursor)
 	}
+	if err := ctx.Option.checkDepth(depth, cursor); err != nil {
+		return 0, err
+	}
 	buflen := int64(len(buf))
 	cursor = skipWhiteSpace(buf, cursor)
 	b := (*sliceHeader)(unsafe.Pointer(&buf)).data
@@ -740,14 +862,22 @@ func (d *structDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsaf
 		return cursor, nil
 	case '{':
 	default:
+		if name := typeName(char(b, cursor)); name != "" {
+			return 0, d.typeError(name, cursor)
+		}
 		return 0, errors.ErrInvalidBeginningOfValue(char(b, cursor), cursor)
 	}
+	start := cursor
 	cursor++
 	cursor = skipWhiteSpace(buf, cursor)
 	if buf[cursor] == '}' {
 		cursor++
 		return cursor, nil
 	}
+	validation := ctx.Option.validation
+	if validation != nil {
+		validation.BeginObject(start)
+	}
 	var (
 		seenFields   map[int]struct{}
 		seenFieldNum int
@@ -756,11 +886,38 @@ func (d *structDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsaf
 	if firstWin {
 		seenFields = make(map[int]struct{}, d.fieldUniqueNameNum)
 	}
+	hasLimits := (ctx.Option.Flags & LimitsOption) != 0
+	keyNum := 0
+	var seenKeys *keySet
+	if (ctx.Option.Flags & RejectDuplicateKeysOption) != 0 {
+		seenKeys = &keySet{}
+	}
 	for {
+		if hasLimits {
+			keyNum++
+			if err := ctx.Option.checkObjectKeys(keyNum, cursor); err != nil {
+				return 0, err
+			}
+		}
+		if validation != nil {
+			validateKey(validation, buf, cursor, depth)
+		}
 		c, field, err := d.keyDecoder(d, buf, cursor)
 		if err != nil {
 			return 0, err
 		}
+		if seenKeys != nil {
+			keyCursor := skipWhiteSpace(buf, cursor)
+			key := keyBytes(buf, keyCursor, c)
+			if field != nil {
+				err = seenKeys.addField(field, string(key), keyCursor)
+			} else {
+				err = seenKeys.addKey(key, keyCursor)
+			}
+			if err != nil {
+				return 0, err
+			}
+		}
 		cursor = skipWhiteSpace(buf, c)
 		if char(b, cursor) != ':' {
 			return 0, errors.ErrExpected("colon after object key", cursor)
@@ -769,46 +926,70 @@ func (d *structDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsaf
 		if cursor >= buflen {
 			return 0, errors.ErrExpected("object value after colon", cursor)
 		}
+		valueStart := cursor
+		if validation != nil {
+			validateString(validation, buf, cursor, depth)
+		}
 		if field != nil {
 			if field.err != nil {
 				return 0, field.err
 			}
 			if firstWin {
 				if _, exists := seenFields[field.fieldIdx]; exists {
-					c, err := skipValue(buf, cursor, depth)
+					c, err := skipUnusedValue(ctx, cursor, depth)
 					if err != nil {
 						return 0, err
 					}
 					cursor = c
 				} else {
-					c, err := field.dec.Decode(ctx, cursor, depth, unsafe.Pointer(uintptr(p)+field.offset))
+					errNum := len(ctx.Option.Errors)
+					c, err := field.decode(ctx, cursor, depth, p)
 					if err != nil {
-						return 0, err
+						c, err = collectError(ctx, err, cursor, depth)
+						if err != nil {
+							return 0, errors.PrependPathKey(err, field.key)
+						}
+					}
+					if len(ctx.Option.Errors) > errNum {
+						ctx.Option.prependPathKey(errNum, field.key)
 					}
 					cursor = c
 					seenFieldNum++
-					if d.fieldUniqueNameNum <= seenFieldNum {
+					if d.fieldUniqueNameNum <= seenFieldNum && seenKeys == nil && validation == nil {
 						return skipObject(buf, cursor, depth)
 					}
 					seenFields[field.fieldIdx] = struct{}{}
 				}
 			} else {
-				c, err := field.dec.Decode(ctx, cursor, depth, unsafe.Pointer(uintptr(p)+field.offset))
+				errNum := len(ctx.Option.Errors)
+				c, err := field.decode(ctx, cursor, depth, p)
 				if err != nil {
-					return 0, err
+					c, err = collectError(ctx, err, cursor, depth)
+					if err != nil {
+						return 0, errors.PrependPathKey(err, field.key)
+					}
+				}
+				if len(ctx.Option.Errors) > errNum {
+					ctx.Option.prependPathKey(errNum, field.key)
 				}
 				cursor = c
 			}
 		} else {
-			c, err := skipValue(buf, cursor, depth)
+			c, err := skipUnusedValue(ctx, cursor, depth)
 			if err != nil {
 				return 0, err
 			}
 			cursor = c
 		}
+		if validation != nil {
+			validation.Value(buf, valueStart, cursor)
+		}
 		cursor = skipWhiteSpace(buf, cursor)
 		if char(b, cursor) == '}' {
 			cursor++
+			if validation != nil {
+				validation.End()
+			}
 			return cursor, nil
 		}
 		if char(b, cursor) != ',' {
@@ -0,0 +1,69 @@
+package decoder
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// The scan functions test eight bytes of the input per iteration, like the string encoder does,
+// and return the position of the first byte that the byte by byte loops have to look at.
+// They stop early when fewer than eight bytes are left, so the caller has to check the byte
+// at the returned position and scan again if it is not one it looks for.
+
+const (
+	lsb = 0x0101010101010101
+	msb = 0x8080808080808080
+)
+
+// zeroBytes returns n with the high bit of its zero bytes set.
+// Only the lowest set bit is exact: a zero byte can set the bits of the bytes above it,
+// which does not matter since the scan functions only use the position of the first one.
+func zeroBytes(n uint64) uint64 {
+	return (n - lsb) &^ n & msb
+}
+
+func loadWord(buf []byte, cursor int64) uint64 {
+	return binary.LittleEndian.Uint64(buf[cursor : cursor+8])
+}
+
+// scanString returns the position of the first '"', '\\' or nul byte at or after cursor.
+func scanString(buf []byte, cursor int64) int64 {
+	for end := int64(len(buf)) - 8; cursor <= end; cursor += 8 {
+		n := loadWord(buf, cursor)
+		mask := zeroBytes(n^(lsb*'"')) | zeroBytes(n^(lsb*'\\')) | zeroBytes(n)
+		if mask != 0 {
+			return cursor + int64(bits.TrailingZeros64(mask)/8)
+		}
+	}
+	return cursor
+}
+
+// scanStringASCII is like scanString, but also stops at the bytes that are not ASCII.
+func scanStringASCII(buf []byte, cursor int64) int64 {
+	for end := int64(len(buf)) - 8; cursor <= end; cursor += 8 {
+		n := loadWord(buf, cursor)
+		mask := n&msb | zeroBytes(n^(lsb*'"')) | zeroBytes(n^(lsb*'\\')) | zeroBytes(n)
+		if mask != 0 {
+			return cursor + int64(bits.TrailingZeros64(mask)/8)
+		}
+	}
+	return cursor
+}
+
+// scanStructural returns the position of the first '{', '}', '[', ']', '"' or nul byte at or after cursor.
+// It can also stop at the control character 0x02, which folds to '"'.
+func scanStructural(buf []byte, cursor int64) int64 {
+	for end := int64(len(buf)) - 8; cursor <= end; cursor += 8 {
+		n := loadWord(buf, cursor)
+		// setting the 0x20 bit folds '[' and ']' into '{' and '}'
+		folded := n | (lsb * 0x20)
+		mask := zeroBytes(folded^(lsb*'{')) | zeroBytes(folded^(lsb*'}')) | zeroBytes(folded^(lsb*'"')) | zeroBytes(n)
+		if mask != 0 {
+			return cursor + int64(bits.TrailingZeros64(mask)/8)
+		}
+	}
+	return cursor
+}
+
+// structural marks the bytes that the skip functions look at outside of strings.
+var structural = [256]bool{'{': true, '}': true, '[': true, ']': true, '"': true, nul: true}
@@ -131,7 +131,10 @@ func (d *uintDecoder) decodeByte(buf []byte, cursor int64) ([]byte, int64, error
 func (d *uintDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) error {
 	bytes, err := d.decodeStreamByte(s)
 	if err != nil {
-		return err
+		bytes, err = lenientNumberStream(s, unsignedLiteral, err)
+		if err != nil {
+			return err
+		}
 	}
 	if bytes == nil {
 		return nil
@@ -161,7 +164,10 @@ func (d *uintDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) err
 func (d *uintDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.Pointer) (int64, error) {
 	bytes, c, err := d.decodeByte(ctx.Buf, cursor)
 	if err != nil {
-		return 0, err
+		bytes, c, err = lenientNumberByte(ctx, cursor, unsignedLiteral, err)
+		if err != nil {
+			return 0, err
+		}
 	}
 	if bytes == nil {
 		return c, nil
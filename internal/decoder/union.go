@@ -0,0 +1,248 @@
+package decoder
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	"github.com/goccy/go-json/internal/errors"
+	"github.com/goccy/go-json/internal/runtime"
+)
+
+// unionDecoder decodes JSON objects into an interface type registered as a union.
+// The concrete type is selected by the discriminator property, which may appear at any position in the object.
+type unionDecoder struct {
+	typ        *runtime.Type
+	union      *runtime.Union
+	structName string
+	fieldName  string
+}
+
+func newUnionDecoder(typ *runtime.Type, union *runtime.Union, structName, fieldName string) *unionDecoder {
+	return &unionDecoder{
+		typ:        typ,
+		union:      union,
+		structName: structName,
+		fieldName:  fieldName,
+	}
+}
+
+func (d *unionDecoder) errUnmarshalType(value string, offset int64) *errors.UnmarshalTypeError {
+	return &errors.UnmarshalTypeError{
+		Value:  value,
+		Type:   runtime.RType2Type(d.typ),
+		Offset: offset,
+		Struct: d.structName,
+		Field:  d.fieldName,
+	}
+}
+
+func (d *unionDecoder) errMissingTag(offset int64) *errors.UnmarshalTypeError {
+	return d.errUnmarshalType(fmt.Sprintf("object without %q", d.union.Key), offset)
+}
+
+// isTagKey reports whether the quoted object key is the discriminator property.
+func (d *unionDecoder) isTagKey(key []byte) bool {
+	k, ok := unquoteBytes(key)
+	return ok && string(k) == d.union.Key
+}
+
+// member returns the concrete type selected by the quoted discriminator value tag.
+func (d *unionDecoder) member(tag []byte, offset int64) (*runtime.Type, error) {
+	name, ok := unquoteBytes(tag)
+	if !ok {
+		return nil, d.errUnmarshalType(fmt.Sprintf("object with %s %s", d.union.Key, tag), offset)
+	}
+	typ, exists := d.union.Types[string(name)]
+	if !exists {
+		return nil, d.errUnmarshalType(fmt.Sprintf("object with %s %q", d.union.Key, name), offset)
+	}
+	return typ, nil
+}
+
+// newValue allocates the value decoded for the concrete type typ.
+// It returns the struct to decode into and its decoder.
+func (d *unionDecoder) newValue(typ *runtime.Type, opt *Option) (unsafe.Pointer, Decoder, error) {
+	ptrType := typ
+	if typ.Kind() != reflect.Ptr {
+		ptrType = runtime.PtrTo(typ)
+	}
+	dec, err := CompileToGetDecoderWithOption(ptrType, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	return unsafe_New(ptrType.Elem()), dec, nil
+}
+
+// set stores the decoded struct v of the concrete type typ into the interface at p.
+func (d *unionDecoder) set(typ *runtime.Type, v, p unsafe.Pointer) {
+	var value reflect.Value
+	if typ.Kind() == reflect.Ptr {
+		value = reflect.NewAt(runtime.RType2Type(typ.Elem()), v)
+	} else {
+		value = reflect.NewAt(runtime.RType2Type(typ), v).Elem()
+	}
+	reflect.NewAt(runtime.RType2Type(d.typ), p).Elem().Set(value)
+}
+
+func jsonValueKind(c byte) string {
+	switch c {
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "bool"
+	}
+	return "number"
+}
+
+// streamTag returns the quoted discriminator value of the object at the cursor.
+// The cursor is moved through the object, so the caller restores it afterwards.
+func (d *unionDecoder) streamTag(s *Stream, depth int64) ([]byte, error) {
+	offset := s.totalOffset()
+	s.cursor++
+	for {
+		if s.skipWhiteSpace() == '}' {
+			return nil, d.errMissingTag(offset)
+		}
+		keyStart := s.cursor
+		if err := s.skipValue(depth); err != nil {
+			return nil, err
+		}
+		isTag := d.isTagKey(s.buf[keyStart:s.cursor])
+		if s.skipWhiteSpace() != ':' {
+			return nil, errors.ErrExpected("colon after object key", s.totalOffset())
+		}
+		s.cursor++
+		s.skipWhiteSpace()
+		valueStart := s.cursor
+		if err := s.skipValue(depth); err != nil {
+			return nil, err
+		}
+		if isTag {
+			return s.buf[valueStart:s.cursor], nil
+		}
+		switch s.skipWhiteSpace() {
+		case ',':
+			s.cursor++
+		case '}':
+			return nil, d.errMissingTag(offset)
+		default:
+			return nil, errors.ErrExpected("comma after object value", s.totalOffset())
+		}
+	}
+}
+
+func (d *unionDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) error {
+	switch c := s.skipWhiteSpace(); c {
+	case 'n':
+		if err := nullBytes(s); err != nil {
+			return err
+		}
+		reflect.NewAt(runtime.RType2Type(d.typ), p).Elem().Set(reflect.Zero(runtime.RType2Type(d.typ)))
+		return nil
+	case '{':
+	case nul:
+		return errors.ErrUnexpectedEndOfJSON("object", s.totalOffset())
+	default:
+		return d.errUnmarshalType(jsonValueKind(c), s.totalOffset())
+	}
+	start, offset := s.cursor, s.totalOffset()
+	tag, err := d.streamTag(s, depth)
+	if err != nil {
+		return err
+	}
+	s.cursor = start
+	typ, err := d.member(tag, offset)
+	if err != nil {
+		return err
+	}
+	v, dec, err := d.newValue(typ, s.Option)
+	if err != nil {
+		return err
+	}
+	// the discriminator is not an unknown field of the member struct
+	s.unionKey = d.union.Key
+	err = dec.DecodeStream(s, depth, v)
+	s.unionKey = ""
+	if err != nil {
+		return err
+	}
+	d.set(typ, v, p)
+	return nil
+}
+
+// tag returns the quoted discriminator value of the object at cursor.
+func (d *unionDecoder) tag(buf []byte, cursor, depth int64) ([]byte, error) {
+	offset := cursor
+	cursor++
+	for {
+		cursor = skipWhiteSpace(buf, cursor)
+		if buf[cursor] == '}' {
+			return nil, d.errMissingTag(offset)
+		}
+		keyEnd, err := skipValue(buf, cursor, depth)
+		if err != nil {
+			return nil, err
+		}
+		isTag := d.isTagKey(buf[cursor:keyEnd])
+		cursor = skipWhiteSpace(buf, keyEnd)
+		if buf[cursor] != ':' {
+			return nil, errors.ErrExpected("colon after object key", cursor)
+		}
+		cursor = skipWhiteSpace(buf, cursor+1)
+		valueEnd, err := skipValue(buf, cursor, depth)
+		if err != nil {
+			return nil, err
+		}
+		if isTag {
+			return buf[cursor:valueEnd], nil
+		}
+		cursor = skipWhiteSpace(buf, valueEnd)
+		switch buf[cursor] {
+		case ',':
+			cursor++
+		case '}':
+			return nil, d.errMissingTag(offset)
+		default:
+			return nil, errors.ErrExpected("comma after object value", cursor)
+		}
+	}
+}
+
+func (d *unionDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.Pointer) (int64, error) {
+	buf := ctx.Buf
+	cursor = skipWhiteSpace(buf, cursor)
+	switch c := buf[cursor]; c {
+	case 'n':
+		if err := validateNull(buf, cursor); err != nil {
+			return 0, err
+		}
+		reflect.NewAt(runtime.RType2Type(d.typ), p).Elem().Set(reflect.Zero(runtime.RType2Type(d.typ)))
+		return cursor + 4, nil
+	case '{':
+	case nul:
+		return 0, errors.ErrUnexpectedEndOfJSON("object", cursor)
+	default:
+		return 0, d.errUnmarshalType(jsonValueKind(c), cursor)
+	}
+	tag, err := d.tag(buf, cursor, depth)
+	if err != nil {
+		return 0, err
+	}
+	typ, err := d.member(tag, cursor)
+	if err != nil {
+		return 0, err
+	}
+	v, dec, err := d.newValue(typ, ctx.Option)
+	if err != nil {
+		return 0, err
+	}
+	cursor, err = dec.Decode(ctx, cursor, depth, v)
+	if err != nil {
+		return 0, err
+	}
+	d.set(typ, v, p)
+	return cursor, nil
+}
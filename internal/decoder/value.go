@@ -0,0 +1,330 @@
+package decoder
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+
+	"github.com/goccy/go-json/internal/dom"
+	"github.com/goccy/go-json/internal/errors"
+	"github.com/goccy/go-json/internal/runtime"
+)
+
+var valueType = runtime.Type2RType(reflect.TypeOf(dom.Value{}))
+
+// valueDecoder decodes any JSON value into a dom.Value tree.
+// Numbers keep their literal and objects keep the order of their keys.
+type valueDecoder struct {
+	stringDecoder *stringDecoder
+	keyDecoder    *stringDecoder
+}
+
+func newValueDecoder(structName, fieldName string) *valueDecoder {
+	return &valueDecoder{
+		stringDecoder: newStringDecoder(structName, fieldName),
+		keyDecoder:    newMapKeyStringDecoder(structName, fieldName),
+	}
+}
+
+func errInvalidNumber(literal []byte, offset int64) *errors.SyntaxError {
+	return errors.ErrSyntax(fmt.Sprintf("json: invalid number literal %q", literal), offset)
+}
+
+func (d *valueDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Pointer) error {
+	v := (*dom.Value)(p)
+	c := s.skipWhiteSpace()
+	for {
+		switch c {
+		case '{':
+			return d.decodeStreamObject(s, depth, v)
+		case '[':
+			return d.decodeStreamArray(s, depth, v)
+		case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			start := s.totalOffset()
+			literal := floatBytes(s)
+			if !isNumberLiteral(literal, floatLiteral) {
+				return errInvalidNumber(literal, start)
+			}
+			dom.Init(v, dom.KindNumber, string(literal), false)
+			return nil
+		case '"':
+			var str string
+			if err := d.stringDecoder.DecodeStream(s, depth, unsafe.Pointer(&str)); err != nil {
+				return err
+			}
+			dom.Init(v, dom.KindString, str, false)
+			return nil
+		case 't':
+			if err := trueBytes(s); err != nil {
+				return err
+			}
+			dom.Init(v, dom.KindBool, "", true)
+			return nil
+		case 'f':
+			if err := falseBytes(s); err != nil {
+				return err
+			}
+			dom.Init(v, dom.KindBool, "", false)
+			return nil
+		case 'n':
+			if err := nullBytes(s); err != nil {
+				return err
+			}
+			dom.Init(v, dom.KindNull, "", false)
+			return nil
+		case nul:
+			if s.read() {
+				c = s.char()
+				continue
+			}
+		}
+		break
+	}
+	return errors.ErrInvalidBeginningOfValue(c, s.totalOffset())
+}
+
+func (d *valueDecoder) decodeStreamObject(s *Stream, depth int64, v *dom.Value) error {
+	depth++
+	if depth > maxDecodeNestingDepth {
+		return errors.ErrExceededMaxDepth(s.char(), s.cursor)
+	}
+	if err := s.Option.checkDepth(depth, s.totalOffset()); err != nil {
+		return err
+	}
+	dom.Init(v, dom.KindObject, "", false)
+	s.cursor++
+	if s.skipWhiteSpace() == '}' {
+		s.cursor++
+		return nil
+	}
+	hasLimits := (s.Option.Flags & LimitsOption) != 0
+	keyNum := 0
+	var seenKeys *keySet
+	if (s.Option.Flags & RejectDuplicateKeysOption) != 0 {
+		seenKeys = &keySet{}
+	}
+	for {
+		if hasLimits {
+			keyNum++
+			if err := s.Option.checkObjectKeys(keyNum, s.totalOffset()); err != nil {
+				return err
+			}
+		}
+		s.skipWhiteSpace()
+		keyOffset := s.totalOffset()
+		var key string
+		if err := d.keyDecoder.DecodeStream(s, depth, unsafe.Pointer(&key)); err != nil {
+			return err
+		}
+		if seenKeys != nil {
+			if err := seenKeys.addKey([]byte(key), keyOffset); err != nil {
+				return err
+			}
+		}
+		if s.skipWhiteSpace() != ':' {
+			return errors.ErrExpected("colon after object key", s.totalOffset())
+		}
+		s.cursor++
+		member := &dom.Value{}
+		if err := d.DecodeStream(s, depth, unsafe.Pointer(member)); err != nil {
+			return errors.PrependPathKey(err, key)
+		}
+		dom.SetMember(v, key, member)
+		switch s.skipWhiteSpace() {
+		case '}':
+			s.cursor++
+			return nil
+		case ',':
+			s.cursor++
+		default:
+			return errors.ErrExpected("comma after object value", s.totalOffset())
+		}
+	}
+}
+
+func (d *valueDecoder) decodeStreamArray(s *Stream, depth int64, v *dom.Value) error {
+	depth++
+	if depth > maxDecodeNestingDepth {
+		return errors.ErrExceededMaxDepth(s.char(), s.cursor)
+	}
+	if err := s.Option.checkDepth(depth, s.totalOffset()); err != nil {
+		return err
+	}
+	dom.Init(v, dom.KindArray, "", false)
+	s.cursor++
+	if s.skipWhiteSpace() == ']' {
+		s.cursor++
+		return nil
+	}
+	hasLimits := (s.Option.Flags & LimitsOption) != 0
+	for idx := 0; ; idx++ {
+		if hasLimits {
+			if err := s.Option.checkArrayLen(idx+1, s.totalOffset()); err != nil {
+				return err
+			}
+		}
+		elem := &dom.Value{}
+		if err := d.DecodeStream(s, depth, unsafe.Pointer(elem)); err != nil {
+			return errors.PrependPathIndex(err, idx)
+		}
+		dom.AppendElement(v, elem)
+		switch s.skipWhiteSpace() {
+		case ']':
+			s.cursor++
+			return nil
+		case ',':
+			s.cursor++
+		default:
+			return errors.ErrExpected("comma after array element", s.totalOffset())
+		}
+	}
+}
+
+func (d *valueDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.Pointer) (int64, error) {
+	v := (*dom.Value)(p)
+	buf := ctx.Buf
+	cursor = skipWhiteSpace(buf, cursor)
+	switch buf[cursor] {
+	case '{':
+		return d.decodeObject(ctx, cursor, depth, v)
+	case '[':
+		return d.decodeArray(ctx, cursor, depth, v)
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		start := cursor
+		cursor++
+		for floatTable[buf[cursor]] {
+			cursor++
+		}
+		literal := buf[start:cursor]
+		if !isNumberLiteral(literal, floatLiteral) {
+			return 0, errInvalidNumber(literal, start)
+		}
+		dom.Init(v, dom.KindNumber, string(literal), false)
+		return cursor, nil
+	case '"':
+		var str string
+		cursor, err := d.stringDecoder.Decode(ctx, cursor, depth, unsafe.Pointer(&str))
+		if err != nil {
+			return 0, err
+		}
+		dom.Init(v, dom.KindString, str, false)
+		return cursor, nil
+	case 't':
+		if err := validateTrue(buf, cursor); err != nil {
+			return 0, err
+		}
+		dom.Init(v, dom.KindBool, "", true)
+		return cursor + 4, nil
+	case 'f':
+		if err := validateFalse(buf, cursor); err != nil {
+			return 0, err
+		}
+		dom.Init(v, dom.KindBool, "", false)
+		return cursor + 5, nil
+	case 'n':
+		if err := validateNull(buf, cursor); err != nil {
+			return 0, err
+		}
+		dom.Init(v, dom.KindNull, "", false)
+		return cursor + 4, nil
+	}
+	return cursor, errors.ErrInvalidBeginningOfValue(buf[cursor], cursor)
+}
+
+func (d *valueDecoder) decodeObject(ctx *RuntimeContext, cursor, depth int64, v *dom.Value) (int64, error) {
+	buf := ctx.Buf
+	depth++
+	if depth > maxDecodeNestingDepth {
+		return 0, errors.ErrExceededMaxDepth(buf[cursor], cursor)
+	}
+	if err := ctx.Option.checkDepth(depth, cursor); err != nil {
+		return 0, err
+	}
+	dom.Init(v, dom.KindObject, "", false)
+	cursor = skipWhiteSpace(buf, cursor+1)
+	if buf[cursor] == '}' {
+		return cursor + 1, nil
+	}
+	hasLimits := (ctx.Option.Flags & LimitsOption) != 0
+	keyNum := 0
+	var seenKeys *keySet
+	if (ctx.Option.Flags & RejectDuplicateKeysOption) != 0 {
+		seenKeys = &keySet{}
+	}
+	for {
+		if hasLimits {
+			keyNum++
+			if err := ctx.Option.checkObjectKeys(keyNum, cursor); err != nil {
+				return 0, err
+			}
+		}
+		var key string
+		keyCursor, err := d.keyDecoder.Decode(ctx, cursor, depth, unsafe.Pointer(&key))
+		if err != nil {
+			return 0, err
+		}
+		if seenKeys != nil {
+			if err := seenKeys.addKey([]byte(key), skipWhiteSpace(buf, cursor)); err != nil {
+				return 0, err
+			}
+		}
+		cursor = skipWhiteSpace(buf, keyCursor)
+		if buf[cursor] != ':' {
+			return 0, errors.ErrExpected("colon after object key", cursor)
+		}
+		member := &dom.Value{}
+		valueCursor, err := d.Decode(ctx, cursor+1, depth, unsafe.Pointer(member))
+		if err != nil {
+			return 0, errors.PrependPathKey(err, key)
+		}
+		dom.SetMember(v, key, member)
+		cursor = skipWhiteSpace(buf, valueCursor)
+		switch buf[cursor] {
+		case '}':
+			return cursor + 1, nil
+		case ',':
+			cursor++
+		default:
+			return 0, errors.ErrExpected("comma after object value", cursor)
+		}
+	}
+}
+
+func (d *valueDecoder) decodeArray(ctx *RuntimeContext, cursor, depth int64, v *dom.Value) (int64, error) {
+	buf := ctx.Buf
+	depth++
+	if depth > maxDecodeNestingDepth {
+		return 0, errors.ErrExceededMaxDepth(buf[cursor], cursor)
+	}
+	if err := ctx.Option.checkDepth(depth, cursor); err != nil {
+		return 0, err
+	}
+	dom.Init(v, dom.KindArray, "", false)
+	cursor = skipWhiteSpace(buf, cursor+1)
+	if buf[cursor] == ']' {
+		return cursor + 1, nil
+	}
+	hasLimits := (ctx.Option.Flags & LimitsOption) != 0
+	for idx := 0; ; idx++ {
+		if hasLimits {
+			if err := ctx.Option.checkArrayLen(idx+1, cursor); err != nil {
+				return 0, err
+			}
+		}
+		elem := &dom.Value{}
+		valueCursor, err := d.Decode(ctx, cursor, depth, unsafe.Pointer(elem))
+		if err != nil {
+			return 0, errors.PrependPathIndex(err, idx)
+		}
+		dom.AppendElement(v, elem)
+		cursor = skipWhiteSpace(buf, valueCursor)
+		switch buf[cursor] {
+		case ']':
+			return cursor + 1, nil
+		case ',':
+			cursor++
+		default:
+			return 0, errors.ErrExpected("comma after array element", cursor)
+		}
+	}
+}
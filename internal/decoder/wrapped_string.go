@@ -40,10 +40,12 @@ func (d *wrappedStringDecoder) DecodeStream(s *Stream, depth int64, p unsafe.Poi
 	}
 	b := make([]byte, len(bytes)+1)
 	copy(b, bytes)
-	if _, err := d.dec.Decode(&RuntimeContext{Buf: b}, 0, depth, p); err != nil {
-		return err
-	}
-	return nil
+	// the schema validates the string itself, not the value inside it
+	validation := s.Option.validation
+	s.Option.validation = nil
+	_, err = d.dec.Decode(&RuntimeContext{Buf: b, Option: s.Option}, 0, depth, p)
+	s.Option.validation = validation
+	return err
 }
 
 func (d *wrappedStringDecoder) Decode(ctx *RuntimeContext, cursor, depth int64, p unsafe.Pointer) (int64, error) {
@@ -60,7 +62,12 @@ func (d *wrappedStringDecoder) Decode(ctx *RuntimeContext, cursor, depth int64,
 	bytes = append(bytes, nul)
 	oldBuf := ctx.Buf
 	ctx.Buf = bytes
-	if _, err := d.dec.Decode(ctx, 0, depth, p); err != nil {
+	// the schema validates the string itself, not the value inside it
+	validation := ctx.Option.validation
+	ctx.Option.validation = nil
+	_, err = d.dec.Decode(ctx, 0, depth, p)
+	ctx.Option.validation = validation
+	if err != nil {
 		return 0, err
 	}
 	ctx.Buf = oldBuf
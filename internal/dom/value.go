@@ -0,0 +1,394 @@
+// Package dom implements the mutable JSON document tree exposed as json.Value.
+// The decoder and the encoder build and walk the tree with the functions of this package.
+package dom
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kind is the kind of a JSON value.
+type Kind int
+
+const (
+	KindInvalid Kind = iota
+	KindNull
+	KindBool
+	KindNumber
+	KindString
+	KindArray
+	KindObject
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindNull:
+		return "null"
+	case KindBool:
+		return "bool"
+	case KindNumber:
+		return "number"
+	case KindString:
+		return "string"
+	case KindArray:
+		return "array"
+	case KindObject:
+		return "object"
+	}
+	return "invalid"
+}
+
+// indexThreshold is the number of object members from which members are looked up with a map.
+const indexThreshold = 8
+
+// Value is a JSON value that can be inspected and modified in place.
+// Objects keep the order of their members and numbers keep their original literal,
+// so a decoded document is encoded again without changes.
+// The zero Value is null.
+type Value struct {
+	kind   Kind
+	text   string   // value of a string or literal of a number
+	b      bool     // value of a bool
+	keys   []string // keys of an object
+	values []*Value // elements of an array or member values of an object
+	index  map[string]int
+}
+
+// NewNull returns a null value.
+func NewNull() *Value {
+	return &Value{kind: KindNull}
+}
+
+// NewBool returns a bool value.
+func NewBool(b bool) *Value {
+	return &Value{kind: KindBool, b: b}
+}
+
+// NewNumber returns a number value with the literal n. n is checked when the value is encoded.
+func NewNumber(n json.Number) *Value {
+	return &Value{kind: KindNumber, text: string(n)}
+}
+
+// NewString returns a string value.
+func NewString(s string) *Value {
+	return &Value{kind: KindString, text: s}
+}
+
+// NewArray returns an array value with elems as its elements.
+// A nil element is stored as null.
+func NewArray(elems ...*Value) *Value {
+	v := &Value{kind: KindArray, values: make([]*Value, 0, len(elems))}
+	for _, elem := range elems {
+		v.values = append(v.values, orNull(elem))
+	}
+	return v
+}
+
+// NewObject returns an empty object value.
+func NewObject() *Value {
+	return &Value{kind: KindObject}
+}
+
+func orNull(v *Value) *Value {
+	if v == nil {
+		return NewNull()
+	}
+	return v
+}
+
+// Init sets v to an empty value of kind with the string or number text and the bool b.
+func Init(v *Value, kind Kind, text string, b bool) {
+	*v = Value{kind: kind, text: text, b: b}
+}
+
+// AppendElement appends elem to the array v.
+func AppendElement(v *Value, elem *Value) {
+	v.values = append(v.values, elem)
+}
+
+// SetMember sets the member key of the object v to value.
+// A new key is added after the existing keys and an existing key keeps its position.
+func SetMember(v *Value, key string, value *Value) {
+	if i := v.lookup(key); i >= 0 {
+		v.values[i] = value
+		return
+	}
+	if v.index != nil {
+		v.index[key] = len(v.keys)
+	}
+	v.keys = append(v.keys, key)
+	v.values = append(v.values, value)
+}
+
+// Members returns the keys and values of the object v, or the elements of the array v.
+// The returned slices must not be modified.
+func Members(v *Value) ([]string, []*Value) {
+	return v.keys, v.values
+}
+
+func (v *Value) lookup(key string) int {
+	if v.index == nil && len(v.keys) > indexThreshold {
+		v.index = make(map[string]int, len(v.keys))
+		for i, k := range v.keys {
+			v.index[k] = i
+		}
+	}
+	if v.index != nil {
+		if i, exists := v.index[key]; exists {
+			return i
+		}
+		return -1
+	}
+	for i, k := range v.keys {
+		if k == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// Kind returns the kind of v. It returns KindInvalid if v is nil.
+func (v *Value) Kind() Kind {
+	if v == nil {
+		return KindInvalid
+	}
+	if v.kind == KindInvalid {
+		return KindNull
+	}
+	return v.kind
+}
+
+// Bool returns the value of the bool v, or false if v is not a bool.
+func (v *Value) Bool() bool {
+	return v.Kind() == KindBool && v.b
+}
+
+// Number returns the literal of the number v, or "" if v is not a number.
+func (v *Value) Number() json.Number {
+	if v.Kind() != KindNumber {
+		return ""
+	}
+	return json.Number(v.text)
+}
+
+// Text returns the value of the string v, or "" if v is not a string.
+func (v *Value) Text() string {
+	if v.Kind() != KindString {
+		return ""
+	}
+	return v.text
+}
+
+// Len returns the number of elements of the array v or the number of members of the object v.
+// It returns 0 for the other kinds.
+func (v *Value) Len() int {
+	if v == nil {
+		return 0
+	}
+	return len(v.values)
+}
+
+// Index returns the i'th element of the array v, or nil if v is not an array or i is out of range.
+func (v *Value) Index(i int) *Value {
+	if v.Kind() != KindArray || i < 0 || i >= len(v.values) {
+		return nil
+	}
+	return v.values[i]
+}
+
+// Keys returns the keys of the object v in order.
+func (v *Value) Keys() []string {
+	if v.Kind() != KindObject {
+		return nil
+	}
+	keys := make([]string, len(v.keys))
+	copy(keys, v.keys)
+	return keys
+}
+
+// Member returns the value of key in the object v, or nil if v is not an object or has no such key.
+func (v *Value) Member(key string) *Value {
+	if v.Kind() != KindObject {
+		return nil
+	}
+	if i := v.lookup(key); i >= 0 {
+		return v.values[i]
+	}
+	return nil
+}
+
+// Get returns the value at path, a JSON Pointer (RFC 6901) such as "/items/0/name",
+// or nil if there is no value at path or path is not a valid JSON Pointer.
+// The empty path refers to v itself.
+func (v *Value) Get(path string) *Value {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return nil
+	}
+	for _, token := range tokens {
+		if v = v.child(token); v == nil {
+			return nil
+		}
+	}
+	return v
+}
+
+func (v *Value) child(token string) *Value {
+	switch v.Kind() {
+	case KindObject:
+		return v.Member(token)
+	case KindArray:
+		i, ok := arrayIndex(token)
+		if !ok {
+			return nil
+		}
+		return v.Index(i)
+	}
+	return nil
+}
+
+// Set stores value at path, a JSON Pointer (RFC 6901).
+// A member of an object is replaced in place or added after the existing members,
+// and members of objects missing on the way to path are created as empty objects.
+// An element of an array is replaced, or appended if the last token of path is
+// the length of the array or "-". The empty path replaces v itself with a copy of value.
+// value is stored without being copied; a nil value is stored as null.
+func (v *Value) Set(path string, value *Value) error {
+	tokens, err := parsePointer(path)
+	if err != nil {
+		return err
+	}
+	value = orNull(value)
+	if len(tokens) == 0 {
+		*v = *value
+		return nil
+	}
+	parent := v
+	for i, token := range tokens[:len(tokens)-1] {
+		next := parent.child(token)
+		if next == nil {
+			if parent.Kind() != KindObject {
+				return pathError(path, tokens[:i+1], "does not exist")
+			}
+			next = NewObject()
+			SetMember(parent, token, next)
+		}
+		parent = next
+	}
+	last := tokens[len(tokens)-1]
+	switch parent.Kind() {
+	case KindObject:
+		SetMember(parent, last, value)
+		return nil
+	case KindArray:
+		if last == "-" {
+			parent.values = append(parent.values, value)
+			return nil
+		}
+		i, ok := arrayIndex(last)
+		switch {
+		case !ok || i > len(parent.values):
+			return pathError(path, tokens, "is out of range")
+		case i == len(parent.values):
+			parent.values = append(parent.values, value)
+		default:
+			parent.values[i] = value
+		}
+		return nil
+	}
+	return pathError(path, tokens[:len(tokens)-1], "is a "+parent.Kind().String())
+}
+
+// Delete removes the value at path, a JSON Pointer (RFC 6901), from its parent object or array
+// and reports whether there was a value at path. The following members or elements move up
+// by one position. The empty path cannot be deleted.
+func (v *Value) Delete(path string) bool {
+	tokens, err := parsePointer(path)
+	if err != nil || len(tokens) == 0 {
+		return false
+	}
+	parent := v.Get(path[:len(path)-len(escapeToken(tokens[len(tokens)-1]))-1])
+	last := tokens[len(tokens)-1]
+	switch parent.Kind() {
+	case KindObject:
+		i := parent.lookup(last)
+		if i < 0 {
+			return false
+		}
+		parent.keys = append(parent.keys[:i], parent.keys[i+1:]...)
+		parent.values = append(parent.values[:i], parent.values[i+1:]...)
+		parent.index = nil
+		return true
+	case KindArray:
+		i, ok := arrayIndex(last)
+		if !ok || i >= len(parent.values) {
+			return false
+		}
+		parent.values = append(parent.values[:i], parent.values[i+1:]...)
+		return true
+	}
+	return false
+}
+
+func pathError(path string, tokens []string, msg string) error {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(escapeToken(token))
+	}
+	return fmt.Errorf("json: cannot set %q: %q %s", path, b.String(), msg)
+}
+
+// parsePointer splits the JSON Pointer path into its unescaped reference tokens.
+func parsePointer(path string) ([]string, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if path[0] != '/' {
+		return nil, fmt.Errorf("json: invalid JSON Pointer %q: must be empty or start with '/'", path)
+	}
+	tokens := strings.Split(path[1:], "/")
+	for i, token := range tokens {
+		if strings.IndexByte(token, '~') < 0 {
+			continue
+		}
+		var b strings.Builder
+		for j := 0; j < len(token); j++ {
+			if token[j] != '~' {
+				b.WriteByte(token[j])
+				continue
+			}
+			j++
+			switch {
+			case j < len(token) && token[j] == '0':
+				b.WriteByte('~')
+			case j < len(token) && token[j] == '1':
+				b.WriteByte('/')
+			default:
+				return nil, fmt.Errorf("json: invalid JSON Pointer %q: invalid escape sequence", path)
+			}
+		}
+		tokens[i] = b.String()
+	}
+	return tokens, nil
+}
+
+func escapeToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// arrayIndex parses an array index token, which has no sign and no leading zeros.
+func arrayIndex(token string) (int, bool) {
+	if token == "" || (token[0] == '0' && len(token) > 1) {
+		return 0, false
+	}
+	for i := 0; i < len(token); i++ {
+		if token[i] < '0' || token[i] > '9' {
+			return 0, false
+		}
+	}
+	i, err := strconv.Atoi(token)
+	return i, err == nil
+}
@@ -0,0 +1,105 @@
+package encoder
+
+import (
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json/internal/errors"
+	"github.com/goccy/go-json/internal/runtime"
+)
+
+var (
+	bigIntPtrType   = runtime.Type2RType(reflect.TypeOf((*big.Int)(nil)))
+	bigFloatPtrType = runtime.Type2RType(reflect.TypeOf((*big.Float)(nil)))
+	bigRatPtrType   = runtime.Type2RType(reflect.TypeOf((*big.Rat)(nil)))
+)
+
+// isBigNumberPtrType reports whether typ is *big.Int, *big.Float or *big.Rat.
+// These types are encoded as JSON numbers by appendBigNumber, so the compiler treats them like json.Marshaler.
+func isBigNumberPtrType(typ *runtime.Type) bool {
+	return typ == bigIntPtrType || typ == bigFloatPtrType || typ == bigRatPtrType
+}
+
+// appendBigNumber appends v as a JSON number if it is a big number.
+// ok is false if v is of another type.
+// A big.Rat that has no finite decimal representation is encoded as a "a/b" string.
+func appendBigNumber(ctx *RuntimeContext, b []byte, v interface{}) (_ []byte, ok bool, err error) {
+	switch n := v.(type) {
+	case *big.Int:
+		if n == nil {
+			return AppendNull(ctx, b), true, nil
+		}
+		return n.Append(b, 10), true, nil
+	case *big.Float:
+		if n == nil {
+			return AppendNull(ctx, b), true, nil
+		}
+		if n.IsInf() {
+			return nil, true, &errors.UnsupportedValueError{
+				Value: reflect.ValueOf(v),
+				Str:   n.String(),
+			}
+		}
+		return appendBigFloat(b, n), true, nil
+	case *big.Rat:
+		if n == nil {
+			return AppendNull(ctx, b), true, nil
+		}
+		if n.IsInt() {
+			return n.Num().Append(b, 10), true, nil
+		}
+		if prec, exact := ratDecimalPrec(n); exact {
+			return append(b, n.FloatString(prec)...), true, nil
+		}
+		b = append(b, '"')
+		b = append(b, n.String()...)
+		return append(b, '"'), true, nil
+	}
+	return b, false, nil
+}
+
+// appendBigFloat formats f with the shortest exact representation.
+// Like float64 values, exponent notation is only used for very large or small magnitudes.
+func appendBigFloat(b []byte, f *big.Float) []byte {
+	if f.Sign() == 0 {
+		return append(b, '0')
+	}
+	e := f.Text('e', -1)
+	exp, _ := strconv.Atoi(e[strings.IndexByte(e, 'e')+1:])
+	if exp < -6 || exp >= 21 {
+		return append(b, e...)
+	}
+	return f.Append(b, 'f', -1)
+}
+
+// ratDecimalPrec returns the number of fractional digits needed to write r exactly.
+// exact is false if the denominator has prime factors other than 2 and 5.
+func ratDecimalPrec(r *big.Rat) (int, bool) {
+	d := new(big.Int).Set(r.Denom())
+	two, five := big.NewInt(2), big.NewInt(5)
+	q, m := new(big.Int), new(big.Int)
+	var n2, n5 int
+	for {
+		if q.QuoRem(d, two, m); m.Sign() != 0 {
+			break
+		}
+		d.Set(q)
+		n2++
+	}
+	for {
+		if q.QuoRem(d, five, m); m.Sign() != 0 {
+			break
+		}
+		d.Set(q)
+		n5++
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return 0, false
+	}
+	if n2 > n5 {
+		return n2, true
+	}
+	return n5, true
+}
@@ -3,6 +3,7 @@ package encoder
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"unsafe"
 
 	"github.com/goccy/go-json/internal/runtime"
@@ -56,6 +57,10 @@ const (
 	CodeKindMarshalJSON
 	CodeKindMarshalText
 	CodeKindRecursive
+	CodeKindValue
+	CodeKindOptional
+	CodeKindOrderedMap
+	CodeKindSensitive
 )
 
 type IntCode struct {
@@ -119,9 +124,10 @@ func (c *UintCode) Filter(_ *FieldQuery) Code {
 }
 
 type FloatCode struct {
-	typ     *runtime.Type
-	bitSize uint8
-	isPtr   bool
+	typ      *runtime.Type
+	bitSize  uint8
+	isString bool
+	isPtr    bool
 }
 
 func (c *FloatCode) Kind() CodeKind {
@@ -138,6 +144,13 @@ func (c *FloatCode) ToOpcode(ctx *compileContext) Opcodes {
 		default:
 			code = newOpCode(ctx, c.typ, OpFloat64Ptr)
 		}
+	case c.isString:
+		switch c.bitSize {
+		case 32:
+			code = newOpCode(ctx, c.typ, OpFloat32String)
+		default:
+			code = newOpCode(ctx, c.typ, OpFloat64String)
+		}
 	default:
 		switch c.bitSize {
 		case 32:
@@ -155,8 +168,9 @@ func (c *FloatCode) Filter(_ *FieldQuery) Code {
 }
 
 type StringCode struct {
-	typ   *runtime.Type
-	isPtr bool
+	typ      *runtime.Type
+	isString bool
+	isPtr    bool
 }
 
 func (c *StringCode) Kind() CodeKind {
@@ -172,6 +186,12 @@ func (c *StringCode) ToOpcode(ctx *compileContext) Opcodes {
 		} else {
 			code = newOpCode(ctx, c.typ, OpStringPtr)
 		}
+	} else if c.isString {
+		if isJSONNumberType {
+			code = newOpCode(ctx, c.typ, OpNumberString)
+		} else {
+			code = newOpCode(ctx, c.typ, OpStringString)
+		}
 	} else {
 		if isJSONNumberType {
 			code = newOpCode(ctx, c.typ, OpNumber)
@@ -188,8 +208,9 @@ func (c *StringCode) Filter(_ *FieldQuery) Code {
 }
 
 type BoolCode struct {
-	typ   *runtime.Type
-	isPtr bool
+	typ      *runtime.Type
+	isString bool
+	isPtr    bool
 }
 
 func (c *BoolCode) Kind() CodeKind {
@@ -201,6 +222,8 @@ func (c *BoolCode) ToOpcode(ctx *compileContext) Opcodes {
 	switch {
 	case c.isPtr:
 		code = newOpCode(ctx, c.typ, OpBoolPtr)
+	case c.isString:
+		code = newOpCode(ctx, c.typ, OpBoolString)
 	default:
 		code = newOpCode(ctx, c.typ, OpBool)
 	}
@@ -213,8 +236,10 @@ func (c *BoolCode) Filter(_ *FieldQuery) Code {
 }
 
 type BytesCode struct {
-	typ   *runtime.Type
-	isPtr bool
+	typ        *runtime.Type
+	isPtr      bool
+	format     runtime.BytesFormat
+	nilAsEmpty bool
 }
 
 func (c *BytesCode) Kind() CodeKind {
@@ -229,6 +254,10 @@ func (c *BytesCode) ToOpcode(ctx *compileContext) Opcodes {
 	default:
 		code = newOpCode(ctx, c.typ, OpBytes)
 	}
+	code.BytesFormat = c.format
+	if c.nilAsEmpty {
+		code.Flags |= NilAsEmptyFlags
+	}
 	ctx.incIndex()
 	return Opcodes{code}
 }
@@ -238,8 +267,9 @@ func (c *BytesCode) Filter(_ *FieldQuery) Code {
 }
 
 type SliceCode struct {
-	typ   *runtime.Type
-	value Code
+	typ        *runtime.Type
+	value      Code
+	nilAsEmpty bool
 }
 
 func (c *SliceCode) Kind() CodeKind {
@@ -252,10 +282,15 @@ func (c *SliceCode) ToOpcode(ctx *compileContext) Opcodes {
 	//             |________|
 	size := c.typ.Elem().Size()
 	header := newSliceHeaderCode(ctx, c.typ)
+	if c.nilAsEmpty {
+		header.Flags |= NilAsEmptyFlags
+	}
 	ctx.incIndex()
 
 	ctx.incIndent()
+	ctx.pushPath("[*]")
 	codes := c.value.ToOpcode(ctx)
+	ctx.popPath()
 	ctx.decIndent()
 
 	codes.First().Flags |= IndirectFlags
@@ -296,7 +331,9 @@ func (c *ArrayCode) ToOpcode(ctx *compileContext) Opcodes {
 	ctx.incIndex()
 
 	ctx.incIndent()
+	ctx.pushPath("[*]")
 	codes := c.value.ToOpcode(ctx)
+	ctx.popPath()
 	ctx.decIndent()
 
 	codes.First().Flags |= IndirectFlags
@@ -321,9 +358,10 @@ func (c *ArrayCode) Filter(_ *FieldQuery) Code {
 }
 
 type MapCode struct {
-	typ   *runtime.Type
-	key   Code
-	value Code
+	typ        *runtime.Type
+	key        Code
+	value      Code
+	nilAsEmpty bool
 }
 
 func (c *MapCode) Kind() CodeKind {
@@ -335,15 +373,24 @@ func (c *MapCode) ToOpcode(ctx *compileContext) Opcodes {
 	//                                     ^                       |
 	//                                     |_______________________|
 	header := newMapHeaderCode(ctx, c.typ)
+	if c.nilAsEmpty {
+		header.Flags |= NilAsEmptyFlags
+	}
 	ctx.incIndex()
 
 	keyCodes := c.key.ToOpcode(ctx)
+	if kind := c.key.Kind(); kind == CodeKindMarshalJSON || kind == CodeKindMarshalText {
+		// like values, pointer keys are loaded through the map key address.
+		keyCodes.First().Flags |= IndirectFlags
+	}
 
 	value := newMapValueCode(ctx, c.typ.Elem(), header)
 	ctx.incIndex()
 
 	ctx.incIndent()
+	ctx.pushPath(".*")
 	valueCodes := c.value.ToOpcode(ctx)
+	ctx.popPath()
 	ctx.decIndent()
 
 	valueCodes.First().Flags |= IndirectFlags
@@ -539,6 +586,10 @@ func (c *StructCode) removeFieldsByTags(tags runtime.StructTags) {
 		if tags.ExistsKey(field.key) {
 			continue
 		}
+		if field.isUnionTag {
+			// the discriminator is only written for the union member itself, not for structs embedding it.
+			continue
+		}
 		fields = append(fields, field)
 	}
 	c.fields = fields
@@ -582,6 +633,7 @@ func (c *StructCode) Filter(query *FieldQuery) Code {
 			isNilCheck:         field.isNilCheck,
 			isAddrForMarshaler: field.isAddrForMarshaler,
 			isNextOpPtrType:    field.isNextOpPtrType,
+			isSensitive:        field.isSensitive,
 		}
 		if len(query.Fields) > 0 {
 			fieldCode.value = fieldCode.value.Filter(query)
@@ -611,6 +663,8 @@ type StructFieldCode struct {
 	isAddrForMarshaler bool
 	isNextOpPtrType    bool
 	isMarshalerContext bool
+	isUnionTag         bool
+	isSensitive        bool
 }
 
 func (c *StructFieldCode) getStruct() *StructCode {
@@ -635,7 +689,7 @@ func (c *StructFieldCode) getAnonymousStruct() *StructCode {
 
 func optimizeStructHeader(code *Opcode, tag *runtime.StructTag) OpType {
 	headType := code.ToHeaderType(tag.IsString)
-	if tag.IsOmitEmpty {
+	if tag.IsOmitEmpty || code.Op == OpOptional {
 		headType = headType.HeadToOmitEmptyHead()
 	}
 	return headType
@@ -643,7 +697,7 @@ func optimizeStructHeader(code *Opcode, tag *runtime.StructTag) OpType {
 
 func optimizeStructField(code *Opcode, tag *runtime.StructTag) OpType {
 	fieldType := code.ToFieldType(tag.IsString)
-	if tag.IsOmitEmpty {
+	if tag.IsOmitEmpty || code.Op == OpOptional {
 		fieldType = fieldType.FieldToOmitEmptyField()
 	}
 	return fieldType
@@ -657,6 +711,8 @@ func (c *StructFieldCode) headerOpcodes(ctx *compileContext, field *Opcode, valu
 		field.Flags |= MarshalerContextFlags
 	}
 	field.NumBitSize = value.NumBitSize
+	field.BytesFormat = value.BytesFormat
+	field.Flags |= value.Flags & NilAsEmptyFlags
 	field.PtrNum = value.PtrNum
 	field.FieldQuery = value.FieldQuery
 	fieldCodes := Opcodes{field}
@@ -677,6 +733,8 @@ func (c *StructFieldCode) fieldOpcodes(ctx *compileContext, field *Opcode, value
 		field.Flags |= MarshalerContextFlags
 	}
 	field.NumBitSize = value.NumBitSize
+	field.BytesFormat = value.BytesFormat
+	field.Flags |= value.Flags & NilAsEmptyFlags
 	field.PtrNum = value.PtrNum
 	field.FieldQuery = value.FieldQuery
 
@@ -735,6 +793,9 @@ func (c *StructFieldCode) flags() OpFlags {
 	if c.isMarshalerContext {
 		flags |= MarshalerContextFlags
 	}
+	if c.isUnionTag {
+		flags |= UnionTagFlags
+	}
 	return flags
 }
 
@@ -748,6 +809,34 @@ func (c *StructFieldCode) toValueOpcodes(ctx *compileContext) Opcodes {
 	return c.value.ToOpcode(ctx)
 }
 
+// redactableValueOpcodes compiles the value of the field like toValueOpcodes.
+// When redacting, the value of a sensitive field is replaced by an OpRedact code with its path,
+// and the promoted fields of a sensitive embedded struct are redacted one by one.
+func (c *StructFieldCode) redactableValueOpcodes(ctx *compileContext, field *Opcode) (Opcodes, bool) {
+	if !ctx.redact {
+		return c.toValueOpcodes(ctx), false
+	}
+	isSensitive := c.isSensitive || ctx.isSensitive
+	if c.getAnonymousStruct() != nil {
+		isSensitiveStruct := ctx.isSensitive
+		ctx.isSensitive = isSensitive
+		codes := c.toValueOpcodes(ctx)
+		ctx.isSensitive = isSensitiveStruct
+		return codes, false
+	}
+	ctx.pushPath("." + c.key)
+	defer ctx.popPath()
+	if !isSensitive {
+		return c.toValueOpcodes(ctx), false
+	}
+	// embedded types other than structs are written with their key like other fields.
+	field.Flags &^= AnonymousKeyFlags
+	code := newOpCode(ctx, c.typ, OpRedact)
+	code.Key = strings.Join(ctx.path, "")
+	ctx.incIndex()
+	return Opcodes{code}, true
+}
+
 func (c *StructFieldCode) ToOpcode(ctx *compileContext, isFirstField, isEndField bool) Opcodes {
 	field := &Opcode{
 		Idx:        opcodeOffset(ctx.ptrIndex),
@@ -760,7 +849,7 @@ func (c *StructFieldCode) ToOpcode(ctx *compileContext, isFirstField, isEndField
 		DisplayKey: c.key,
 	}
 	ctx.incIndex()
-	valueCodes := c.toValueOpcodes(ctx)
+	valueCodes, isRedacted := c.redactableValueOpcodes(ctx, field)
 	if isFirstField {
 		codes := c.headerOpcodes(ctx, field, valueCodes)
 		if isEndField {
@@ -770,7 +859,7 @@ func (c *StructFieldCode) ToOpcode(ctx *compileContext, isFirstField, isEndField
 	}
 	codes := c.fieldOpcodes(ctx, field, valueCodes)
 	if isEndField {
-		if isEnableStructEndOptimization(c.value) {
+		if !isRedacted && isEnableStructEndOptimization(c.value) {
 			field.Op = field.Op.FieldToEnd()
 		} else {
 			codes = c.addStructEndCode(ctx, codes)
@@ -791,7 +880,7 @@ func (c *StructFieldCode) ToAnonymousOpcode(ctx *compileContext, isFirstField, i
 		DisplayKey: c.key,
 	}
 	ctx.incIndex()
-	valueCodes := c.toValueOpcodes(ctx)
+	valueCodes, _ := c.redactableValueOpcodes(ctx, field)
 	if isFirstField {
 		return c.headerOpcodes(ctx, field, valueCodes)
 	}
@@ -836,6 +925,10 @@ func (c *InterfaceCode) ToOpcode(ctx *compileContext) Opcodes {
 	if c.typ.NumMethod() > 0 {
 		code.Flags |= NonEmptyInterfaceFlags
 	}
+	if ctx.redact {
+		// the paths of the redacted values in the dynamic value start at this path.
+		code.Key = strings.Join(ctx.path, "")
+	}
 	ctx.incIndex()
 	return Opcodes{code}
 }
@@ -848,6 +941,150 @@ func (c *InterfaceCode) Filter(query *FieldQuery) Code {
 	}
 }
 
+// ValueCode encodes a json.Value tree with a single opcode.
+type ValueCode struct {
+	typ   *runtime.Type
+	isPtr bool
+}
+
+func (c *ValueCode) Kind() CodeKind {
+	return CodeKindValue
+}
+
+func (c *ValueCode) ToOpcode(ctx *compileContext) Opcodes {
+	var code *Opcode
+	switch {
+	case c.isPtr:
+		code = newOpCode(ctx, c.typ, OpValuePtr)
+	default:
+		code = newOpCode(ctx, c.typ, OpValue)
+	}
+	ctx.incIndex()
+	return Opcodes{code}
+}
+
+func (c *ValueCode) Filter(_ *FieldQuery) Code {
+	return c
+}
+
+// OptionalCode encodes the value of an Optional if it is set, and null otherwise.
+type OptionalCode struct {
+	typ      *runtime.Type
+	value    Code
+	offset   uintptr // offset of the value in the Optional
+	isString bool    // the value is encoded as a JSON string
+	isPtr    bool
+}
+
+func (c *OptionalCode) Kind() CodeKind {
+	return CodeKindOptional
+}
+
+func (c *OptionalCode) ToOpcode(ctx *compileContext) Opcodes {
+	// header => value
+	//   |          |
+	//   |__________|=> next (if not set)
+	var header *Opcode
+	switch {
+	case c.isPtr:
+		header = newOpCode(ctx, c.typ, OpOptionalPtr)
+	default:
+		header = newOpCode(ctx, c.typ, OpOptional)
+	}
+	header.Offset = uint32(c.offset)
+	ctx.incIndex()
+
+	codes := c.value.ToOpcode(ctx)
+	codes.First().Flags |= IndirectFlags
+
+	header.Next = codes.First()
+	header.End = codes.Last()
+	return Opcodes{header}.Add(codes...)
+}
+
+func (c *OptionalCode) Filter(query *FieldQuery) Code {
+	return &OptionalCode{
+		typ:      c.typ,
+		value:    c.value.Filter(query),
+		offset:   c.offset,
+		isString: c.isString,
+		isPtr:    c.isPtr,
+	}
+}
+
+// OrderedMapCode encodes the keys and values of an OrderedMap in order.
+type OrderedMapCode struct {
+	typ          *runtime.Type
+	value        Code
+	valuesOffset uintptr // offset of the values in the OrderedMap
+	isPtr        bool
+}
+
+func (c *OrderedMapCode) Kind() CodeKind {
+	return CodeKindOrderedMap
+}
+
+func (c *OrderedMapCode) ToOpcode(ctx *compileContext) Opcodes {
+	// header => value => elem => end
+	//             ^        |
+	//             |________|
+	header := newOrderedMapHeaderCode(ctx, c.typ, c.valuesOffset)
+	if c.isPtr {
+		header.Op = OpOrderedMapPtr
+	}
+	ctx.incIndex()
+
+	ctx.incIndent()
+	ctx.pushPath(".*")
+	codes := c.value.ToOpcode(ctx)
+	ctx.popPath()
+	ctx.decIndent()
+
+	codes.First().Flags |= IndirectFlags
+	elemCode := newOrderedMapElemCode(ctx, c.typ, header)
+	ctx.incIndex()
+	end := newOpCode(ctx, c.typ, OpOrderedMapEnd)
+	ctx.incIndex()
+	header.End = end
+	header.Next = codes.First()
+	codes.Last().Next = elemCode
+	elemCode.Next = codes.First()
+	elemCode.End = end
+	return Opcodes{header}.Add(codes...).Add(elemCode).Add(end)
+}
+
+func (c *OrderedMapCode) Filter(_ *FieldQuery) Code {
+	return c
+}
+
+// SensitiveCode encodes a list element, map value or top-level value whose type is registered as sensitive.
+// When redacting, it is replaced by an OpRedact code with its path.
+type SensitiveCode struct {
+	typ   *runtime.Type
+	value Code
+}
+
+func (c *SensitiveCode) Kind() CodeKind {
+	return CodeKindSensitive
+}
+
+func (c *SensitiveCode) ToOpcode(ctx *compileContext) Opcodes {
+	if !ctx.redact {
+		return c.value.ToOpcode(ctx)
+	}
+	code := newOpCode(ctx, c.typ, OpRedact)
+	code.Key = strings.Join(ctx.path, "")
+	ctx.incIndex()
+	return Opcodes{code}
+}
+
+func (c *SensitiveCode) Filter(query *FieldQuery) Code {
+	return &SensitiveCode{
+		typ:   c.typ,
+		value: c.value.Filter(query),
+	}
+}
+
 type MarshalJSONCode struct {
 	typ                *runtime.Type
 	fieldQuery         *FieldQuery
@@ -999,6 +1236,12 @@ func convertPtrOp(code *Opcode) OpType {
 		return OpMarshalTextPtr
 	case OpInterface:
 		return OpInterfacePtr
+	case OpValue:
+		return OpValuePtr
+	case OpOptional:
+		return OpOptionalPtr
+	case OpOrderedMap:
+		return OpOrderedMapPtr
 	case OpRecursive:
 		return OpRecursivePtr
 	}
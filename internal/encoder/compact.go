@@ -29,7 +29,7 @@ func Compact(buf *bytes.Buffer, src []byte, escape bool) error {
 		return errors.ErrUnexpectedEndOfJSON("", 0)
 	}
 	buf.Grow(len(src))
-	dst := buf.Bytes()
+	dst := buf.Bytes()[buf.Len():]
 
 	ctx := TakeRuntimeContext()
 	ctxBuf := ctx.Buf[:0]
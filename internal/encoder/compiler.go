@@ -5,6 +5,7 @@ import (
 	"encoding"
 	"encoding/json"
 	"reflect"
+	"sync"
 	"sync/atomic"
 	"unsafe"
 
@@ -24,6 +25,11 @@ var (
 	cachedOpcodeSets       []*OpcodeSet
 	cachedOpcodeMap        unsafe.Pointer // map[uintptr]*OpcodeSet
 	typeAddr               *runtime.TypeAddr
+
+	// tagKeyOpcodeSets caches the code sets compiled with TagKeyOption for each tag key,
+	// apart from the code sets of the json tags.
+	tagKeyOpcodeSets   = map[string]map[uintptr]*OpcodeSet{}
+	tagKeyOpcodeSetsMu sync.RWMutex
 )
 
 func init() {
@@ -34,6 +40,15 @@ func init() {
 	cachedOpcodeSets = make([]*OpcodeSet, typeAddr.AddrRange>>typeAddr.AddrShift)
 }
 
+// ClearCache discards the compiled code sets, so that the types are compiled again on their next use.
+func ClearCache() {
+	clearCachedOpcodeSets()
+	atomic.StorePointer(&cachedOpcodeMap, nil)
+	tagKeyOpcodeSetsMu.Lock()
+	tagKeyOpcodeSets = map[string]map[uintptr]*OpcodeSet{}
+	tagKeyOpcodeSetsMu.Unlock()
+}
+
 func loadOpcodeMap() map[uintptr]*OpcodeSet {
 	p := atomic.LoadPointer(&cachedOpcodeMap)
 	return *(*map[uintptr]*OpcodeSet)(unsafe.Pointer(&p))
@@ -63,10 +78,47 @@ func compileToGetCodeSetSlowPath(typeptr uintptr) (*OpcodeSet, error) {
 	return codeSet, nil
 }
 
+func compileToGetCodeSetWithTagKey(ctx *RuntimeContext, typeptr uintptr) (*OpcodeSet, error) {
+	tagKey := ctx.Option.TagKey
+	tagKeyOpcodeSetsMu.RLock()
+	codeSet, exists := tagKeyOpcodeSets[tagKey][typeptr]
+	tagKeyOpcodeSetsMu.RUnlock()
+	if !exists {
+		c := newCompiler()
+		c.tagKey = tagKey
+		compiled, err := c.compile(typeptr)
+		if err != nil {
+			return nil, err
+		}
+		tagKeyOpcodeSetsMu.Lock()
+		if tagKeyOpcodeSets[tagKey] == nil {
+			tagKeyOpcodeSets[tagKey] = map[uintptr]*OpcodeSet{}
+		}
+		tagKeyOpcodeSets[tagKey][typeptr] = compiled
+		tagKeyOpcodeSetsMu.Unlock()
+		codeSet = compiled
+	}
+	return getFilteredCodeSetIfNeeded(ctx, codeSet)
+}
+
 func getFilteredCodeSetIfNeeded(ctx *RuntimeContext, codeSet *OpcodeSet) (*OpcodeSet, error) {
-	if (ctx.Option.Flag & ContextOption) == 0 {
+	if (ctx.Option.Flag & (ContextOption | RedactOption)) == 0 {
 		return codeSet, nil
 	}
+	if (ctx.Option.Flag & ContextOption) != 0 {
+		queryCodeSet, err := getQueryCodeSet(ctx, codeSet)
+		if err != nil {
+			return nil, err
+		}
+		codeSet = queryCodeSet
+	}
+	if (ctx.Option.Flag & RedactOption) == 0 {
+		return codeSet, nil
+	}
+	return getRedactedCodeSet(codeSet)
+}
+
+func getQueryCodeSet(ctx *RuntimeContext, codeSet *OpcodeSet) (*OpcodeSet, error) {
 	query := FieldQueryFromContext(ctx.Option.Context)
 	if query == nil {
 		return codeSet, nil
@@ -84,8 +136,26 @@ func getFilteredCodeSetIfNeeded(ctx *RuntimeContext, codeSet *OpcodeSet) (*Opcod
 	return queryCodeSet, nil
 }
 
+// getRedactedCodeSet returns the variant of codeSet that writes a mask in place of sensitive values.
+// It is compiled on first use, so encoding without RedactOption does not pay for redaction.
+func getRedactedCodeSet(codeSet *OpcodeSet) (*OpcodeSet, error) {
+	if redactedCodeSet := codeSet.getRedactedCodeSet(); redactedCodeSet != nil {
+		return redactedCodeSet, nil
+	}
+	c := newCompiler()
+	c.redact = true
+	redactedCodeSet, err := c.codeToOpcodeSet(codeSet.Type, codeSet.Code)
+	if err != nil {
+		return nil, err
+	}
+	codeSet.setRedactedCodeSet(redactedCodeSet)
+	return redactedCodeSet, nil
+}
+
 type Compiler struct {
 	structTypeToCode map[uintptr]*StructCode
+	redact           bool   // replace the values of sensitive fields with OpRedact
+	tagKey           string // key of the struct tags read before json
 }
 
 func newCompiler() *Compiler {
@@ -101,13 +171,15 @@ func (c *Compiler) compile(typeptr uintptr) (*OpcodeSet, error) {
 	if err != nil {
 		return nil, err
 	}
-	return c.codeToOpcodeSet(typ, code)
+	return c.codeToOpcodeSet(typ, c.sensitiveCode(typ, code))
 }
 
 func (c *Compiler) codeToOpcodeSet(typ *runtime.Type, code Code) (*OpcodeSet, error) {
 	noescapeKeyCode := c.codeToOpcode(&compileContext{
 		structTypeToCodes: map[uintptr]Opcodes{},
 		recursiveCodes:    &Opcodes{},
+		redact:            c.redact,
+		path:              []string{"$"},
 	}, typ, code)
 	if err := noescapeKeyCode.Validate(); err != nil {
 		return nil, err
@@ -116,6 +188,8 @@ func (c *Compiler) codeToOpcodeSet(typ *runtime.Type, code Code) (*OpcodeSet, er
 		structTypeToCodes: map[uintptr]Opcodes{},
 		recursiveCodes:    &Opcodes{},
 		escapeKey:         true,
+		redact:            c.redact,
+		path:              []string{"$"},
 	}, typ, code)
 	noescapeKeyCode = copyOpcode(noescapeKeyCode)
 	escapeKeyCode = copyOpcode(escapeKeyCode)
@@ -139,6 +213,18 @@ func (c *Compiler) codeToOpcodeSet(typ *runtime.Type, code Code) (*OpcodeSet, er
 
 func (c *Compiler) typeToCode(typ *runtime.Type) (Code, error) {
 	switch {
+	case typ == valueType:
+		return c.valueCode(typ, false)
+	case typ.Kind() == reflect.Ptr && typ.Elem() == valueType:
+		return c.valueCode(typ.Elem(), true)
+	case runtime.IsOptional(typ):
+		return c.optionalCode(typ, false)
+	case typ.Kind() == reflect.Ptr && runtime.IsOptional(typ.Elem()):
+		return c.optionalCode(typ.Elem(), true)
+	case runtime.IsOrderedMap(typ):
+		return c.orderedMapCode(typ, false)
+	case typ.Kind() == reflect.Ptr && runtime.IsOrderedMap(typ.Elem()):
+		return c.orderedMapCode(typ.Elem(), true)
 	case c.implementsMarshalJSON(typ):
 		return c.marshalJSONCode(typ)
 	case c.implementsMarshalText(typ):
@@ -214,6 +300,12 @@ func (c *Compiler) typeToCode(typ *runtime.Type) (Code, error) {
 
 func (c *Compiler) typeToCodeWithPtr(typ *runtime.Type, isPtr bool) (Code, error) {
 	switch {
+	case typ == valueType:
+		return c.valueCode(typ, false)
+	case runtime.IsOptional(typ):
+		return c.optionalCode(typ, false)
+	case runtime.IsOrderedMap(typ):
+		return c.orderedMapCode(typ, false)
 	case c.implementsMarshalJSON(typ):
 		return c.marshalJSONCode(typ)
 	case c.implementsMarshalText(typ):
@@ -405,6 +497,41 @@ func (c *Compiler) interfaceCode(typ *runtime.Type, isPtr bool) (*InterfaceCode,
 	return &InterfaceCode{typ: typ, isPtr: isPtr}, nil
 }
 
+//nolint:unparam
+func (c *Compiler) valueCode(typ *runtime.Type, isPtr bool) (*ValueCode, error) {
+	return &ValueCode{typ: typ, isPtr: isPtr}, nil
+}
+
+// optionalCode compiles the value of the Optional type typ like the element of an array.
+func (c *Compiler) optionalCode(typ *runtime.Type, isPtr bool) (*OptionalCode, error) {
+	field := typ.Field(1)
+	valueType := runtime.Type2RType(field.Type)
+	code, err := c.listElemCode(valueType)
+	if err != nil {
+		return nil, err
+	}
+	if code.Kind() == CodeKindStruct {
+		structCode := code.(*StructCode)
+		structCode.enableIndirect()
+	}
+	return &OptionalCode{typ: typ, value: c.sensitiveCode(valueType, code), offset: field.Offset, isPtr: isPtr}, nil
+}
+
+// orderedMapCode compiles the values of the OrderedMap type typ like the elements of a slice.
+func (c *Compiler) orderedMapCode(typ *runtime.Type, isPtr bool) (*OrderedMapCode, error) {
+	field := typ.Field(1)
+	valueType := runtime.Type2RType(field.Type.Elem())
+	code, err := c.listElemCode(valueType)
+	if err != nil {
+		return nil, err
+	}
+	if code.Kind() == CodeKindStruct {
+		structCode := code.(*StructCode)
+		structCode.enableIndirect()
+	}
+	return &OrderedMapCode{typ: typ, value: c.sensitiveCode(valueType, code), valuesOffset: field.Offset, isPtr: isPtr}, nil
+}
+
 //nolint:unparam
 func (c *Compiler) marshalJSONCode(typ *runtime.Type) (*MarshalJSONCode, error) {
 	return &MarshalJSONCode{
@@ -446,7 +573,7 @@ func (c *Compiler) sliceCode(typ *runtime.Type) (*SliceCode, error) {
 		structCode := code.(*StructCode)
 		structCode.enableIndirect()
 	}
-	return &SliceCode{typ: typ, value: code}, nil
+	return &SliceCode{typ: typ, value: c.sensitiveCode(elem, code)}, nil
 }
 
 func (c *Compiler) arrayCode(typ *runtime.Type) (*ArrayCode, error) {
@@ -459,7 +586,7 @@ func (c *Compiler) arrayCode(typ *runtime.Type) (*ArrayCode, error) {
 		structCode := code.(*StructCode)
 		structCode.enableIndirect()
 	}
-	return &ArrayCode{typ: typ, value: code}, nil
+	return &ArrayCode{typ: typ, value: c.sensitiveCode(elem, code)}, nil
 }
 
 func (c *Compiler) mapCode(typ *runtime.Type) (*MapCode, error) {
@@ -475,7 +602,16 @@ func (c *Compiler) mapCode(typ *runtime.Type) (*MapCode, error) {
 		structCode := valueCode.(*StructCode)
 		structCode.enableIndirect()
 	}
-	return &MapCode{typ: typ, key: keyCode, value: valueCode}, nil
+	return &MapCode{typ: typ, key: keyCode, value: c.sensitiveCode(typ.Elem(), valueCode)}, nil
+}
+
+// sensitiveCode wraps the code of a list element, map value or top-level value in a SensitiveCode
+// if typ is registered as sensitive. Struct fields are redacted by their StructFieldCode instead.
+func (c *Compiler) sensitiveCode(typ *runtime.Type, code Code) Code {
+	if !runtime.IsSensitive(typ) {
+		return code
+	}
+	return &SensitiveCode{typ: typ, value: code}
 }
 
 func (c *Compiler) listElemCode(typ *runtime.Type) (Code, error) {
@@ -503,6 +639,9 @@ func (c *Compiler) listElemCode(typ *runtime.Type) (Code, error) {
 
 func (c *Compiler) mapKeyCode(typ *runtime.Type) (Code, error) {
 	switch {
+	case isBigNumberPtrType(typ):
+		// object keys must be strings, so big numbers use their text form here.
+		return c.marshalTextCode(typ)
 	case c.implementsMarshalJSON(typ):
 		return c.marshalJSONCode(typ)
 	case c.implementsMarshalText(typ):
@@ -606,6 +745,15 @@ func (c *Compiler) structCode(typ *runtime.Type, isPtr bool) (*StructCode, error
 		}
 		fields = append(fields, field)
 	}
+	if member, ok := runtime.UnionMemberOf(typ); ok && !tags.ExistsKey(member.Key) {
+		tagField := c.unionTagFieldCode(typ, member.Key)
+		if indirect {
+			fields = append([]*StructFieldCode{tagField}, fields...)
+		} else {
+			// the only field of a pointer-shaped struct must stay the head field, so the discriminator is written last.
+			fields = append(fields, tagField)
+		}
+	}
 	fieldMap := c.getFieldMap(fields)
 	duplicatedFieldMap := c.getDuplicatedFieldMap(fieldMap)
 	code.fields = c.filteredDuplicatedFields(fields, duplicatedFieldMap)
@@ -617,6 +765,9 @@ func (c *Compiler) structCode(typ *runtime.Type, isPtr bool) (*StructCode, error
 }
 
 func (c *Compiler) structFieldCode(structCode *StructCode, tag *runtime.StructTag, isPtr, isOnlyOneFirstField bool) (*StructFieldCode, error) {
+	if tag.Err != nil {
+		return nil, tag.Err
+	}
 	field := tag.Field
 	fieldType := runtime.Type2RType(field.Type)
 	isIndirectSpecialCase := isPtr && isOnlyOneFirstField
@@ -680,11 +831,84 @@ func (c *Compiler) structFieldCode(structCode *StructCode, tag *runtime.StructTa
 		case CodeKindPtr, CodeKindInterface:
 			fieldCode.isNextOpPtrType = true
 		}
+		if tag.BytesFormat != runtime.BytesFormatDefault {
+			setBytesFormat(code, tag.BytesFormat)
+		}
+		if tag.IsNilAsEmpty {
+			setNilAsEmpty(code)
+		}
+		if optionalCode, ok := code.(*OptionalCode); ok && tag.IsString {
+			optionalCode.isString = setString(optionalCode.value)
+		}
 		fieldCode.value = code
 	}
+	fieldCode.isSensitive = tag.IsSensitive || runtime.IsSensitive(fieldType)
 	return fieldCode, nil
 }
 
+// setBytesFormat sets the format of the []byte value of a field, which can be behind pointers.
+func setBytesFormat(code Code, format runtime.BytesFormat) {
+	switch code := code.(type) {
+	case *BytesCode:
+		code.format = format
+	case *PtrCode:
+		setBytesFormat(code.value, format)
+	}
+}
+
+// setNilAsEmpty makes the slice or map value of a field, which can be behind pointers,
+// encode as an empty array or object when it is nil. A nil []byte encodes as an empty value of its format.
+func setNilAsEmpty(code Code) {
+	switch code := code.(type) {
+	case *BytesCode:
+		code.nilAsEmpty = true
+	case *SliceCode:
+		code.nilAsEmpty = true
+	case *MapCode:
+		code.nilAsEmpty = true
+	case *PtrCode:
+		setNilAsEmpty(code.value)
+	}
+}
+
+// setString makes the value of an Optional field encode as a JSON string,
+// like the string tag option does for fields of string, number and bool types.
+// It reports false for values of other types, which are encoded as they are.
+func setString(code Code) bool {
+	switch code := code.(type) {
+	case *IntCode:
+		code.isString = !code.isPtr
+		return code.isString
+	case *UintCode:
+		code.isString = !code.isPtr
+		return code.isString
+	case *FloatCode:
+		code.isString = !code.isPtr
+		return code.isString
+	case *StringCode:
+		code.isString = !code.isPtr
+		return code.isString
+	case *BoolCode:
+		code.isString = !code.isPtr
+		return code.isString
+	case *SensitiveCode:
+		return setString(code.value)
+	}
+	return false
+}
+
+// unionTagFieldCode creates the field that writes the discriminator property of
+// a struct type registered as a union member before its other fields.
+func (c *Compiler) unionTagFieldCode(typ *runtime.Type, key string) *StructFieldCode {
+	return &StructFieldCode{
+		typ:        typ,
+		key:        key,
+		tag:        &runtime.StructTag{Key: key},
+		value:      &MarshalJSONCode{typ: typ},
+		isUnionTag: true,
+	}
+}
+
 func (c *Compiler) isAssignableIndirect(fieldCode *StructFieldCode, isPtr bool) bool {
 	if isPtr {
 		return false
@@ -798,16 +1022,7 @@ func (c *Compiler) isTaggedKeyOnly(fields []*StructFieldCode) bool {
 }
 
 func (c *Compiler) typeToStructTags(typ *runtime.Type) runtime.StructTags {
-	tags := runtime.StructTags{}
-	fieldNum := typ.NumField()
-	for i := 0; i < fieldNum; i++ {
-		field := typ.Field(i)
-		if runtime.IsIgnoredStructField(field) {
-			continue
-		}
-		tags = append(tags, runtime.StructTagFromField(field))
-	}
-	return tags
+	return runtime.StructFieldTags(typ, c.tagKey)
 }
 
 // *struct{ field T } => struct { field *T }
@@ -866,7 +1081,7 @@ func (c *Compiler) isNilableType(typ *runtime.Type) bool {
 }
 
 func (c *Compiler) implementsMarshalJSONType(typ *runtime.Type) bool {
-	return typ.Implements(marshalJSONType) || typ.Implements(marshalJSONContextType)
+	return typ.Implements(marshalJSONType) || typ.Implements(marshalJSONContextType) || isBigNumberPtrType(typ)
 }
 
 func (c *Compiler) isPtrMarshalJSONType(typ *runtime.Type) bool {
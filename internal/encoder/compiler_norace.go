@@ -4,6 +4,9 @@
 package encoder
 
 func CompileToGetCodeSet(ctx *RuntimeContext, typeptr uintptr) (*OpcodeSet, error) {
+	if (ctx.Option.Flag & TagKeyOption) != 0 {
+		return compileToGetCodeSetWithTagKey(ctx, typeptr)
+	}
 	if typeptr > typeAddr.MaxTypeAddr || typeptr < typeAddr.BaseTypeAddr {
 		codeSet, err := compileToGetCodeSetSlowPath(typeptr)
 		if err != nil {
@@ -30,3 +33,9 @@ func CompileToGetCodeSet(ctx *RuntimeContext, typeptr uintptr) (*OpcodeSet, erro
 	cachedOpcodeSets[index] = codeSet
 	return filtered, nil
 }
+
+func clearCachedOpcodeSets() {
+	for i := range cachedOpcodeSets {
+		cachedOpcodeSets[i] = nil
+	}
+}
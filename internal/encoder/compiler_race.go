@@ -10,6 +10,9 @@ import (
 var setsMu sync.RWMutex
 
 func CompileToGetCodeSet(ctx *RuntimeContext, typeptr uintptr) (*OpcodeSet, error) {
+	if (ctx.Option.Flag & TagKeyOption) != 0 {
+		return compileToGetCodeSetWithTagKey(ctx, typeptr)
+	}
 	if typeptr > typeAddr.MaxTypeAddr || typeptr < typeAddr.BaseTypeAddr {
 		codeSet, err := compileToGetCodeSetSlowPath(typeptr)
 		if err != nil {
@@ -43,3 +46,11 @@ func CompileToGetCodeSet(ctx *RuntimeContext, typeptr uintptr) (*OpcodeSet, erro
 	setsMu.Unlock()
 	return filtered, nil
 }
+
+func clearCachedOpcodeSets() {
+	setsMu.Lock()
+	for i := range cachedOpcodeSets {
+		cachedOpcodeSets[i] = nil
+	}
+	setsMu.Unlock()
+}
@@ -13,6 +13,9 @@ type compileContext struct {
 	ptrIndex          int
 	indent            uint32
 	escapeKey         bool
+	redact            bool     // replace the values of sensitive fields with OpRedact
+	isSensitive       bool     // compiling the promoted fields of a sensitive embedded struct
+	path              []string // JSONPath of the value being compiled when redacting
 	structTypeToCodes map[uintptr]Opcodes
 	recursiveCodes    *Opcodes
 }
@@ -25,6 +28,18 @@ func (c *compileContext) decIndent() {
 	c.indent--
 }
 
+func (c *compileContext) pushPath(elem string) {
+	if c.redact {
+		c.path = append(c.path, elem)
+	}
+}
+
+func (c *compileContext) popPath() {
+	if c.redact {
+		c.path = c.path[:len(c.path)-1]
+	}
+}
+
 func (c *compileContext) incIndex() {
 	c.incOpcodeIndex()
 	c.incPtrIndex()
@@ -75,6 +90,7 @@ type RuntimeContext struct {
 	Ptrs       []uintptr
 	KeepRefs   []unsafe.Pointer
 	SeenPtr    []uintptr
+	RedactPath []string // paths of the interface values being encoded with RedactOption
 	BaseIndent uint32
 	Prefix     []byte
 	IndentStr  []byte
@@ -88,9 +104,20 @@ func (c *RuntimeContext) Init(p uintptr, codelen int) {
 	c.Ptrs[0] = p
 	c.KeepRefs = c.KeepRefs[:0]
 	c.SeenPtr = c.SeenPtr[:0]
+	c.RedactPath = c.RedactPath[:0]
 	c.BaseIndent = 0
 }
 
+// Path returns the JSONPath of the value whose compiled path is key.
+// Paths are compiled from the root of the code set, so the values encoded from an interface value
+// get the path of the interface value in place of the leading "$".
+func (c *RuntimeContext) Path(key string) string {
+	if len(c.RedactPath) == 0 {
+		return key
+	}
+	return c.RedactPath[len(c.RedactPath)-1] + key[1:]
+}
+
 func (c *RuntimeContext) Ptr() uintptr {
 	header := (*runtime.SliceHeader)(unsafe.Pointer(&c.Ptrs))
 	return uintptr(header.Data)
@@ -103,6 +103,7 @@ type OpcodeSet struct {
 	EndCode                  *Opcode
 	Code                     Code
 	QueryCache               map[string]*OpcodeSet
+	RedactedCodeSet          *OpcodeSet
 	cacheMu                  sync.RWMutex
 }
 
@@ -119,6 +120,19 @@ func (s *OpcodeSet) setQueryCache(hash string, codeSet *OpcodeSet) {
 	s.cacheMu.Unlock()
 }
 
+func (s *OpcodeSet) getRedactedCodeSet() *OpcodeSet {
+	s.cacheMu.RLock()
+	codeSet := s.RedactedCodeSet
+	s.cacheMu.RUnlock()
+	return codeSet
+}
+
+func (s *OpcodeSet) setRedactedCodeSet(codeSet *OpcodeSet) {
+	s.cacheMu.Lock()
+	s.RedactedCodeSet = codeSet
+	s.cacheMu.Unlock()
+}
+
 type CompiledCode struct {
 	Code    *Opcode
 	Linked  bool // whether recursive code already have linked
@@ -311,11 +325,71 @@ func MapIterNext(it *mapIter)
 //go:noescape
 func MapLen(m unsafe.Pointer) int
 
-func AppendByteSlice(_ *RuntimeContext, b []byte, src []byte) []byte {
-	if src == nil {
+// AppendByteSlice appends src in the format set by the field tag of code, or else by the encode options.
+func AppendByteSlice(ctx *RuntimeContext, code *Opcode, b []byte, src []byte) []byte {
+	return appendByteSlice(ctx, code, b, src, -1)
+}
+
+// AppendByteSliceIndent is like AppendByteSlice, but indents the arrays of the array format from the indent of code.
+func AppendByteSliceIndent(ctx *RuntimeContext, code *Opcode, b []byte, src []byte) []byte {
+	return appendByteSlice(ctx, code, b, src, int(code.Indent))
+}
+
+// ByteSliceFormat returns the format of the []byte value of code, set by its field tag or else by the encode options.
+func ByteSliceFormat(ctx *RuntimeContext, code *Opcode) runtime.BytesFormat {
+	format := code.BytesFormat
+	if format == runtime.BytesFormatDefault && ctx.Option.Flag&BytesFormatOption != 0 {
+		format = ctx.Option.BytesFormat
+	}
+	return format
+}
+
+// appendByteSlice appends src. indent is -1 for compact output.
+// With ColorizeOption, the elements of the array format are colored like integers.
+func appendByteSlice(ctx *RuntimeContext, code *Opcode, b []byte, src []byte, indent int) []byte {
+	if src == nil && code.Flags&NilAsEmptyFlags == 0 && ctx.Option.Flag&NilSliceAsEmptyOption == 0 {
 		return append(b, `null`...)
 	}
-	encodedLen := base64.StdEncoding.EncodedLen(len(src))
+	switch ByteSliceFormat(ctx, code) {
+	case runtime.BytesFormatBase64URL:
+		return appendBase64(b, base64.RawURLEncoding, src)
+	case runtime.BytesFormatBase64Raw:
+		return appendBase64(b, base64.RawStdEncoding, src)
+	case runtime.BytesFormatHex:
+		b = append(b, '"')
+		for _, c := range src {
+			b = append(b, hex[c>>4], hex[c&0xF])
+		}
+		return append(b, '"')
+	case runtime.BytesFormatArray:
+		var elemFormat ColorFormat
+		if ctx.Option.Flag&ColorizeOption != 0 {
+			elemFormat = ctx.Option.ColorScheme.Int
+		}
+		b = append(b, '[')
+		for i, c := range src {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if indent >= 0 {
+				b = append(b, '\n')
+				b = AppendIndent(ctx, b, uint32(indent+1))
+			}
+			b = append(b, elemFormat.Header...)
+			b = strconv.AppendUint(b, uint64(c), 10)
+			b = append(b, elemFormat.Footer...)
+		}
+		if indent >= 0 && len(src) > 0 {
+			b = append(b, '\n')
+			b = AppendIndent(ctx, b, uint32(indent))
+		}
+		return append(b, ']')
+	}
+	return appendBase64(b, base64.StdEncoding, src)
+}
+
+func appendBase64(b []byte, enc *base64.Encoding, src []byte) []byte {
+	encodedLen := enc.EncodedLen(len(src))
 	b = append(b, '"')
 	pos := len(b)
 	remainLen := cap(b[pos:])
@@ -325,7 +399,7 @@ func AppendByteSlice(_ *RuntimeContext, b []byte, src []byte) []byte {
 	} else {
 		buf = make([]byte, encodedLen)
 	}
-	base64.StdEncoding.Encode(buf, src)
+	enc.Encode(buf, src)
 	return append(append(b, buf...), '"')
 }
 
@@ -395,7 +469,16 @@ func AppendNumber(_ *RuntimeContext, b []byte, n json.Number) ([]byte, error) {
 	return b, nil
 }
 
+// appendUnionTag appends the discriminator value of the union member type that the struct of code belongs to.
+func appendUnionTag(ctx *RuntimeContext, code *Opcode, b []byte) []byte {
+	member, _ := runtime.UnionMemberOf(code.Type)
+	return AppendString(ctx, b, member.Name)
+}
+
 func AppendMarshalJSON(ctx *RuntimeContext, code *Opcode, b []byte, v interface{}) ([]byte, error) {
+	if (code.Flags & UnionTagFlags) != 0 {
+		return appendUnionTag(ctx, code, b), nil
+	}
 	rv := reflect.ValueOf(v) // convert by dynamic interface type
 	if (code.Flags & AddrForMarshalerFlags) != 0 {
 		if rv.CanAddr() {
@@ -407,6 +490,9 @@ func AppendMarshalJSON(ctx *RuntimeContext, code *Opcode, b []byte, v interface{
 		}
 	}
 	v = rv.Interface()
+	if bb, ok, err := appendBigNumber(ctx, b, v); ok {
+		return bb, err
+	}
 	var bb []byte
 	if (code.Flags & MarshalerContextFlags) != 0 {
 		marshaler, ok := v.(marshalerContext)
@@ -444,6 +530,9 @@ func AppendMarshalJSON(ctx *RuntimeContext, code *Opcode, b []byte, v interface{
 }
 
 func AppendMarshalJSONIndent(ctx *RuntimeContext, code *Opcode, b []byte, v interface{}) ([]byte, error) {
+	if (code.Flags & UnionTagFlags) != 0 {
+		return appendUnionTag(ctx, code, b), nil
+	}
 	rv := reflect.ValueOf(v) // convert by dynamic interface type
 	if (code.Flags & AddrForMarshalerFlags) != 0 {
 		if rv.CanAddr() {
@@ -455,6 +544,9 @@ func AppendMarshalJSONIndent(ctx *RuntimeContext, code *Opcode, b []byte, v inte
 		}
 	}
 	v = rv.Interface()
+	if bb, ok, err := appendBigNumber(ctx, b, v); ok {
+		return bb, err
+	}
 	var bb []byte
 	if (code.Flags & MarshalerContextFlags) != 0 {
 		marshaler, ok := v.(marshalerContext)
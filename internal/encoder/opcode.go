@@ -23,19 +23,22 @@ const (
 	IsNilableTypeFlags     OpFlags = 1 << 7
 	MarshalerContextFlags  OpFlags = 1 << 8
 	NonEmptyInterfaceFlags OpFlags = 1 << 9
+	UnionTagFlags          OpFlags = 1 << 10
+	NilAsEmptyFlags        OpFlags = 1 << 11
 )
 
 type Opcode struct {
-	Op         OpType  // operation type
-	Idx        uint32  // offset to access ptr
-	Next       *Opcode // next opcode
-	End        *Opcode // array/slice/struct/map end
-	NextField  *Opcode // next struct field
-	Key        string  // struct field key
-	Offset     uint32  // offset size from struct header
-	PtrNum     uint8   // pointer number: e.g. double pointer is 2.
-	NumBitSize uint8
-	Flags      OpFlags
+	Op          OpType              // operation type
+	BytesFormat runtime.BytesFormat // format of []byte values set by the field tag
+	Idx         uint32              // offset to access ptr
+	Next        *Opcode             // next opcode
+	End         *Opcode             // array/slice/struct/map end
+	NextField   *Opcode             // next struct field
+	Key         string              // struct field key
+	Offset      uint32              // offset size from struct header
+	PtrNum      uint8               // pointer number: e.g. double pointer is 2.
+	NumBitSize  uint8
+	Flags       OpFlags
 
 	Type       *runtime.Type // go type
 	Jmp        *CompiledCode // for recursive call
@@ -326,22 +329,23 @@ func copyOpcode(code *Opcode) *Opcode {
 	c := code
 	for {
 		*ptr = Opcode{
-			Op:         c.Op,
-			Key:        c.Key,
-			PtrNum:     c.PtrNum,
-			NumBitSize: c.NumBitSize,
-			Flags:      c.Flags,
-			Idx:        c.Idx,
-			Offset:     c.Offset,
-			Type:       c.Type,
-			FieldQuery: c.FieldQuery,
-			DisplayIdx: c.DisplayIdx,
-			DisplayKey: c.DisplayKey,
-			ElemIdx:    c.ElemIdx,
-			Length:     c.Length,
-			Size:       c.Size,
-			Indent:     c.Indent,
-			Jmp:        c.Jmp,
+			Op:          c.Op,
+			BytesFormat: c.BytesFormat,
+			Key:         c.Key,
+			PtrNum:      c.PtrNum,
+			NumBitSize:  c.NumBitSize,
+			Flags:       c.Flags,
+			Idx:         c.Idx,
+			Offset:      c.Offset,
+			Type:        c.Type,
+			FieldQuery:  c.FieldQuery,
+			DisplayIdx:  c.DisplayIdx,
+			DisplayKey:  c.DisplayKey,
+			ElemIdx:     c.ElemIdx,
+			Length:      c.Length,
+			Size:        c.Size,
+			Indent:      c.Indent,
+			Jmp:         c.Jmp,
 		}
 		if c.End != nil {
 			ptr.End = getCodeAddrByIdx(head, c.End.DisplayIdx)
@@ -585,6 +589,39 @@ func newSliceElemCode(ctx *compileContext, typ *runtime.Type, head *Opcode, size
 	}
 }
 
+func newOrderedMapHeaderCode(ctx *compileContext, typ *runtime.Type, valuesOffset uintptr) *Opcode {
+	idx := opcodeOffset(ctx.ptrIndex)
+	ctx.incPtrIndex()
+	elemIdx := opcodeOffset(ctx.ptrIndex)
+	ctx.incPtrIndex()
+	length := opcodeOffset(ctx.ptrIndex)
+	return &Opcode{
+		Op:         OpOrderedMap,
+		Type:       typ,
+		Idx:        idx,
+		DisplayIdx: ctx.opcodeIndex,
+		ElemIdx:    elemIdx,
+		Length:     length,
+		Offset:     uint32(valuesOffset),
+		Indent:     ctx.indent,
+	}
+}
+
+func newOrderedMapElemCode(ctx *compileContext, typ *runtime.Type, head *Opcode) *Opcode {
+	valueType := typ.Field(1).Type.Elem()
+	return &Opcode{
+		Op:         OpOrderedMapElem,
+		Type:       typ,
+		Idx:        head.Idx,
+		DisplayIdx: ctx.opcodeIndex,
+		ElemIdx:    head.ElemIdx,
+		Length:     head.Length,
+		Offset:     head.Offset,
+		Indent:     ctx.indent,
+		Size:       uint32(valueType.Size()),
+	}
+}
+
 func newArrayHeaderCode(ctx *compileContext, typ *runtime.Type, alen int) *Opcode {
 	idx := opcodeOffset(ctx.ptrIndex)
 	ctx.incPtrIndex()
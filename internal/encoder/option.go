@@ -3,6 +3,8 @@ package encoder
 import (
 	"context"
 	"io"
+
+	"github.com/goccy/go-json/internal/runtime"
 )
 
 type OptionFlag uint16
@@ -17,6 +19,11 @@ const (
 	NormalizeUTF8Option
 	FieldQueryOption
 	CamelCaseOption
+	BytesFormatOption
+	NilSliceAsEmptyOption
+	NilMapAsEmptyOption
+	RedactOption
+	TagKeyOption
 )
 
 type Option struct {
@@ -24,6 +31,9 @@ type Option struct {
 	ColorScheme *ColorScheme
 	Context     context.Context
 	DebugOut    io.Writer
+	BytesFormat runtime.BytesFormat      // format of []byte values without a format tag with BytesFormatOption
+	Redact      func(path string) string // mask of sensitive values with RedactOption
+	TagKey      string                   // key of the struct tags read before json with TagKeyOption
 }
 
 type EncodeFormat struct {
@@ -22,7 +22,7 @@ const (
 	CodeStructEnd   CodeType = 11
 )
 
-var opTypeStrings = [400]string{
+var opTypeStrings = [409]string{
 	"End",
 	"Interface",
 	"Ptr",
@@ -37,6 +37,15 @@ var opTypeStrings = [400]string{
 	"RecursivePtr",
 	"RecursiveEnd",
 	"InterfaceEnd",
+	"Value",
+	"ValuePtr",
+	"Optional",
+	"OptionalPtr",
+	"OrderedMap",
+	"OrderedMapPtr",
+	"OrderedMapElem",
+	"OrderedMapEnd",
+	"Redact",
 	"Int",
 	"Uint",
 	"Float32",
@@ -442,396 +451,405 @@ const (
 	OpRecursivePtr                           OpType = 11
 	OpRecursiveEnd                           OpType = 12
 	OpInterfaceEnd                           OpType = 13
-	OpInt                                    OpType = 14
-	OpUint                                   OpType = 15
-	OpFloat32                                OpType = 16
-	OpFloat64                                OpType = 17
-	OpBool                                   OpType = 18
-	OpString                                 OpType = 19
-	OpBytes                                  OpType = 20
-	OpNumber                                 OpType = 21
-	OpArray                                  OpType = 22
-	OpMap                                    OpType = 23
-	OpSlice                                  OpType = 24
-	OpStruct                                 OpType = 25
-	OpMarshalJSON                            OpType = 26
-	OpMarshalText                            OpType = 27
-	OpIntString                              OpType = 28
-	OpUintString                             OpType = 29
-	OpFloat32String                          OpType = 30
-	OpFloat64String                          OpType = 31
-	OpBoolString                             OpType = 32
-	OpStringString                           OpType = 33
-	OpNumberString                           OpType = 34
-	OpIntPtr                                 OpType = 35
-	OpUintPtr                                OpType = 36
-	OpFloat32Ptr                             OpType = 37
-	OpFloat64Ptr                             OpType = 38
-	OpBoolPtr                                OpType = 39
-	OpStringPtr                              OpType = 40
-	OpBytesPtr                               OpType = 41
-	OpNumberPtr                              OpType = 42
-	OpArrayPtr                               OpType = 43
-	OpMapPtr                                 OpType = 44
-	OpSlicePtr                               OpType = 45
-	OpMarshalJSONPtr                         OpType = 46
-	OpMarshalTextPtr                         OpType = 47
-	OpInterfacePtr                           OpType = 48
-	OpIntPtrString                           OpType = 49
-	OpUintPtrString                          OpType = 50
-	OpFloat32PtrString                       OpType = 51
-	OpFloat64PtrString                       OpType = 52
-	OpBoolPtrString                          OpType = 53
-	OpStringPtrString                        OpType = 54
-	OpNumberPtrString                        OpType = 55
-	OpStructHeadInt                          OpType = 56
-	OpStructHeadOmitEmptyInt                 OpType = 57
-	OpStructPtrHeadInt                       OpType = 58
-	OpStructPtrHeadOmitEmptyInt              OpType = 59
-	OpStructHeadUint                         OpType = 60
-	OpStructHeadOmitEmptyUint                OpType = 61
-	OpStructPtrHeadUint                      OpType = 62
-	OpStructPtrHeadOmitEmptyUint             OpType = 63
-	OpStructHeadFloat32                      OpType = 64
-	OpStructHeadOmitEmptyFloat32             OpType = 65
-	OpStructPtrHeadFloat32                   OpType = 66
-	OpStructPtrHeadOmitEmptyFloat32          OpType = 67
-	OpStructHeadFloat64                      OpType = 68
-	OpStructHeadOmitEmptyFloat64             OpType = 69
-	OpStructPtrHeadFloat64                   OpType = 70
-	OpStructPtrHeadOmitEmptyFloat64          OpType = 71
-	OpStructHeadBool                         OpType = 72
-	OpStructHeadOmitEmptyBool                OpType = 73
-	OpStructPtrHeadBool                      OpType = 74
-	OpStructPtrHeadOmitEmptyBool             OpType = 75
-	OpStructHeadString                       OpType = 76
-	OpStructHeadOmitEmptyString              OpType = 77
-	OpStructPtrHeadString                    OpType = 78
-	OpStructPtrHeadOmitEmptyString           OpType = 79
-	OpStructHeadBytes                        OpType = 80
-	OpStructHeadOmitEmptyBytes               OpType = 81
-	OpStructPtrHeadBytes                     OpType = 82
-	OpStructPtrHeadOmitEmptyBytes            OpType = 83
-	OpStructHeadNumber                       OpType = 84
-	OpStructHeadOmitEmptyNumber              OpType = 85
-	OpStructPtrHeadNumber                    OpType = 86
-	OpStructPtrHeadOmitEmptyNumber           OpType = 87
-	OpStructHeadArray                        OpType = 88
-	OpStructHeadOmitEmptyArray               OpType = 89
-	OpStructPtrHeadArray                     OpType = 90
-	OpStructPtrHeadOmitEmptyArray            OpType = 91
-	OpStructHeadMap                          OpType = 92
-	OpStructHeadOmitEmptyMap                 OpType = 93
-	OpStructPtrHeadMap                       OpType = 94
-	OpStructPtrHeadOmitEmptyMap              OpType = 95
-	OpStructHeadSlice                        OpType = 96
-	OpStructHeadOmitEmptySlice               OpType = 97
-	OpStructPtrHeadSlice                     OpType = 98
-	OpStructPtrHeadOmitEmptySlice            OpType = 99
-	OpStructHeadStruct                       OpType = 100
-	OpStructHeadOmitEmptyStruct              OpType = 101
-	OpStructPtrHeadStruct                    OpType = 102
-	OpStructPtrHeadOmitEmptyStruct           OpType = 103
-	OpStructHeadMarshalJSON                  OpType = 104
-	OpStructHeadOmitEmptyMarshalJSON         OpType = 105
-	OpStructPtrHeadMarshalJSON               OpType = 106
-	OpStructPtrHeadOmitEmptyMarshalJSON      OpType = 107
-	OpStructHeadMarshalText                  OpType = 108
-	OpStructHeadOmitEmptyMarshalText         OpType = 109
-	OpStructPtrHeadMarshalText               OpType = 110
-	OpStructPtrHeadOmitEmptyMarshalText      OpType = 111
-	OpStructHeadIntString                    OpType = 112
-	OpStructHeadOmitEmptyIntString           OpType = 113
-	OpStructPtrHeadIntString                 OpType = 114
-	OpStructPtrHeadOmitEmptyIntString        OpType = 115
-	OpStructHeadUintString                   OpType = 116
-	OpStructHeadOmitEmptyUintString          OpType = 117
-	OpStructPtrHeadUintString                OpType = 118
-	OpStructPtrHeadOmitEmptyUintString       OpType = 119
-	OpStructHeadFloat32String                OpType = 120
-	OpStructHeadOmitEmptyFloat32String       OpType = 121
-	OpStructPtrHeadFloat32String             OpType = 122
-	OpStructPtrHeadOmitEmptyFloat32String    OpType = 123
-	OpStructHeadFloat64String                OpType = 124
-	OpStructHeadOmitEmptyFloat64String       OpType = 125
-	OpStructPtrHeadFloat64String             OpType = 126
-	OpStructPtrHeadOmitEmptyFloat64String    OpType = 127
-	OpStructHeadBoolString                   OpType = 128
-	OpStructHeadOmitEmptyBoolString          OpType = 129
-	OpStructPtrHeadBoolString                OpType = 130
-	OpStructPtrHeadOmitEmptyBoolString       OpType = 131
-	OpStructHeadStringString                 OpType = 132
-	OpStructHeadOmitEmptyStringString        OpType = 133
-	OpStructPtrHeadStringString              OpType = 134
-	OpStructPtrHeadOmitEmptyStringString     OpType = 135
-	OpStructHeadNumberString                 OpType = 136
-	OpStructHeadOmitEmptyNumberString        OpType = 137
-	OpStructPtrHeadNumberString              OpType = 138
-	OpStructPtrHeadOmitEmptyNumberString     OpType = 139
-	OpStructHeadIntPtr                       OpType = 140
-	OpStructHeadOmitEmptyIntPtr              OpType = 141
-	OpStructPtrHeadIntPtr                    OpType = 142
-	OpStructPtrHeadOmitEmptyIntPtr           OpType = 143
-	OpStructHeadUintPtr                      OpType = 144
-	OpStructHeadOmitEmptyUintPtr             OpType = 145
-	OpStructPtrHeadUintPtr                   OpType = 146
-	OpStructPtrHeadOmitEmptyUintPtr          OpType = 147
-	OpStructHeadFloat32Ptr                   OpType = 148
-	OpStructHeadOmitEmptyFloat32Ptr          OpType = 149
-	OpStructPtrHeadFloat32Ptr                OpType = 150
-	OpStructPtrHeadOmitEmptyFloat32Ptr       OpType = 151
-	OpStructHeadFloat64Ptr                   OpType = 152
-	OpStructHeadOmitEmptyFloat64Ptr          OpType = 153
-	OpStructPtrHeadFloat64Ptr                OpType = 154
-	OpStructPtrHeadOmitEmptyFloat64Ptr       OpType = 155
-	OpStructHeadBoolPtr                      OpType = 156
-	OpStructHeadOmitEmptyBoolPtr             OpType = 157
-	OpStructPtrHeadBoolPtr                   OpType = 158
-	OpStructPtrHeadOmitEmptyBoolPtr          OpType = 159
-	OpStructHeadStringPtr                    OpType = 160
-	OpStructHeadOmitEmptyStringPtr           OpType = 161
-	OpStructPtrHeadStringPtr                 OpType = 162
-	OpStructPtrHeadOmitEmptyStringPtr        OpType = 163
-	OpStructHeadBytesPtr                     OpType = 164
-	OpStructHeadOmitEmptyBytesPtr            OpType = 165
-	OpStructPtrHeadBytesPtr                  OpType = 166
-	OpStructPtrHeadOmitEmptyBytesPtr         OpType = 167
-	OpStructHeadNumberPtr                    OpType = 168
-	OpStructHeadOmitEmptyNumberPtr           OpType = 169
-	OpStructPtrHeadNumberPtr                 OpType = 170
-	OpStructPtrHeadOmitEmptyNumberPtr        OpType = 171
-	OpStructHeadArrayPtr                     OpType = 172
-	OpStructHeadOmitEmptyArrayPtr            OpType = 173
-	OpStructPtrHeadArrayPtr                  OpType = 174
-	OpStructPtrHeadOmitEmptyArrayPtr         OpType = 175
-	OpStructHeadMapPtr                       OpType = 176
-	OpStructHeadOmitEmptyMapPtr              OpType = 177
-	OpStructPtrHeadMapPtr                    OpType = 178
-	OpStructPtrHeadOmitEmptyMapPtr           OpType = 179
-	OpStructHeadSlicePtr                     OpType = 180
-	OpStructHeadOmitEmptySlicePtr            OpType = 181
-	OpStructPtrHeadSlicePtr                  OpType = 182
-	OpStructPtrHeadOmitEmptySlicePtr         OpType = 183
-	OpStructHeadMarshalJSONPtr               OpType = 184
-	OpStructHeadOmitEmptyMarshalJSONPtr      OpType = 185
-	OpStructPtrHeadMarshalJSONPtr            OpType = 186
-	OpStructPtrHeadOmitEmptyMarshalJSONPtr   OpType = 187
-	OpStructHeadMarshalTextPtr               OpType = 188
-	OpStructHeadOmitEmptyMarshalTextPtr      OpType = 189
-	OpStructPtrHeadMarshalTextPtr            OpType = 190
-	OpStructPtrHeadOmitEmptyMarshalTextPtr   OpType = 191
-	OpStructHeadInterfacePtr                 OpType = 192
-	OpStructHeadOmitEmptyInterfacePtr        OpType = 193
-	OpStructPtrHeadInterfacePtr              OpType = 194
-	OpStructPtrHeadOmitEmptyInterfacePtr     OpType = 195
-	OpStructHeadIntPtrString                 OpType = 196
-	OpStructHeadOmitEmptyIntPtrString        OpType = 197
-	OpStructPtrHeadIntPtrString              OpType = 198
-	OpStructPtrHeadOmitEmptyIntPtrString     OpType = 199
-	OpStructHeadUintPtrString                OpType = 200
-	OpStructHeadOmitEmptyUintPtrString       OpType = 201
-	OpStructPtrHeadUintPtrString             OpType = 202
-	OpStructPtrHeadOmitEmptyUintPtrString    OpType = 203
-	OpStructHeadFloat32PtrString             OpType = 204
-	OpStructHeadOmitEmptyFloat32PtrString    OpType = 205
-	OpStructPtrHeadFloat32PtrString          OpType = 206
-	OpStructPtrHeadOmitEmptyFloat32PtrString OpType = 207
-	OpStructHeadFloat64PtrString             OpType = 208
-	OpStructHeadOmitEmptyFloat64PtrString    OpType = 209
-	OpStructPtrHeadFloat64PtrString          OpType = 210
-	OpStructPtrHeadOmitEmptyFloat64PtrString OpType = 211
-	OpStructHeadBoolPtrString                OpType = 212
-	OpStructHeadOmitEmptyBoolPtrString       OpType = 213
-	OpStructPtrHeadBoolPtrString             OpType = 214
-	OpStructPtrHeadOmitEmptyBoolPtrString    OpType = 215
-	OpStructHeadStringPtrString              OpType = 216
-	OpStructHeadOmitEmptyStringPtrString     OpType = 217
-	OpStructPtrHeadStringPtrString           OpType = 218
-	OpStructPtrHeadOmitEmptyStringPtrString  OpType = 219
-	OpStructHeadNumberPtrString              OpType = 220
-	OpStructHeadOmitEmptyNumberPtrString     OpType = 221
-	OpStructPtrHeadNumberPtrString           OpType = 222
-	OpStructPtrHeadOmitEmptyNumberPtrString  OpType = 223
-	OpStructHead                             OpType = 224
-	OpStructHeadOmitEmpty                    OpType = 225
-	OpStructPtrHead                          OpType = 226
-	OpStructPtrHeadOmitEmpty                 OpType = 227
-	OpStructFieldInt                         OpType = 228
-	OpStructFieldOmitEmptyInt                OpType = 229
-	OpStructEndInt                           OpType = 230
-	OpStructEndOmitEmptyInt                  OpType = 231
-	OpStructFieldUint                        OpType = 232
-	OpStructFieldOmitEmptyUint               OpType = 233
-	OpStructEndUint                          OpType = 234
-	OpStructEndOmitEmptyUint                 OpType = 235
-	OpStructFieldFloat32                     OpType = 236
-	OpStructFieldOmitEmptyFloat32            OpType = 237
-	OpStructEndFloat32                       OpType = 238
-	OpStructEndOmitEmptyFloat32              OpType = 239
-	OpStructFieldFloat64                     OpType = 240
-	OpStructFieldOmitEmptyFloat64            OpType = 241
-	OpStructEndFloat64                       OpType = 242
-	OpStructEndOmitEmptyFloat64              OpType = 243
-	OpStructFieldBool                        OpType = 244
-	OpStructFieldOmitEmptyBool               OpType = 245
-	OpStructEndBool                          OpType = 246
-	OpStructEndOmitEmptyBool                 OpType = 247
-	OpStructFieldString                      OpType = 248
-	OpStructFieldOmitEmptyString             OpType = 249
-	OpStructEndString                        OpType = 250
-	OpStructEndOmitEmptyString               OpType = 251
-	OpStructFieldBytes                       OpType = 252
-	OpStructFieldOmitEmptyBytes              OpType = 253
-	OpStructEndBytes                         OpType = 254
-	OpStructEndOmitEmptyBytes                OpType = 255
-	OpStructFieldNumber                      OpType = 256
-	OpStructFieldOmitEmptyNumber             OpType = 257
-	OpStructEndNumber                        OpType = 258
-	OpStructEndOmitEmptyNumber               OpType = 259
-	OpStructFieldArray                       OpType = 260
-	OpStructFieldOmitEmptyArray              OpType = 261
-	OpStructEndArray                         OpType = 262
-	OpStructEndOmitEmptyArray                OpType = 263
-	OpStructFieldMap                         OpType = 264
-	OpStructFieldOmitEmptyMap                OpType = 265
-	OpStructEndMap                           OpType = 266
-	OpStructEndOmitEmptyMap                  OpType = 267
-	OpStructFieldSlice                       OpType = 268
-	OpStructFieldOmitEmptySlice              OpType = 269
-	OpStructEndSlice                         OpType = 270
-	OpStructEndOmitEmptySlice                OpType = 271
-	OpStructFieldStruct                      OpType = 272
-	OpStructFieldOmitEmptyStruct             OpType = 273
-	OpStructEndStruct                        OpType = 274
-	OpStructEndOmitEmptyStruct               OpType = 275
-	OpStructFieldMarshalJSON                 OpType = 276
-	OpStructFieldOmitEmptyMarshalJSON        OpType = 277
-	OpStructEndMarshalJSON                   OpType = 278
-	OpStructEndOmitEmptyMarshalJSON          OpType = 279
-	OpStructFieldMarshalText                 OpType = 280
-	OpStructFieldOmitEmptyMarshalText        OpType = 281
-	OpStructEndMarshalText                   OpType = 282
-	OpStructEndOmitEmptyMarshalText          OpType = 283
-	OpStructFieldIntString                   OpType = 284
-	OpStructFieldOmitEmptyIntString          OpType = 285
-	OpStructEndIntString                     OpType = 286
-	OpStructEndOmitEmptyIntString            OpType = 287
-	OpStructFieldUintString                  OpType = 288
-	OpStructFieldOmitEmptyUintString         OpType = 289
-	OpStructEndUintString                    OpType = 290
-	OpStructEndOmitEmptyUintString           OpType = 291
-	OpStructFieldFloat32String               OpType = 292
-	OpStructFieldOmitEmptyFloat32String      OpType = 293
-	OpStructEndFloat32String                 OpType = 294
-	OpStructEndOmitEmptyFloat32String        OpType = 295
-	OpStructFieldFloat64String               OpType = 296
-	OpStructFieldOmitEmptyFloat64String      OpType = 297
-	OpStructEndFloat64String                 OpType = 298
-	OpStructEndOmitEmptyFloat64String        OpType = 299
-	OpStructFieldBoolString                  OpType = 300
-	OpStructFieldOmitEmptyBoolString         OpType = 301
-	OpStructEndBoolString                    OpType = 302
-	OpStructEndOmitEmptyBoolString           OpType = 303
-	OpStructFieldStringString                OpType = 304
-	OpStructFieldOmitEmptyStringString       OpType = 305
-	OpStructEndStringString                  OpType = 306
-	OpStructEndOmitEmptyStringString         OpType = 307
-	OpStructFieldNumberString                OpType = 308
-	OpStructFieldOmitEmptyNumberString       OpType = 309
-	OpStructEndNumberString                  OpType = 310
-	OpStructEndOmitEmptyNumberString         OpType = 311
-	OpStructFieldIntPtr                      OpType = 312
-	OpStructFieldOmitEmptyIntPtr             OpType = 313
-	OpStructEndIntPtr                        OpType = 314
-	OpStructEndOmitEmptyIntPtr               OpType = 315
-	OpStructFieldUintPtr                     OpType = 316
-	OpStructFieldOmitEmptyUintPtr            OpType = 317
-	OpStructEndUintPtr                       OpType = 318
-	OpStructEndOmitEmptyUintPtr              OpType = 319
-	OpStructFieldFloat32Ptr                  OpType = 320
-	OpStructFieldOmitEmptyFloat32Ptr         OpType = 321
-	OpStructEndFloat32Ptr                    OpType = 322
-	OpStructEndOmitEmptyFloat32Ptr           OpType = 323
-	OpStructFieldFloat64Ptr                  OpType = 324
-	OpStructFieldOmitEmptyFloat64Ptr         OpType = 325
-	OpStructEndFloat64Ptr                    OpType = 326
-	OpStructEndOmitEmptyFloat64Ptr           OpType = 327
-	OpStructFieldBoolPtr                     OpType = 328
-	OpStructFieldOmitEmptyBoolPtr            OpType = 329
-	OpStructEndBoolPtr                       OpType = 330
-	OpStructEndOmitEmptyBoolPtr              OpType = 331
-	OpStructFieldStringPtr                   OpType = 332
-	OpStructFieldOmitEmptyStringPtr          OpType = 333
-	OpStructEndStringPtr                     OpType = 334
-	OpStructEndOmitEmptyStringPtr            OpType = 335
-	OpStructFieldBytesPtr                    OpType = 336
-	OpStructFieldOmitEmptyBytesPtr           OpType = 337
-	OpStructEndBytesPtr                      OpType = 338
-	OpStructEndOmitEmptyBytesPtr             OpType = 339
-	OpStructFieldNumberPtr                   OpType = 340
-	OpStructFieldOmitEmptyNumberPtr          OpType = 341
-	OpStructEndNumberPtr                     OpType = 342
-	OpStructEndOmitEmptyNumberPtr            OpType = 343
-	OpStructFieldArrayPtr                    OpType = 344
-	OpStructFieldOmitEmptyArrayPtr           OpType = 345
-	OpStructEndArrayPtr                      OpType = 346
-	OpStructEndOmitEmptyArrayPtr             OpType = 347
-	OpStructFieldMapPtr                      OpType = 348
-	OpStructFieldOmitEmptyMapPtr             OpType = 349
-	OpStructEndMapPtr                        OpType = 350
-	OpStructEndOmitEmptyMapPtr               OpType = 351
-	OpStructFieldSlicePtr                    OpType = 352
-	OpStructFieldOmitEmptySlicePtr           OpType = 353
-	OpStructEndSlicePtr                      OpType = 354
-	OpStructEndOmitEmptySlicePtr             OpType = 355
-	OpStructFieldMarshalJSONPtr              OpType = 356
-	OpStructFieldOmitEmptyMarshalJSONPtr     OpType = 357
-	OpStructEndMarshalJSONPtr                OpType = 358
-	OpStructEndOmitEmptyMarshalJSONPtr       OpType = 359
-	OpStructFieldMarshalTextPtr              OpType = 360
-	OpStructFieldOmitEmptyMarshalTextPtr     OpType = 361
-	OpStructEndMarshalTextPtr                OpType = 362
-	OpStructEndOmitEmptyMarshalTextPtr       OpType = 363
-	OpStructFieldInterfacePtr                OpType = 364
-	OpStructFieldOmitEmptyInterfacePtr       OpType = 365
-	OpStructEndInterfacePtr                  OpType = 366
-	OpStructEndOmitEmptyInterfacePtr         OpType = 367
-	OpStructFieldIntPtrString                OpType = 368
-	OpStructFieldOmitEmptyIntPtrString       OpType = 369
-	OpStructEndIntPtrString                  OpType = 370
-	OpStructEndOmitEmptyIntPtrString         OpType = 371
-	OpStructFieldUintPtrString               OpType = 372
-	OpStructFieldOmitEmptyUintPtrString      OpType = 373
-	OpStructEndUintPtrString                 OpType = 374
-	OpStructEndOmitEmptyUintPtrString        OpType = 375
-	OpStructFieldFloat32PtrString            OpType = 376
-	OpStructFieldOmitEmptyFloat32PtrString   OpType = 377
-	OpStructEndFloat32PtrString              OpType = 378
-	OpStructEndOmitEmptyFloat32PtrString     OpType = 379
-	OpStructFieldFloat64PtrString            OpType = 380
-	OpStructFieldOmitEmptyFloat64PtrString   OpType = 381
-	OpStructEndFloat64PtrString              OpType = 382
-	OpStructEndOmitEmptyFloat64PtrString     OpType = 383
-	OpStructFieldBoolPtrString               OpType = 384
-	OpStructFieldOmitEmptyBoolPtrString      OpType = 385
-	OpStructEndBoolPtrString                 OpType = 386
-	OpStructEndOmitEmptyBoolPtrString        OpType = 387
-	OpStructFieldStringPtrString             OpType = 388
-	OpStructFieldOmitEmptyStringPtrString    OpType = 389
-	OpStructEndStringPtrString               OpType = 390
-	OpStructEndOmitEmptyStringPtrString      OpType = 391
-	OpStructFieldNumberPtrString             OpType = 392
-	OpStructFieldOmitEmptyNumberPtrString    OpType = 393
-	OpStructEndNumberPtrString               OpType = 394
-	OpStructEndOmitEmptyNumberPtrString      OpType = 395
-	OpStructField                            OpType = 396
-	OpStructFieldOmitEmpty                   OpType = 397
-	OpStructEnd                              OpType = 398
-	OpStructEndOmitEmpty                     OpType = 399
+	OpValue                                  OpType = 14
+	OpValuePtr                               OpType = 15
+	OpOptional                               OpType = 16
+	OpOptionalPtr                            OpType = 17
+	OpOrderedMap                             OpType = 18
+	OpOrderedMapPtr                          OpType = 19
+	OpOrderedMapElem                         OpType = 20
+	OpOrderedMapEnd                          OpType = 21
+	OpRedact                                 OpType = 22
+	OpInt                                    OpType = 23
+	OpUint                                   OpType = 24
+	OpFloat32                                OpType = 25
+	OpFloat64                                OpType = 26
+	OpBool                                   OpType = 27
+	OpString                                 OpType = 28
+	OpBytes                                  OpType = 29
+	OpNumber                                 OpType = 30
+	OpArray                                  OpType = 31
+	OpMap                                    OpType = 32
+	OpSlice                                  OpType = 33
+	OpStruct                                 OpType = 34
+	OpMarshalJSON                            OpType = 35
+	OpMarshalText                            OpType = 36
+	OpIntString                              OpType = 37
+	OpUintString                             OpType = 38
+	OpFloat32String                          OpType = 39
+	OpFloat64String                          OpType = 40
+	OpBoolString                             OpType = 41
+	OpStringString                           OpType = 42
+	OpNumberString                           OpType = 43
+	OpIntPtr                                 OpType = 44
+	OpUintPtr                                OpType = 45
+	OpFloat32Ptr                             OpType = 46
+	OpFloat64Ptr                             OpType = 47
+	OpBoolPtr                                OpType = 48
+	OpStringPtr                              OpType = 49
+	OpBytesPtr                               OpType = 50
+	OpNumberPtr                              OpType = 51
+	OpArrayPtr                               OpType = 52
+	OpMapPtr                                 OpType = 53
+	OpSlicePtr                               OpType = 54
+	OpMarshalJSONPtr                         OpType = 55
+	OpMarshalTextPtr                         OpType = 56
+	OpInterfacePtr                           OpType = 57
+	OpIntPtrString                           OpType = 58
+	OpUintPtrString                          OpType = 59
+	OpFloat32PtrString                       OpType = 60
+	OpFloat64PtrString                       OpType = 61
+	OpBoolPtrString                          OpType = 62
+	OpStringPtrString                        OpType = 63
+	OpNumberPtrString                        OpType = 64
+	OpStructHeadInt                          OpType = 65
+	OpStructHeadOmitEmptyInt                 OpType = 66
+	OpStructPtrHeadInt                       OpType = 67
+	OpStructPtrHeadOmitEmptyInt              OpType = 68
+	OpStructHeadUint                         OpType = 69
+	OpStructHeadOmitEmptyUint                OpType = 70
+	OpStructPtrHeadUint                      OpType = 71
+	OpStructPtrHeadOmitEmptyUint             OpType = 72
+	OpStructHeadFloat32                      OpType = 73
+	OpStructHeadOmitEmptyFloat32             OpType = 74
+	OpStructPtrHeadFloat32                   OpType = 75
+	OpStructPtrHeadOmitEmptyFloat32          OpType = 76
+	OpStructHeadFloat64                      OpType = 77
+	OpStructHeadOmitEmptyFloat64             OpType = 78
+	OpStructPtrHeadFloat64                   OpType = 79
+	OpStructPtrHeadOmitEmptyFloat64          OpType = 80
+	OpStructHeadBool                         OpType = 81
+	OpStructHeadOmitEmptyBool                OpType = 82
+	OpStructPtrHeadBool                      OpType = 83
+	OpStructPtrHeadOmitEmptyBool             OpType = 84
+	OpStructHeadString                       OpType = 85
+	OpStructHeadOmitEmptyString              OpType = 86
+	OpStructPtrHeadString                    OpType = 87
+	OpStructPtrHeadOmitEmptyString           OpType = 88
+	OpStructHeadBytes                        OpType = 89
+	OpStructHeadOmitEmptyBytes               OpType = 90
+	OpStructPtrHeadBytes                     OpType = 91
+	OpStructPtrHeadOmitEmptyBytes            OpType = 92
+	OpStructHeadNumber                       OpType = 93
+	OpStructHeadOmitEmptyNumber              OpType = 94
+	OpStructPtrHeadNumber                    OpType = 95
+	OpStructPtrHeadOmitEmptyNumber           OpType = 96
+	OpStructHeadArray                        OpType = 97
+	OpStructHeadOmitEmptyArray               OpType = 98
+	OpStructPtrHeadArray                     OpType = 99
+	OpStructPtrHeadOmitEmptyArray            OpType = 100
+	OpStructHeadMap                          OpType = 101
+	OpStructHeadOmitEmptyMap                 OpType = 102
+	OpStructPtrHeadMap                       OpType = 103
+	OpStructPtrHeadOmitEmptyMap              OpType = 104
+	OpStructHeadSlice                        OpType = 105
+	OpStructHeadOmitEmptySlice               OpType = 106
+	OpStructPtrHeadSlice                     OpType = 107
+	OpStructPtrHeadOmitEmptySlice            OpType = 108
+	OpStructHeadStruct                       OpType = 109
+	OpStructHeadOmitEmptyStruct              OpType = 110
+	OpStructPtrHeadStruct                    OpType = 111
+	OpStructPtrHeadOmitEmptyStruct           OpType = 112
+	OpStructHeadMarshalJSON                  OpType = 113
+	OpStructHeadOmitEmptyMarshalJSON         OpType = 114
+	OpStructPtrHeadMarshalJSON               OpType = 115
+	OpStructPtrHeadOmitEmptyMarshalJSON      OpType = 116
+	OpStructHeadMarshalText                  OpType = 117
+	OpStructHeadOmitEmptyMarshalText         OpType = 118
+	OpStructPtrHeadMarshalText               OpType = 119
+	OpStructPtrHeadOmitEmptyMarshalText      OpType = 120
+	OpStructHeadIntString                    OpType = 121
+	OpStructHeadOmitEmptyIntString           OpType = 122
+	OpStructPtrHeadIntString                 OpType = 123
+	OpStructPtrHeadOmitEmptyIntString        OpType = 124
+	OpStructHeadUintString                   OpType = 125
+	OpStructHeadOmitEmptyUintString          OpType = 126
+	OpStructPtrHeadUintString                OpType = 127
+	OpStructPtrHeadOmitEmptyUintString       OpType = 128
+	OpStructHeadFloat32String                OpType = 129
+	OpStructHeadOmitEmptyFloat32String       OpType = 130
+	OpStructPtrHeadFloat32String             OpType = 131
+	OpStructPtrHeadOmitEmptyFloat32String    OpType = 132
+	OpStructHeadFloat64String                OpType = 133
+	OpStructHeadOmitEmptyFloat64String       OpType = 134
+	OpStructPtrHeadFloat64String             OpType = 135
+	OpStructPtrHeadOmitEmptyFloat64String    OpType = 136
+	OpStructHeadBoolString                   OpType = 137
+	OpStructHeadOmitEmptyBoolString          OpType = 138
+	OpStructPtrHeadBoolString                OpType = 139
+	OpStructPtrHeadOmitEmptyBoolString       OpType = 140
+	OpStructHeadStringString                 OpType = 141
+	OpStructHeadOmitEmptyStringString        OpType = 142
+	OpStructPtrHeadStringString              OpType = 143
+	OpStructPtrHeadOmitEmptyStringString     OpType = 144
+	OpStructHeadNumberString                 OpType = 145
+	OpStructHeadOmitEmptyNumberString        OpType = 146
+	OpStructPtrHeadNumberString              OpType = 147
+	OpStructPtrHeadOmitEmptyNumberString     OpType = 148
+	OpStructHeadIntPtr                       OpType = 149
+	OpStructHeadOmitEmptyIntPtr              OpType = 150
+	OpStructPtrHeadIntPtr                    OpType = 151
+	OpStructPtrHeadOmitEmptyIntPtr           OpType = 152
+	OpStructHeadUintPtr                      OpType = 153
+	OpStructHeadOmitEmptyUintPtr             OpType = 154
+	OpStructPtrHeadUintPtr                   OpType = 155
+	OpStructPtrHeadOmitEmptyUintPtr          OpType = 156
+	OpStructHeadFloat32Ptr                   OpType = 157
+	OpStructHeadOmitEmptyFloat32Ptr          OpType = 158
+	OpStructPtrHeadFloat32Ptr                OpType = 159
+	OpStructPtrHeadOmitEmptyFloat32Ptr       OpType = 160
+	OpStructHeadFloat64Ptr                   OpType = 161
+	OpStructHeadOmitEmptyFloat64Ptr          OpType = 162
+	OpStructPtrHeadFloat64Ptr                OpType = 163
+	OpStructPtrHeadOmitEmptyFloat64Ptr       OpType = 164
+	OpStructHeadBoolPtr                      OpType = 165
+	OpStructHeadOmitEmptyBoolPtr             OpType = 166
+	OpStructPtrHeadBoolPtr                   OpType = 167
+	OpStructPtrHeadOmitEmptyBoolPtr          OpType = 168
+	OpStructHeadStringPtr                    OpType = 169
+	OpStructHeadOmitEmptyStringPtr           OpType = 170
+	OpStructPtrHeadStringPtr                 OpType = 171
+	OpStructPtrHeadOmitEmptyStringPtr        OpType = 172
+	OpStructHeadBytesPtr                     OpType = 173
+	OpStructHeadOmitEmptyBytesPtr            OpType = 174
+	OpStructPtrHeadBytesPtr                  OpType = 175
+	OpStructPtrHeadOmitEmptyBytesPtr         OpType = 176
+	OpStructHeadNumberPtr                    OpType = 177
+	OpStructHeadOmitEmptyNumberPtr           OpType = 178
+	OpStructPtrHeadNumberPtr                 OpType = 179
+	OpStructPtrHeadOmitEmptyNumberPtr        OpType = 180
+	OpStructHeadArrayPtr                     OpType = 181
+	OpStructHeadOmitEmptyArrayPtr            OpType = 182
+	OpStructPtrHeadArrayPtr                  OpType = 183
+	OpStructPtrHeadOmitEmptyArrayPtr         OpType = 184
+	OpStructHeadMapPtr                       OpType = 185
+	OpStructHeadOmitEmptyMapPtr              OpType = 186
+	OpStructPtrHeadMapPtr                    OpType = 187
+	OpStructPtrHeadOmitEmptyMapPtr           OpType = 188
+	OpStructHeadSlicePtr                     OpType = 189
+	OpStructHeadOmitEmptySlicePtr            OpType = 190
+	OpStructPtrHeadSlicePtr                  OpType = 191
+	OpStructPtrHeadOmitEmptySlicePtr         OpType = 192
+	OpStructHeadMarshalJSONPtr               OpType = 193
+	OpStructHeadOmitEmptyMarshalJSONPtr      OpType = 194
+	OpStructPtrHeadMarshalJSONPtr            OpType = 195
+	OpStructPtrHeadOmitEmptyMarshalJSONPtr   OpType = 196
+	OpStructHeadMarshalTextPtr               OpType = 197
+	OpStructHeadOmitEmptyMarshalTextPtr      OpType = 198
+	OpStructPtrHeadMarshalTextPtr            OpType = 199
+	OpStructPtrHeadOmitEmptyMarshalTextPtr   OpType = 200
+	OpStructHeadInterfacePtr                 OpType = 201
+	OpStructHeadOmitEmptyInterfacePtr        OpType = 202
+	OpStructPtrHeadInterfacePtr              OpType = 203
+	OpStructPtrHeadOmitEmptyInterfacePtr     OpType = 204
+	OpStructHeadIntPtrString                 OpType = 205
+	OpStructHeadOmitEmptyIntPtrString        OpType = 206
+	OpStructPtrHeadIntPtrString              OpType = 207
+	OpStructPtrHeadOmitEmptyIntPtrString     OpType = 208
+	OpStructHeadUintPtrString                OpType = 209
+	OpStructHeadOmitEmptyUintPtrString       OpType = 210
+	OpStructPtrHeadUintPtrString             OpType = 211
+	OpStructPtrHeadOmitEmptyUintPtrString    OpType = 212
+	OpStructHeadFloat32PtrString             OpType = 213
+	OpStructHeadOmitEmptyFloat32PtrString    OpType = 214
+	OpStructPtrHeadFloat32PtrString          OpType = 215
+	OpStructPtrHeadOmitEmptyFloat32PtrString OpType = 216
+	OpStructHeadFloat64PtrString             OpType = 217
+	OpStructHeadOmitEmptyFloat64PtrString    OpType = 218
+	OpStructPtrHeadFloat64PtrString          OpType = 219
+	OpStructPtrHeadOmitEmptyFloat64PtrString OpType = 220
+	OpStructHeadBoolPtrString                OpType = 221
+	OpStructHeadOmitEmptyBoolPtrString       OpType = 222
+	OpStructPtrHeadBoolPtrString             OpType = 223
+	OpStructPtrHeadOmitEmptyBoolPtrString    OpType = 224
+	OpStructHeadStringPtrString              OpType = 225
+	OpStructHeadOmitEmptyStringPtrString     OpType = 226
+	OpStructPtrHeadStringPtrString           OpType = 227
+	OpStructPtrHeadOmitEmptyStringPtrString  OpType = 228
+	OpStructHeadNumberPtrString              OpType = 229
+	OpStructHeadOmitEmptyNumberPtrString     OpType = 230
+	OpStructPtrHeadNumberPtrString           OpType = 231
+	OpStructPtrHeadOmitEmptyNumberPtrString  OpType = 232
+	OpStructHead                             OpType = 233
+	OpStructHeadOmitEmpty                    OpType = 234
+	OpStructPtrHead                          OpType = 235
+	OpStructPtrHeadOmitEmpty                 OpType = 236
+	OpStructFieldInt                         OpType = 237
+	OpStructFieldOmitEmptyInt                OpType = 238
+	OpStructEndInt                           OpType = 239
+	OpStructEndOmitEmptyInt                  OpType = 240
+	OpStructFieldUint                        OpType = 241
+	OpStructFieldOmitEmptyUint               OpType = 242
+	OpStructEndUint                          OpType = 243
+	OpStructEndOmitEmptyUint                 OpType = 244
+	OpStructFieldFloat32                     OpType = 245
+	OpStructFieldOmitEmptyFloat32            OpType = 246
+	OpStructEndFloat32                       OpType = 247
+	OpStructEndOmitEmptyFloat32              OpType = 248
+	OpStructFieldFloat64                     OpType = 249
+	OpStructFieldOmitEmptyFloat64            OpType = 250
+	OpStructEndFloat64                       OpType = 251
+	OpStructEndOmitEmptyFloat64              OpType = 252
+	OpStructFieldBool                        OpType = 253
+	OpStructFieldOmitEmptyBool               OpType = 254
+	OpStructEndBool                          OpType = 255
+	OpStructEndOmitEmptyBool                 OpType = 256
+	OpStructFieldString                      OpType = 257
+	OpStructFieldOmitEmptyString             OpType = 258
+	OpStructEndString                        OpType = 259
+	OpStructEndOmitEmptyString               OpType = 260
+	OpStructFieldBytes                       OpType = 261
+	OpStructFieldOmitEmptyBytes              OpType = 262
+	OpStructEndBytes                         OpType = 263
+	OpStructEndOmitEmptyBytes                OpType = 264
+	OpStructFieldNumber                      OpType = 265
+	OpStructFieldOmitEmptyNumber             OpType = 266
+	OpStructEndNumber                        OpType = 267
+	OpStructEndOmitEmptyNumber               OpType = 268
+	OpStructFieldArray                       OpType = 269
+	OpStructFieldOmitEmptyArray              OpType = 270
+	OpStructEndArray                         OpType = 271
+	OpStructEndOmitEmptyArray                OpType = 272
+	OpStructFieldMap                         OpType = 273
+	OpStructFieldOmitEmptyMap                OpType = 274
+	OpStructEndMap                           OpType = 275
+	OpStructEndOmitEmptyMap                  OpType = 276
+	OpStructFieldSlice                       OpType = 277
+	OpStructFieldOmitEmptySlice              OpType = 278
+	OpStructEndSlice                         OpType = 279
+	OpStructEndOmitEmptySlice                OpType = 280
+	OpStructFieldStruct                      OpType = 281
+	OpStructFieldOmitEmptyStruct             OpType = 282
+	OpStructEndStruct                        OpType = 283
+	OpStructEndOmitEmptyStruct               OpType = 284
+	OpStructFieldMarshalJSON                 OpType = 285
+	OpStructFieldOmitEmptyMarshalJSON        OpType = 286
+	OpStructEndMarshalJSON                   OpType = 287
+	OpStructEndOmitEmptyMarshalJSON          OpType = 288
+	OpStructFieldMarshalText                 OpType = 289
+	OpStructFieldOmitEmptyMarshalText        OpType = 290
+	OpStructEndMarshalText                   OpType = 291
+	OpStructEndOmitEmptyMarshalText          OpType = 292
+	OpStructFieldIntString                   OpType = 293
+	OpStructFieldOmitEmptyIntString          OpType = 294
+	OpStructEndIntString                     OpType = 295
+	OpStructEndOmitEmptyIntString            OpType = 296
+	OpStructFieldUintString                  OpType = 297
+	OpStructFieldOmitEmptyUintString         OpType = 298
+	OpStructEndUintString                    OpType = 299
+	OpStructEndOmitEmptyUintString           OpType = 300
+	OpStructFieldFloat32String               OpType = 301
+	OpStructFieldOmitEmptyFloat32String      OpType = 302
+	OpStructEndFloat32String                 OpType = 303
+	OpStructEndOmitEmptyFloat32String        OpType = 304
+	OpStructFieldFloat64String               OpType = 305
+	OpStructFieldOmitEmptyFloat64String      OpType = 306
+	OpStructEndFloat64String                 OpType = 307
+	OpStructEndOmitEmptyFloat64String        OpType = 308
+	OpStructFieldBoolString                  OpType = 309
+	OpStructFieldOmitEmptyBoolString         OpType = 310
+	OpStructEndBoolString                    OpType = 311
+	OpStructEndOmitEmptyBoolString           OpType = 312
+	OpStructFieldStringString                OpType = 313
+	OpStructFieldOmitEmptyStringString       OpType = 314
+	OpStructEndStringString                  OpType = 315
+	OpStructEndOmitEmptyStringString         OpType = 316
+	OpStructFieldNumberString                OpType = 317
+	OpStructFieldOmitEmptyNumberString       OpType = 318
+	OpStructEndNumberString                  OpType = 319
+	OpStructEndOmitEmptyNumberString         OpType = 320
+	OpStructFieldIntPtr                      OpType = 321
+	OpStructFieldOmitEmptyIntPtr             OpType = 322
+	OpStructEndIntPtr                        OpType = 323
+	OpStructEndOmitEmptyIntPtr               OpType = 324
+	OpStructFieldUintPtr                     OpType = 325
+	OpStructFieldOmitEmptyUintPtr            OpType = 326
+	OpStructEndUintPtr                       OpType = 327
+	OpStructEndOmitEmptyUintPtr              OpType = 328
+	OpStructFieldFloat32Ptr                  OpType = 329
+	OpStructFieldOmitEmptyFloat32Ptr         OpType = 330
+	OpStructEndFloat32Ptr                    OpType = 331
+	OpStructEndOmitEmptyFloat32Ptr           OpType = 332
+	OpStructFieldFloat64Ptr                  OpType = 333
+	OpStructFieldOmitEmptyFloat64Ptr         OpType = 334
+	OpStructEndFloat64Ptr                    OpType = 335
+	OpStructEndOmitEmptyFloat64Ptr           OpType = 336
+	OpStructFieldBoolPtr                     OpType = 337
+	OpStructFieldOmitEmptyBoolPtr            OpType = 338
+	OpStructEndBoolPtr                       OpType = 339
+	OpStructEndOmitEmptyBoolPtr              OpType = 340
+	OpStructFieldStringPtr                   OpType = 341
+	OpStructFieldOmitEmptyStringPtr          OpType = 342
+	OpStructEndStringPtr                     OpType = 343
+	OpStructEndOmitEmptyStringPtr            OpType = 344
+	OpStructFieldBytesPtr                    OpType = 345
+	OpStructFieldOmitEmptyBytesPtr           OpType = 346
+	OpStructEndBytesPtr                      OpType = 347
+	OpStructEndOmitEmptyBytesPtr             OpType = 348
+	OpStructFieldNumberPtr                   OpType = 349
+	OpStructFieldOmitEmptyNumberPtr          OpType = 350
+	OpStructEndNumberPtr                     OpType = 351
+	OpStructEndOmitEmptyNumberPtr            OpType = 352
+	OpStructFieldArrayPtr                    OpType = 353
+	OpStructFieldOmitEmptyArrayPtr           OpType = 354
+	OpStructEndArrayPtr                      OpType = 355
+	OpStructEndOmitEmptyArrayPtr             OpType = 356
+	OpStructFieldMapPtr                      OpType = 357
+	OpStructFieldOmitEmptyMapPtr             OpType = 358
+	OpStructEndMapPtr                        OpType = 359
+	OpStructEndOmitEmptyMapPtr               OpType = 360
+	OpStructFieldSlicePtr                    OpType = 361
+	OpStructFieldOmitEmptySlicePtr           OpType = 362
+	OpStructEndSlicePtr                      OpType = 363
+	OpStructEndOmitEmptySlicePtr             OpType = 364
+	OpStructFieldMarshalJSONPtr              OpType = 365
+	OpStructFieldOmitEmptyMarshalJSONPtr     OpType = 366
+	OpStructEndMarshalJSONPtr                OpType = 367
+	OpStructEndOmitEmptyMarshalJSONPtr       OpType = 368
+	OpStructFieldMarshalTextPtr              OpType = 369
+	OpStructFieldOmitEmptyMarshalTextPtr     OpType = 370
+	OpStructEndMarshalTextPtr                OpType = 371
+	OpStructEndOmitEmptyMarshalTextPtr       OpType = 372
+	OpStructFieldInterfacePtr                OpType = 373
+	OpStructFieldOmitEmptyInterfacePtr       OpType = 374
+	OpStructEndInterfacePtr                  OpType = 375
+	OpStructEndOmitEmptyInterfacePtr         OpType = 376
+	OpStructFieldIntPtrString                OpType = 377
+	OpStructFieldOmitEmptyIntPtrString       OpType = 378
+	OpStructEndIntPtrString                  OpType = 379
+	OpStructEndOmitEmptyIntPtrString         OpType = 380
+	OpStructFieldUintPtrString               OpType = 381
+	OpStructFieldOmitEmptyUintPtrString      OpType = 382
+	OpStructEndUintPtrString                 OpType = 383
+	OpStructEndOmitEmptyUintPtrString        OpType = 384
+	OpStructFieldFloat32PtrString            OpType = 385
+	OpStructFieldOmitEmptyFloat32PtrString   OpType = 386
+	OpStructEndFloat32PtrString              OpType = 387
+	OpStructEndOmitEmptyFloat32PtrString     OpType = 388
+	OpStructFieldFloat64PtrString            OpType = 389
+	OpStructFieldOmitEmptyFloat64PtrString   OpType = 390
+	OpStructEndFloat64PtrString              OpType = 391
+	OpStructEndOmitEmptyFloat64PtrString     OpType = 392
+	OpStructFieldBoolPtrString               OpType = 393
+	OpStructFieldOmitEmptyBoolPtrString      OpType = 394
+	OpStructEndBoolPtrString                 OpType = 395
+	OpStructEndOmitEmptyBoolPtrString        OpType = 396
+	OpStructFieldStringPtrString             OpType = 397
+	OpStructFieldOmitEmptyStringPtrString    OpType = 398
+	OpStructEndStringPtrString               OpType = 399
+	OpStructEndOmitEmptyStringPtrString      OpType = 400
+	OpStructFieldNumberPtrString             OpType = 401
+	OpStructFieldOmitEmptyNumberPtrString    OpType = 402
+	OpStructEndNumberPtrString               OpType = 403
+	OpStructEndOmitEmptyNumberPtrString      OpType = 404
+	OpStructField                            OpType = 405
+	OpStructFieldOmitEmpty                   OpType = 406
+	OpStructEnd                              OpType = 407
+	OpStructEndOmitEmpty                     OpType = 408
 )
 
 func (t OpType) String() string {
-	if int(t) >= 400 {
+	if int(t) >= 409 {
 		return ""
 	}
 	return opTypeStrings[int(t)]
@@ -849,9 +867,9 @@ func (t OpType) CodeType() CodeType {
 		return CodeArrayHead
 	case OpArrayElem:
 		return CodeArrayElem
-	case OpSlice, OpSlicePtr:
+	case OpSlice, OpSlicePtr, OpOrderedMap, OpOrderedMapPtr:
 		return CodeSliceHead
-	case OpSliceElem:
+	case OpSliceElem, OpOrderedMapElem:
 		return CodeSliceElem
 	case OpMap, OpMapPtr:
 		return CodeMapHead
@@ -0,0 +1,422 @@
+package encoder
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json/internal/runtime"
+)
+
+// jsonSchemaer is implemented by types that describe their JSON encoding with a JSON Schema.
+type jsonSchemaer interface {
+	JSONSchema() []byte
+}
+
+var (
+	jsonSchemaerType = reflect.TypeOf((*jsonSchemaer)(nil)).Elem()
+	timeType         = runtime.Type2RType(reflect.TypeOf(time.Time{}))
+)
+
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema returns a JSON Schema (draft 2020-12) describing the encoding of the values of typ.
+// It is generated from the same codes as the encoder, so field names, omitempty, the string option,
+// embedded fields and duplicated fields are resolved exactly like when encoding.
+// Named struct types are described in $defs.
+func Schema(typ *runtime.Type) ([]byte, error) {
+	g := &schemaGenerator{
+		names: map[*runtime.Type]string{},
+		types: map[string]*runtime.Type{},
+		defs:  map[string][]byte{},
+	}
+	code, err := newCompiler().typeToCode(typ)
+	if err != nil {
+		return nil, err
+	}
+	root, err := g.schema(code, typ.Kind() == reflect.Ptr)
+	if err != nil {
+		return nil, err
+	}
+	b := append([]byte(`{"$schema":`), strconv.Quote(schemaDialect)...)
+	if len(root) > 2 {
+		b = append(b, ',')
+		b = append(b, root[1:len(root)-1]...)
+	}
+	if len(g.defs) > 0 {
+		names := make([]string, 0, len(g.defs))
+		for name := range g.defs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		b = append(b, `,"$defs":{`...)
+		for i, name := range names {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendString(b, name)
+			b = append(b, ':')
+			b = append(b, g.defs[name]...)
+		}
+		b = append(b, '}')
+	}
+	return append(b, '}'), nil
+}
+
+type schemaGenerator struct {
+	names map[*runtime.Type]string // $defs names of named struct types
+	types map[string]*runtime.Type // struct types by $defs name
+	defs  map[string][]byte
+}
+
+// typeSchema returns the schema for values of the JSON type name, or null if nullable is true.
+func typeSchema(name string, nullable bool) []byte {
+	if nullable {
+		return []byte(`{"type":["` + name + `","null"]}`)
+	}
+	return []byte(`{"type":"` + name + `"}`)
+}
+
+// nullable returns a schema that accepts null or the values of schema.
+func nullable(schema []byte) []byte {
+	if string(schema) == "{}" {
+		return schema
+	}
+	b := append([]byte(`{"anyOf":[`), schema...)
+	return append(b, `,{"type":"null"}]}`...)
+}
+
+// withType returns schema with the type keyword added as the first keyword.
+func withType(name string, isNullable bool, schema string) []byte {
+	b := typeSchema(name, isNullable)
+	if schema == "" {
+		return b
+	}
+	b = append(b[:len(b)-1], ',')
+	return append(append(b, schema...), '}')
+}
+
+func (g *schemaGenerator) schema(code Code, isNullable bool) ([]byte, error) {
+	switch c := code.(type) {
+	case *IntCode:
+		if c.isString {
+			return withType("string", isNullable, `"pattern":"^-?[0-9]+$"`), nil
+		}
+		return typeSchema("integer", isNullable), nil
+	case *UintCode:
+		if c.isString {
+			return withType("string", isNullable, `"pattern":"^[0-9]+$"`), nil
+		}
+		return typeSchema("integer", isNullable), nil
+	case *FloatCode:
+		return typeSchema("number", isNullable), nil
+	case *StringCode:
+		if c.typ == runtime.Type2RType(jsonNumberType) {
+			return typeSchema("number", isNullable), nil
+		}
+		return typeSchema("string", isNullable), nil
+	case *BoolCode:
+		return typeSchema("boolean", isNullable), nil
+	case *BytesCode:
+		isNullable = isNullable || !c.nilAsEmpty
+		switch c.format {
+		case runtime.BytesFormatBase64URL:
+			return withType("string", isNullable, `"contentEncoding":"base64url"`), nil
+		case runtime.BytesFormatHex:
+			return withType("string", isNullable, `"pattern":"^([0-9a-fA-F]{2})*$"`), nil
+		case runtime.BytesFormatArray:
+			return withType("array", isNullable, `"items":{"type":"integer","minimum":0,"maximum":255}`), nil
+		}
+		return withType("string", isNullable, `"contentEncoding":"base64"`), nil
+	case *SliceCode:
+		items, err := g.schema(c.value, false)
+		if err != nil {
+			return nil, err
+		}
+		return withType("array", isNullable || !c.nilAsEmpty, `"items":`+string(items)), nil
+	case *ArrayCode:
+		items, err := g.schema(c.value, false)
+		if err != nil {
+			return nil, err
+		}
+		n := strconv.Itoa(c.typ.Len())
+		return withType("array", isNullable, `"items":`+string(items)+`,"minItems":`+n+`,"maxItems":`+n), nil
+	case *MapCode:
+		return g.mapSchema(c, isNullable)
+	case *PtrCode:
+		return g.schema(c.value, true)
+	case *OptionalCode:
+		return g.schema(c.value, true)
+	case *OrderedMapCode:
+		value, err := g.schema(c.value, false)
+		if err != nil {
+			return nil, err
+		}
+		return withType("object", isNullable || c.isPtr, `"additionalProperties":`+string(value)), nil
+	case *StructCode:
+		return g.structSchema(c, isNullable)
+	case *InterfaceCode:
+		return g.interfaceSchema(c.typ)
+	case *MarshalJSONCode:
+		return g.marshalerSchema(c.typ, false, isNullable || c.isNilableType)
+	case *MarshalTextCode:
+		return g.marshalerSchema(c.typ, true, isNullable || c.isNilableType)
+	case *SensitiveCode:
+		return g.schema(c.value, isNullable)
+	}
+	return nil, fmt.Errorf("json: cannot generate schema for %T", code)
+}
+
+func (g *schemaGenerator) mapSchema(c *MapCode, isNullable bool) ([]byte, error) {
+	value, err := g.schema(c.value, false)
+	if err != nil {
+		return nil, err
+	}
+	schema := `"additionalProperties":` + string(value)
+	key := c.key
+	if ptr, ok := key.(*PtrCode); ok {
+		key = ptr.value
+	}
+	switch key := key.(type) {
+	case *IntCode:
+		schema += `,"propertyNames":{"pattern":"^-?[0-9]+$"}`
+	case *UintCode:
+		schema += `,"propertyNames":{"pattern":"^[0-9]+$"}`
+	case *MarshalTextCode:
+		if keySchema, ok := describedSchema(key.typ); ok {
+			schema += `,"propertyNames":` + string(keySchema)
+		}
+	}
+	return withType("object", isNullable || !c.nilAsEmpty, schema), nil
+}
+
+// defName returns the $defs name of the named struct type typ.
+func (g *schemaGenerator) defName(typ *runtime.Type) string {
+	if name, exists := g.names[typ]; exists {
+		return name
+	}
+	name := typ.Name()
+	if _, exists := g.types[name]; exists {
+		name = strings.ReplaceAll(typ.PkgPath(), "/", ".") + "." + typ.Name()
+	}
+	for i := 2; ; i++ {
+		if _, exists := g.types[name]; !exists {
+			break
+		}
+		name = typ.Name() + strconv.Itoa(i)
+	}
+	g.names[typ] = name
+	g.types[name] = typ
+	return name
+}
+
+func (g *schemaGenerator) structSchema(c *StructCode, isNullable bool) ([]byte, error) {
+	if c.typ.Name() == "" {
+		schema, err := g.structBody(c)
+		if err != nil {
+			return nil, err
+		}
+		if isNullable {
+			return nullable(schema), nil
+		}
+		return schema, nil
+	}
+	_, exists := g.names[c.typ]
+	name := g.defName(c.typ)
+	if !exists {
+		code := c
+		if c.isRecursive {
+			// recursive codes do not hold the fields of the struct.
+			compiled, err := newCompiler().structCode(c.typ, false)
+			if err != nil {
+				return nil, err
+			}
+			code = compiled
+		}
+		g.defs[name] = []byte("{}")
+		body, err := g.structBody(code)
+		if err != nil {
+			return nil, err
+		}
+		g.defs[name] = body
+	}
+	ref := []byte(`{"$ref":` + strconv.Quote("#/$defs/"+escapeJSONPointer(name)) + `}`)
+	if isNullable {
+		return nullable(ref), nil
+	}
+	return ref, nil
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+type schemaProperty struct {
+	key      string
+	schema   []byte
+	required bool
+}
+
+func (g *schemaGenerator) structBody(c *StructCode) ([]byte, error) {
+	props, err := g.structProperties(c, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	b := []byte(`{"type":"object","properties":{`)
+	var required []string
+	for i, prop := range props {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = appendString(b, prop.key)
+		b = append(b, ':')
+		b = append(b, prop.schema...)
+		if prop.required {
+			required = append(required, prop.key)
+		}
+	}
+	b = append(b, '}')
+	if len(required) > 0 {
+		b = append(b, `,"required":[`...)
+		for i, key := range required {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendString(b, key)
+		}
+		b = append(b, ']')
+	}
+	return append(b, `,"additionalProperties":false}`...), nil
+}
+
+// structProperties appends the properties encoded for the fields of c to props.
+// The fields of embedded structs are promoted; they are only required if the embedded struct is not a pointer.
+func (g *schemaGenerator) structProperties(c *StructCode, props []*schemaProperty, required bool) ([]*schemaProperty, error) {
+	for _, field := range c.fields {
+		if isEmbeddedStruct(field) {
+			embedded := field.getAnonymousStruct()
+			if embedded == nil {
+				continue
+			}
+			var err error
+			props, err = g.structProperties(embedded, props, required && field.typ.Kind() != reflect.Ptr)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		schema, err := g.fieldSchema(field)
+		if err != nil {
+			return nil, err
+		}
+		_, isOptional := field.value.(*OptionalCode)
+		props = append(props, &schemaProperty{
+			key:      field.key,
+			schema:   schema,
+			required: required && !field.tag.IsOmitEmpty && !isOptional,
+		})
+	}
+	return props, nil
+}
+
+func (g *schemaGenerator) fieldSchema(field *StructFieldCode) ([]byte, error) {
+	if field.isUnionTag {
+		member, _ := runtime.UnionMemberOf(field.typ)
+		return append(appendString([]byte(`{"const":`), member.Name), '}'), nil
+	}
+	if field.tag.IsString {
+		value := field.value
+		isNullable := false
+		if ptr, ok := value.(*PtrCode); ok {
+			value, isNullable = ptr.value, true
+		} else if optional, ok := value.(*OptionalCode); ok && optional.isString {
+			value, isNullable = optional.value, true
+		}
+		switch value.(type) {
+		case *IntCode, *UintCode, *FloatCode, *BoolCode:
+			return typeSchema("string", isNullable), nil
+		case *StringCode:
+			if !isNullable {
+				return withType("string", false, `"contentMediaType":"application/json"`), nil
+			}
+			return typeSchema("string", isNullable), nil
+		}
+	}
+	return g.schema(field.value, false)
+}
+
+func (g *schemaGenerator) interfaceSchema(typ *runtime.Type) ([]byte, error) {
+	union := runtime.UnionOf(typ)
+	if union == nil {
+		return []byte("{}"), nil
+	}
+	names := make([]string, 0, len(union.Types))
+	for name := range union.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	b := []byte(`{"oneOf":[`)
+	for _, name := range names {
+		code, err := newCompiler().typeToCode(union.Types[name])
+		if err != nil {
+			return nil, err
+		}
+		schema, err := g.schema(code, false)
+		if err != nil {
+			return nil, err
+		}
+		b = append(append(b, schema...), ',')
+	}
+	return append(b, `{"type":"null"}]}`...), nil
+}
+
+// describedSchema returns the schema returned by the JSONSchema method of typ or *typ.
+func describedSchema(typ *runtime.Type) ([]byte, bool) {
+	rtype := runtime.RType2Type(typ)
+	var v reflect.Value
+	switch {
+	case rtype.Kind() == reflect.Ptr && rtype.Implements(jsonSchemaerType):
+		v = reflect.New(rtype.Elem())
+	case reflect.PtrTo(rtype).Implements(jsonSchemaerType):
+		v = reflect.New(rtype)
+	default:
+		return nil, false
+	}
+	src := v.Interface().(jsonSchemaer).JSONSchema()
+	schema, err := compact(nil, append(src[:len(src):len(src)], nul), false)
+	if err != nil {
+		return nil, false
+	}
+	return schema, true
+}
+
+func (g *schemaGenerator) marshalerSchema(typ *runtime.Type, isText, isNullable bool) ([]byte, error) {
+	schema, ok := describedSchema(typ)
+	if !ok {
+		ptr := typ
+		if typ.Kind() != reflect.Ptr {
+			ptr = runtime.PtrTo(typ)
+		}
+		switch {
+		case ptr.Elem() == timeType:
+			schema = []byte(`{"type":"string","format":"date-time"}`)
+		case ptr == bigIntPtrType:
+			schema = []byte(`{"type":"integer"}`)
+		case ptr == bigFloatPtrType:
+			schema = []byte(`{"type":"number"}`)
+		case ptr == bigRatPtrType:
+			schema = []byte(`{"type":["number","string"]}`)
+		case isText:
+			schema = []byte(`{"type":"string"}`)
+		default:
+			return []byte("{}"), nil
+		}
+	}
+	if isNullable {
+		return nullable(schema), nil
+	}
+	return schema, nil
+}
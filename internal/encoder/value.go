@@ -0,0 +1,112 @@
+package encoder
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/goccy/go-json/internal/dom"
+	"github.com/goccy/go-json/internal/errors"
+	"github.com/goccy/go-json/internal/runtime"
+)
+
+var valueType = runtime.Type2RType(reflect.TypeOf(dom.Value{}))
+
+// maxValueDepth is the nesting depth of json.Value trees from which they are assumed to be cyclic.
+const maxValueDepth = 10000
+
+// ValueAppender appends the scalars and object keys of json.Value trees,
+// so that each VM can write them the same way as the values it encodes itself.
+type ValueAppender struct {
+	Null   func(ctx *RuntimeContext, b []byte) []byte
+	Bool   func(ctx *RuntimeContext, b []byte, v bool) []byte
+	Number func(ctx *RuntimeContext, b []byte, n json.Number) ([]byte, error)
+	String func(ctx *RuntimeContext, b []byte, v string) []byte
+	Key    func(ctx *RuntimeContext, b []byte, key string) []byte
+}
+
+// PlainValueAppender appends json.Value trees without colors.
+var PlainValueAppender = &ValueAppender{
+	Null: AppendNull,
+	Bool: func(_ *RuntimeContext, b []byte, v bool) []byte {
+		if v {
+			return append(b, "true"...)
+		}
+		return append(b, "false"...)
+	},
+	Number: AppendNumber,
+	String: AppendString,
+	Key:    AppendString,
+}
+
+// AppendValue appends the json.Value tree v with a.
+func AppendValue(ctx *RuntimeContext, a *ValueAppender, b []byte, v *dom.Value) ([]byte, error) {
+	return appendValue(ctx, a, b, v, 0, -1)
+}
+
+// AppendValueIndent appends the json.Value tree v with a, indented from the indent of code.
+func AppendValueIndent(ctx *RuntimeContext, a *ValueAppender, code *Opcode, b []byte, v *dom.Value) ([]byte, error) {
+	return appendValue(ctx, a, b, v, 0, int(code.Indent))
+}
+
+// appendValue appends v at the nesting depth. indent is -1 for compact output.
+func appendValue(ctx *RuntimeContext, a *ValueAppender, b []byte, v *dom.Value, depth, indent int) ([]byte, error) {
+	if depth > maxValueDepth {
+		return nil, &errors.UnsupportedValueError{
+			Value: reflect.ValueOf(v),
+			Str:   "encountered a cycle via *json.Value",
+		}
+	}
+	switch v.Kind() {
+	case dom.KindBool:
+		return a.Bool(ctx, b, v.Bool()), nil
+	case dom.KindNumber:
+		return a.Number(ctx, b, v.Number())
+	case dom.KindString:
+		return a.String(ctx, b, v.Text()), nil
+	case dom.KindArray, dom.KindObject:
+		keys, values := dom.Members(v)
+		isObject := v.Kind() == dom.KindObject
+		begin, end := byte('['), byte(']')
+		if isObject {
+			begin, end = '{', '}'
+		}
+		b = append(b, begin)
+		if len(values) == 0 {
+			return append(b, end), nil
+		}
+		var err error
+		for i, value := range values {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if indent >= 0 {
+				b = append(b, '\n')
+				b = AppendIndent(ctx, b, uint32(indent+1))
+			}
+			if isObject {
+				b = a.Key(ctx, b, keys[i])
+				b = append(b, ':')
+				if indent >= 0 {
+					b = append(b, ' ')
+				}
+			}
+			b, err = appendValue(ctx, a, b, value, depth+1, nextIndent(indent))
+			if err != nil {
+				return nil, err
+			}
+		}
+		if indent >= 0 {
+			b = append(b, '\n')
+			b = AppendIndent(ctx, b, uint32(indent))
+		}
+		return append(b, end), nil
+	}
+	return a.Null(ctx, b), nil
+}
+
+func nextIndent(indent int) int {
+	if indent < 0 {
+		return indent
+	}
+	return indent + 1
+}
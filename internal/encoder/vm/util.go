@@ -6,6 +6,7 @@ import (
 	"unicode"
 	"unsafe"
 
+	"github.com/goccy/go-json/internal/dom"
 	"github.com/goccy/go-json/internal/encoder"
 	"github.com/goccy/go-json/internal/runtime"
 )
@@ -89,6 +90,9 @@ func ptrToBytes(p uintptr) []byte               { return **(**[]byte)(unsafe.Poi
 func ptrToNumber(p uintptr) json.Number         { return **(**json.Number)(unsafe.Pointer(&p)) }
 func ptrToString(p uintptr) string              { return **(**string)(unsafe.Pointer(&p)) }
 func ptrToSlice(p uintptr) *runtime.SliceHeader { return *(**runtime.SliceHeader)(unsafe.Pointer(&p)) }
+func ptrToOptionalState(p uintptr) runtime.OptionalState {
+	return **(**runtime.OptionalState)(unsafe.Pointer(&p))
+}
 func ptrToPtr(p uintptr) uintptr {
 	return uintptr(**(**unsafe.Pointer)(unsafe.Pointer(&p)))
 }
@@ -102,6 +106,11 @@ func ptrToNPtr(p uintptr, ptrNum uint8) uintptr {
 	return p
 }
 
+// isUnsetOptional reports whether the field value at p encoded by code is an Optional that is not set.
+func isUnsetOptional(code *encoder.Opcode, p uintptr) bool {
+	return code.Op == encoder.OpOptional && ptrToOptionalState(p) == runtime.OptionalUnset
+}
+
 func ptrToUnsafePtr(p uintptr) unsafe.Pointer {
 	return *(*unsafe.Pointer)(unsafe.Pointer(&p))
 }
@@ -149,10 +158,19 @@ func appendMapEnd(_ *encoder.RuntimeContext, _ *encoder.Opcode, b []byte) []byte
 	return b
 }
 
+func appendOrderedMapKey(ctx *encoder.RuntimeContext, _ *encoder.Opcode, b []byte, key string) []byte {
+	b = appendString(ctx, b, key)
+	return append(b, ':')
+}
+
 func appendMarshalJSON(ctx *encoder.RuntimeContext, code *encoder.Opcode, b []byte, v interface{}) ([]byte, error) {
 	return encoder.AppendMarshalJSON(ctx, code, b, v)
 }
 
+func appendValue(ctx *encoder.RuntimeContext, _ *encoder.Opcode, b []byte, p uintptr) ([]byte, error) {
+	return encoder.AppendValue(ctx, encoder.PlainValueAppender, b, (*dom.Value)(ptrToUnsafePtr(p)))
+}
+
 func appendMarshalText(ctx *encoder.RuntimeContext, code *encoder.Opcode, b []byte, v interface{}) ([]byte, error) {
 	return encoder.AppendMarshalText(ctx, code, b, v)
 }
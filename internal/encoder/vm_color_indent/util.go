@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"unsafe"
 
+	"github.com/goccy/go-json/internal/dom"
 	"github.com/goccy/go-json/internal/encoder"
 	"github.com/goccy/go-json/internal/runtime"
 )
@@ -84,6 +85,9 @@ func ptrToBytes(p uintptr) []byte               { return **(**[]byte)(unsafe.Poi
 func ptrToNumber(p uintptr) json.Number         { return **(**json.Number)(unsafe.Pointer(&p)) }
 func ptrToString(p uintptr) string              { return **(**string)(unsafe.Pointer(&p)) }
 func ptrToSlice(p uintptr) *runtime.SliceHeader { return *(**runtime.SliceHeader)(unsafe.Pointer(&p)) }
+func ptrToOptionalState(p uintptr) runtime.OptionalState {
+	return **(**runtime.OptionalState)(unsafe.Pointer(&p))
+}
 func ptrToPtr(p uintptr) uintptr {
 	return uintptr(**(**unsafe.Pointer)(unsafe.Pointer(&p)))
 }
@@ -97,6 +101,11 @@ func ptrToNPtr(p uintptr, ptrNum uint8) uintptr {
 	return p
 }
 
+// isUnsetOptional reports whether the field value at p encoded by code is an Optional that is not set.
+func isUnsetOptional(code *encoder.Opcode, p uintptr) bool {
+	return code.Op == encoder.OpOptional && ptrToOptionalState(p) == runtime.OptionalUnset
+}
+
 func ptrToUnsafePtr(p uintptr) unsafe.Pointer {
 	return *(*unsafe.Pointer)(unsafe.Pointer(&p))
 }
@@ -142,10 +151,14 @@ func appendString(ctx *encoder.RuntimeContext, b []byte, v string) []byte {
 	return append(b, format.Footer...)
 }
 
-func appendByteSlice(ctx *encoder.RuntimeContext, b []byte, src []byte) []byte {
+func appendByteSlice(ctx *encoder.RuntimeContext, code *encoder.Opcode, b []byte, src []byte) []byte {
+	if encoder.ByteSliceFormat(ctx, code) == runtime.BytesFormatArray {
+		// the array is colored by its elements.
+		return encoder.AppendByteSliceIndent(ctx, code, b, src)
+	}
 	format := ctx.Option.ColorScheme.Binary
 	b = append(b, format.Header...)
-	b = encoder.AppendByteSlice(ctx, b, src)
+	b = encoder.AppendByteSliceIndent(ctx, code, b, src)
 	return append(b, format.Footer...)
 }
 
@@ -207,6 +220,12 @@ func appendMapEnd(ctx *encoder.RuntimeContext, code *encoder.Opcode, b []byte) [
 	return append(b, '}', ',', '\n')
 }
 
+func appendOrderedMapKey(ctx *encoder.RuntimeContext, code *encoder.Opcode, b []byte, key string) []byte {
+	b = appendIndent(ctx, b, code.Indent+1)
+	b = appendString(ctx, b, key)
+	return append(b, ':', ' ')
+}
+
 func appendArrayHead(ctx *encoder.RuntimeContext, code *encoder.Opcode, b []byte) []byte {
 	b = append(b, '[', '\n')
 	return appendIndent(ctx, b, code.Indent+1)
@@ -238,6 +257,24 @@ func appendMarshalJSON(ctx *encoder.RuntimeContext, code *encoder.Opcode, b []by
 	return encoder.AppendMarshalJSONIndent(ctx, code, b, v)
 }
 
+// valueAppender writes the scalars and object keys of json.Value trees in the colors of the ColorScheme.
+var valueAppender = &encoder.ValueAppender{
+	Null:   appendNull,
+	Bool:   appendBool,
+	Number: appendNumber,
+	String: appendString,
+	Key: func(ctx *encoder.RuntimeContext, b []byte, key string) []byte {
+		format := ctx.Option.ColorScheme.ObjectKey
+		b = append(b, format.Header...)
+		b = encoder.AppendString(ctx, b, key)
+		return append(b, format.Footer...)
+	},
+}
+
+func appendValue(ctx *encoder.RuntimeContext, code *encoder.Opcode, b []byte, p uintptr) ([]byte, error) {
+	return encoder.AppendValueIndent(ctx, valueAppender, code, b, (*dom.Value)(ptrToUnsafePtr(p)))
+}
+
 func appendMarshalText(ctx *encoder.RuntimeContext, code *encoder.Opcode, b []byte, v interface{}) ([]byte, error) {
 	format := ctx.Option.ColorScheme.String
 	b = append(b, format.Header...)
@@ -67,6 +67,42 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 			b = append(b, '"')
 			b = appendComma(ctx, b)
 			code = code.Next
+		case encoder.OpFloat32String:
+			b = append(b, '"')
+			b = appendFloat32(ctx, b, ptrToFloat32(load(ctxptr, code.Idx)))
+			b = append(b, '"')
+			b = appendComma(ctx, b)
+			code = code.Next
+		case encoder.OpFloat64String:
+			v := ptrToFloat64(load(ctxptr, code.Idx))
+			if math.IsInf(v, 0) || math.IsNaN(v) {
+				return nil, errUnsupportedFloat(v)
+			}
+			b = append(b, '"')
+			b = appendFloat64(ctx, b, v)
+			b = append(b, '"')
+			b = appendComma(ctx, b)
+			code = code.Next
+		case encoder.OpStringString:
+			s := ptrToString(load(ctxptr, code.Idx))
+			b = appendString(ctx, b, string(appendString(ctx, []byte{}, s)))
+			b = appendComma(ctx, b)
+			code = code.Next
+		case encoder.OpBoolString:
+			b = append(b, '"')
+			b = appendBool(ctx, b, ptrToBool(load(ctxptr, code.Idx)))
+			b = append(b, '"')
+			b = appendComma(ctx, b)
+			code = code.Next
+		case encoder.OpNumberString:
+			b = append(b, '"')
+			bb, err := appendNumber(ctx, b, ptrToNumber(load(ctxptr, code.Idx)))
+			if err != nil {
+				return nil, err
+			}
+			b = append(bb, '"')
+			b = appendComma(ctx, b)
+			code = code.Next
 		case encoder.OpFloat32Ptr:
 			p := loadNPtr(ctxptr, code.Idx, code.PtrNum)
 			if p == 0 {
@@ -134,7 +170,7 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 			store(ctxptr, code.Idx, p)
 			fallthrough
 		case encoder.OpBytes:
-			b = appendByteSlice(ctx, b, ptrToBytes(load(ctxptr, code.Idx)))
+			b = appendByteSlice(ctx, code, b, ptrToBytes(load(ctxptr, code.Idx)))
 			b = appendComma(ctx, b)
 			code = code.Next
 		case encoder.OpNumberPtr:
@@ -231,6 +267,9 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 			store(ctxptr, end.Idx, oldOffset)
 			store(ctxptr, end.ElemIdx, uintptr(unsafe.Pointer(code.Next)))
 			storeIndent(ctxptr, end, uintptr(oldBaseIndent))
+			if (ctx.Option.Flag & encoder.RedactOption) != 0 {
+				ctx.RedactPath = append(ctx.RedactPath, ctx.Path(code.Key))
+			}
 			code = c
 			recursiveLevel++
 		case encoder.OpInterfaceEnd:
@@ -240,11 +279,99 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 			offset := load(ctxptr, code.Idx)
 			restoreIndent(ctx, code, ctxptr)
 			ctx.SeenPtr = ctx.SeenPtr[:len(ctx.SeenPtr)-1]
+			if (ctx.Option.Flag & encoder.RedactOption) != 0 {
+				ctx.RedactPath = ctx.RedactPath[:len(ctx.RedactPath)-1]
+			}
 
 			codePtr := load(ctxptr, code.ElemIdx)
 			code = (*encoder.Opcode)(ptrToUnsafePtr(codePtr))
 			ctxptr = ctx.Ptr() + offset
 			ptrOffset = offset
+		case encoder.OpValuePtr:
+			p := loadNPtr(ctxptr, code.Idx, code.PtrNum)
+			if p == 0 {
+				b = appendNullComma(ctx, b)
+				code = code.Next
+				break
+			}
+			store(ctxptr, code.Idx, p)
+			fallthrough
+		case encoder.OpValue:
+			bb, err := appendValue(ctx, code, b, load(ctxptr, code.Idx))
+			if err != nil {
+				return nil, err
+			}
+			b = appendComma(ctx, bb)
+			code = code.Next
+		case encoder.OpOptionalPtr:
+			p := loadNPtr(ctxptr, code.Idx, code.PtrNum)
+			if p == 0 {
+				b = appendNullComma(ctx, b)
+				code = code.End.Next
+				break
+			}
+			store(ctxptr, code.Idx, p)
+			fallthrough
+		case encoder.OpOptional:
+			p := load(ctxptr, code.Idx)
+			if p == 0 || ptrToOptionalState(p) != runtime.OptionalSet {
+				b = appendNullComma(ctx, b)
+				code = code.End.Next
+				break
+			}
+			p += uintptr(code.Offset)
+			code = code.Next
+			store(ctxptr, code.Idx, p)
+		case encoder.OpOrderedMapPtr:
+			p := loadNPtr(ctxptr, code.Idx, code.PtrNum)
+			if p == 0 {
+				b = appendNullComma(ctx, b)
+				code = code.End.Next
+				break
+			}
+			store(ctxptr, code.Idx, p)
+			fallthrough
+		case encoder.OpOrderedMap:
+			p := load(ctxptr, code.Idx)
+			if p == 0 {
+				b = appendNullComma(ctx, b)
+				code = code.End.Next
+				break
+			}
+			keys := ptrToSlice(p)
+			if keys.Len == 0 {
+				b = appendEmptyObject(ctx, b)
+				code = code.End.Next
+				break
+			}
+			store(ctxptr, code.ElemIdx, 0)
+			store(ctxptr, code.Length, uintptr(keys.Len))
+			b = appendStructHead(ctx, b)
+			b = appendOrderedMapKey(ctx, code, b, ptrToString(uintptr(keys.Data)))
+			values := ptrToSlice(p + uintptr(code.Offset))
+			code = code.Next
+			store(ctxptr, code.Idx, uintptr(values.Data))
+		case encoder.OpOrderedMapElem:
+			idx := load(ctxptr, code.ElemIdx)
+			length := load(ctxptr, code.Length)
+			idx++
+			if idx < length {
+				store(ctxptr, code.ElemIdx, idx)
+				p := load(ctxptr, code.Idx)
+				keys := ptrToSlice(p)
+				b = appendOrderedMapKey(ctx, code, b, ptrToString(uintptr(keys.Data)+idx*unsafe.Sizeof("")))
+				values := ptrToSlice(p + uintptr(code.Offset))
+				size := uintptr(code.Size)
+				code = code.Next
+				store(ctxptr, code.Idx, uintptr(values.Data)+idx*size)
+			} else {
+				b = appendMapEnd(ctx, code, b)
+				code = code.End.Next
+			}
+		case encoder.OpRedact:
+			b = appendString(ctx, b, ctx.Option.Redact(ctx.Path(code.Key)))
+			b = appendComma(ctx, b)
+			code = code.Next
 		case encoder.OpMarshalJSONPtr:
 			p := load(ctxptr, code.Idx)
 			if p == 0 {
@@ -307,12 +434,22 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 			fallthrough
 		case encoder.OpSlice:
 			p := load(ctxptr, code.Idx)
-			slice := ptrToSlice(p)
-			if p == 0 || slice.Data == nil {
+			if p == 0 {
+				// nil pointer to the slice
 				b = appendNullComma(ctx, b)
 				code = code.End.Next
 				break
 			}
+			slice := ptrToSlice(p)
+			if slice.Data == nil {
+				if code.Flags&encoder.NilAsEmptyFlags != 0 || ctx.Option.Flag&encoder.NilSliceAsEmptyOption != 0 {
+					b = appendEmptyArray(ctx, b)
+				} else {
+					b = appendNullComma(ctx, b)
+				}
+				code = code.End.Next
+				break
+			}
 			store(ctxptr, code.ElemIdx, 0)
 			store(ctxptr, code.Length, uintptr(slice.Len))
 			store(ctxptr, code.Idx, uintptr(slice.Data))
@@ -379,18 +516,23 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 				code = code.End.Next
 			}
 		case encoder.OpMapPtr:
-			p := loadNPtr(ctxptr, code.Idx, code.PtrNum)
+			// the last dereference loads the map, which is handled by OpMap when it is nil
+			p := loadNPtr(ctxptr, code.Idx, code.PtrNum-1)
 			if p == 0 {
 				b = appendNullComma(ctx, b)
 				code = code.End.Next
 				break
 			}
-			store(ctxptr, code.Idx, p)
+			store(ctxptr, code.Idx, ptrToPtr(p))
 			fallthrough
 		case encoder.OpMap:
 			p := load(ctxptr, code.Idx)
 			if p == 0 {
-				b = appendNullComma(ctx, b)
+				if code.Flags&encoder.NilAsEmptyFlags != 0 || ctx.Option.Flag&encoder.NilMapAsEmptyOption != 0 {
+					b = appendEmptyObject(ctx, b)
+				} else {
+					b = appendNullComma(ctx, b)
+				}
 				code = code.End.Next
 				break
 			}
@@ -578,7 +720,7 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 				b = appendStructHead(ctx, b)
 			}
 			p += uintptr(code.Offset)
-			if p == 0 || (ptrToPtr(p) == 0 && (code.Flags&encoder.IsNextOpPtrTypeFlags) != 0) {
+			if p == 0 || (ptrToPtr(p) == 0 && (code.Flags&encoder.IsNextOpPtrTypeFlags) != 0) || isUnsetOptional(code.Next, p) {
 				code = code.NextField
 			} else {
 				b = appendStructKey(ctx, code, b)
@@ -2235,7 +2377,7 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 				b = appendStructHead(ctx, b)
 			}
 			b = appendStructKey(ctx, code, b)
-			b = appendByteSlice(ctx, b, ptrToBytes(p+uintptr(code.Offset)))
+			b = appendByteSlice(ctx, code, b, ptrToBytes(p+uintptr(code.Offset)))
 			b = appendComma(ctx, b)
 			code = code.Next
 		case encoder.OpStructPtrHeadOmitEmptyBytes:
@@ -2268,7 +2410,7 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 				code = code.NextField
 			} else {
 				b = appendStructKey(ctx, code, b)
-				b = appendByteSlice(ctx, b, v)
+				b = appendByteSlice(ctx, code, b, v)
 				b = appendComma(ctx, b)
 				code = code.Next
 			}
@@ -2302,7 +2444,7 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 			if p == 0 {
 				b = appendNull(ctx, b)
 			} else {
-				b = appendByteSlice(ctx, b, ptrToBytes(p))
+				b = appendByteSlice(ctx, code, b, ptrToBytes(p))
 			}
 			b = appendComma(ctx, b)
 			code = code.Next
@@ -2334,7 +2476,7 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 			}
 			if p != 0 {
 				b = appendStructKey(ctx, code, b)
-				b = appendByteSlice(ctx, b, ptrToBytes(p))
+				b = appendByteSlice(ctx, code, b, ptrToBytes(p))
 				b = appendComma(ctx, b)
 			}
 			code = code.Next
@@ -3263,7 +3405,7 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 		case encoder.OpStructFieldOmitEmpty:
 			p := load(ctxptr, code.Idx)
 			p += uintptr(code.Offset)
-			if ptrToPtr(p) == 0 && (code.Flags&encoder.IsNextOpPtrTypeFlags) != 0 {
+			if (ptrToPtr(p) == 0 && (code.Flags&encoder.IsNextOpPtrTypeFlags) != 0) || isUnsetOptional(code.Next, p) {
 				code = code.NextField
 			} else {
 				b = appendStructKey(ctx, code, b)
@@ -3769,7 +3911,7 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 		case encoder.OpStructFieldBytes:
 			p := load(ctxptr, code.Idx)
 			b = appendStructKey(ctx, code, b)
-			b = appendByteSlice(ctx, b, ptrToBytes(p+uintptr(code.Offset)))
+			b = appendByteSlice(ctx, code, b, ptrToBytes(p+uintptr(code.Offset)))
 			b = appendComma(ctx, b)
 			code = code.Next
 		case encoder.OpStructFieldOmitEmptyBytes:
@@ -3777,7 +3919,7 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 			v := ptrToBytes(p + uintptr(code.Offset))
 			if len(v) > 0 {
 				b = appendStructKey(ctx, code, b)
-				b = appendByteSlice(ctx, b, v)
+				b = appendByteSlice(ctx, code, b, v)
 				b = appendComma(ctx, b)
 			}
 			code = code.Next
@@ -3788,7 +3930,7 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 			if p == 0 {
 				b = appendNull(ctx, b)
 			} else {
-				b = appendByteSlice(ctx, b, ptrToBytes(p))
+				b = appendByteSlice(ctx, code, b, ptrToBytes(p))
 			}
 			b = appendComma(ctx, b)
 			code = code.Next
@@ -3797,7 +3939,7 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 			p = ptrToNPtr(p+uintptr(code.Offset), code.PtrNum)
 			if p != 0 {
 				b = appendStructKey(ctx, code, b)
-				b = appendByteSlice(ctx, b, ptrToBytes(p))
+				b = appendByteSlice(ctx, code, b, ptrToBytes(p))
 				b = appendComma(ctx, b)
 			}
 			code = code.Next
@@ -4114,9 +4256,12 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 			b = appendStructKey(ctx, code, b)
 			p := load(ctxptr, code.Idx)
 			p = ptrToPtr(p + uintptr(code.Offset))
-			if p != 0 {
-				p = ptrToNPtr(p, code.PtrNum)
+			if p == 0 {
+				b = appendNullComma(ctx, b)
+				code = code.NextField
+				break
 			}
+			p = ptrToNPtr(p, code.PtrNum)
 			code = code.Next
 			store(ctxptr, code.Idx, p)
 		case encoder.OpStructFieldOmitEmptyMapPtr:
@@ -4699,7 +4844,7 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 		case encoder.OpStructEndBytes:
 			p := load(ctxptr, code.Idx)
 			b = appendStructKey(ctx, code, b)
-			b = appendByteSlice(ctx, b, ptrToBytes(p+uintptr(code.Offset)))
+			b = appendByteSlice(ctx, code, b, ptrToBytes(p+uintptr(code.Offset)))
 			b = appendStructEnd(ctx, code, b)
 			code = code.Next
 		case encoder.OpStructEndOmitEmptyBytes:
@@ -4707,7 +4852,7 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 			v := ptrToBytes(p + uintptr(code.Offset))
 			if len(v) > 0 {
 				b = appendStructKey(ctx, code, b)
-				b = appendByteSlice(ctx, b, v)
+				b = appendByteSlice(ctx, code, b, v)
 				b = appendStructEnd(ctx, code, b)
 			} else {
 				b = appendStructEndSkipLast(ctx, code, b)
@@ -4720,7 +4865,7 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 			if p == 0 {
 				b = appendNull(ctx, b)
 			} else {
-				b = appendByteSlice(ctx, b, ptrToBytes(p))
+				b = appendByteSlice(ctx, code, b, ptrToBytes(p))
 			}
 			b = appendStructEnd(ctx, code, b)
 			code = code.Next
@@ -4729,7 +4874,7 @@ func Run(ctx *encoder.RuntimeContext, b []byte, codeSet *encoder.OpcodeSet) ([]b
 			p = ptrToNPtr(p+uintptr(code.Offset), code.PtrNum)
 			if p != 0 {
 				b = appendStructKey(ctx, code, b)
-				b = appendByteSlice(ctx, b, ptrToBytes(p))
+				b = appendByteSlice(ctx, code, b, ptrToBytes(p))
 				b = appendStructEnd(ctx, code, b)
 			} else {
 				b = appendStructEndSkipLast(ctx, code, b)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"unsafe"
 
+	"github.com/goccy/go-json/internal/dom"
 	"github.com/goccy/go-json/internal/encoder"
 	"github.com/goccy/go-json/internal/runtime"
 )
@@ -17,7 +18,7 @@ var (
 	appendFloat32       = encoder.AppendFloat32
 	appendFloat64       = encoder.AppendFloat64
 	appendString        = encoder.AppendString
-	appendByteSlice     = encoder.AppendByteSlice
+	appendByteSlice     = encoder.AppendByteSliceIndent
 	appendNumber        = encoder.AppendNumber
 	appendStructEnd     = encoder.AppendStructEndIndent
 	appendIndent        = encoder.AppendIndent
@@ -90,6 +91,9 @@ func ptrToBytes(p uintptr) []byte               { return **(**[]byte)(unsafe.Poi
 func ptrToNumber(p uintptr) json.Number         { return **(**json.Number)(unsafe.Pointer(&p)) }
 func ptrToString(p uintptr) string              { return **(**string)(unsafe.Pointer(&p)) }
 func ptrToSlice(p uintptr) *runtime.SliceHeader { return *(**runtime.SliceHeader)(unsafe.Pointer(&p)) }
+func ptrToOptionalState(p uintptr) runtime.OptionalState {
+	return **(**runtime.OptionalState)(unsafe.Pointer(&p))
+}
 func ptrToPtr(p uintptr) uintptr {
 	return uintptr(**(**unsafe.Pointer)(unsafe.Pointer(&p)))
 }
@@ -103,6 +107,11 @@ func ptrToNPtr(p uintptr, ptrNum uint8) uintptr {
 	return p
 }
 
+// isUnsetOptional reports whether the field value at p encoded by code is an Optional that is not set.
+func isUnsetOptional(code *encoder.Opcode, p uintptr) bool {
+	return code.Op == encoder.OpOptional && ptrToOptionalState(p) == runtime.OptionalUnset
+}
+
 func ptrToUnsafePtr(p uintptr) unsafe.Pointer {
 	return *(*unsafe.Pointer)(unsafe.Pointer(&p))
 }
@@ -151,6 +160,12 @@ func appendMapEnd(ctx *encoder.RuntimeContext, code *encoder.Opcode, b []byte) [
 	return append(b, '}', ',', '\n')
 }
 
+func appendOrderedMapKey(ctx *encoder.RuntimeContext, code *encoder.Opcode, b []byte, key string) []byte {
+	b = appendIndent(ctx, b, code.Indent+1)
+	b = appendString(ctx, b, key)
+	return append(b, ':', ' ')
+}
+
 func appendArrayHead(ctx *encoder.RuntimeContext, code *encoder.Opcode, b []byte) []byte {
 	b = append(b, '[', '\n')
 	return appendIndent(ctx, b, code.Indent+1)
@@ -182,6 +197,10 @@ func appendMarshalJSON(ctx *encoder.RuntimeContext, code *encoder.Opcode, b []by
 	return encoder.AppendMarshalJSONIndent(ctx, code, b, v)
 }
 
+func appendValue(ctx *encoder.RuntimeContext, code *encoder.Opcode, b []byte, p uintptr) ([]byte, error) {
+	return encoder.AppendValueIndent(ctx, encoder.PlainValueAppender, code, b, (*dom.Value)(ptrToUnsafePtr(p)))
+}
+
 func appendMarshalText(ctx *encoder.RuntimeContext, code *encoder.Opcode, b []byte, v interface{}) ([]byte, error) {
 	return encoder.AppendMarshalTextIndent(ctx, code, b, v)
 }
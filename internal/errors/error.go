@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 )
 
 type InvalidUTF8Error struct {
@@ -51,6 +52,7 @@ func (e *MarshalerError) Unwrap() error { return e.Err }
 type SyntaxError struct {
 	msg    string // description of error
 	Offset int64  // error occurred after reading Offset bytes
+	Location
 }
 
 func (e *SyntaxError) Error() string { return e.msg }
@@ -79,6 +81,7 @@ type UnmarshalTypeError struct {
 	Offset int64        // error occurred after reading Offset bytes
 	Struct string       // name of the struct type containing the field
 	Field  string       // the full path from root node to the field
+	Location
 }
 
 func (e *UnmarshalTypeError) Error() string {
@@ -156,9 +159,145 @@ func ErrInvalidCharacter(c byte, context string, cursor int64) *SyntaxError {
 	}
 }
 
+func ErrAfterTopLevelValue(c byte, cursor int64) *SyntaxError {
+	return &SyntaxError{
+		msg:    fmt.Sprintf("invalid character '%c' after top-level value", c),
+		Offset: cursor,
+	}
+}
+
 func ErrInvalidBeginningOfValue(c byte, cursor int64) *SyntaxError {
 	return &SyntaxError{
 		msg:    fmt.Sprintf("invalid character '%c' looking for beginning of value", c),
 		Offset: cursor,
 	}
 }
+
+// A DepthLimitError is returned when the nesting depth of the input
+// exceeds the MaxDepth decode limit.
+type DepthLimitError struct {
+	Max    int64 // configured limit
+	Offset int64 // error occurred after reading Offset bytes
+	Location
+}
+
+func (e *DepthLimitError) Error() string {
+	return fmt.Sprintf("json: exceeded max depth %d", e.Max)
+}
+
+// A StringLimitError is returned when a JSON string is longer than
+// the MaxStringLen decode limit.
+type StringLimitError struct {
+	Max    int64 // configured limit
+	Len    int64 // length of the string that was found
+	Offset int64 // error occurred after reading Offset bytes
+	Location
+}
+
+func (e *StringLimitError) Error() string {
+	return fmt.Sprintf("json: string length %d exceeds limit %d", e.Len, e.Max)
+}
+
+// An ArrayLimitError is returned when a JSON array has more elements than
+// the MaxArrayLen decode limit.
+type ArrayLimitError struct {
+	Max    int64 // configured limit
+	Offset int64 // error occurred after reading Offset bytes
+	Location
+}
+
+func (e *ArrayLimitError) Error() string {
+	return fmt.Sprintf("json: array length exceeds limit %d", e.Max)
+}
+
+// An ObjectKeysLimitError is returned when a JSON object has more keys than
+// the MaxObjectKeys decode limit.
+type ObjectKeysLimitError struct {
+	Max    int64 // configured limit
+	Offset int64 // error occurred after reading Offset bytes
+	Location
+}
+
+func (e *ObjectKeysLimitError) Error() string {
+	return fmt.Sprintf("json: number of object keys exceeds limit %d", e.Max)
+}
+
+// A BytesLimitError is returned when the input is larger than
+// the MaxBytes decode limit.
+type BytesLimitError struct {
+	Max int64 // configured limit
+}
+
+func (e *BytesLimitError) Error() string {
+	return fmt.Sprintf("json: input exceeds limit of %d bytes", e.Max)
+}
+
+// A DuplicateKeyError is returned when an object contains the same key more than once
+// and duplicate keys are rejected.
+type DuplicateKeyError struct {
+	Key    string // the duplicated object key
+	Offset int64  // error occurred after reading Offset bytes
+	Location
+}
+
+func (e *DuplicateKeyError) Error() string {
+	return fmt.Sprintf("json: duplicate key %q at offset %d", e.Key, e.Offset)
+}
+
+// DecodeErrors is returned when values are skipped because of type errors
+// while collecting errors. It lists every failure in input order.
+type DecodeErrors []*UnmarshalTypeError
+
+func (e DecodeErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the collected errors.
+func (e DecodeErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// A SchemaError is returned when a JSON value does not conform to a JSON Schema.
+type SchemaError struct {
+	Keyword         string // the failed schema keyword, e.g. "minLength"
+	KeywordLocation string // JSON pointer to the failed keyword in the schema, e.g. "/properties/name/minLength"
+	Message         string // description of the failure
+	Offset          int64  // error occurred after reading Offset bytes
+	Location
+}
+
+func (e *SchemaError) Error() string {
+	if e.Path != "" {
+		return fmt.Sprintf("json: schema validation failed at %s: %s", e.Path, e.Message)
+	}
+	return fmt.Sprintf("json: schema validation failed: %s", e.Message)
+}
+
+// SchemaErrors is returned when a JSON value does not conform to a JSON Schema.
+// It lists every failure in input order.
+type SchemaErrors []*SchemaError
+
+func (e SchemaErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap returns the schema errors.
+func (e SchemaErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
@@ -0,0 +1,171 @@
+package errors
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// snippetWidth is the maximum number of bytes shown on each side of the caret by Snippet.
+const snippetWidth = 40
+
+// Location describes where in the input a decode error occurred.
+// It is embedded in the errors returned while decoding.
+type Location struct {
+	Line   int64  // 1-based line number of the failing byte
+	Column int64  // 1-based byte column of the failing byte
+	Path   string // JSON path of the failing value, e.g. $.items[3].price
+
+	located bool
+	text    string // offending line, possibly shortened to the bytes around the caret
+	caret   int    // position of the caret in text
+}
+
+// Snippet renders the offending line of the input with a caret
+// pointing at the failing byte.
+// It returns an empty string if the location is unknown.
+func (l *Location) Snippet() string {
+	if l.Line == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(l.text)
+	b.WriteByte('\n')
+	for i := 0; i < l.caret; i++ {
+		if l.text[i] == '\t' {
+			b.WriteByte('\t')
+		} else {
+			b.WriteByte(' ')
+		}
+	}
+	b.WriteByte('^')
+	return b.String()
+}
+
+func locationOf(err error) (*Location, int64) {
+	switch e := err.(type) {
+	case *SyntaxError:
+		return &e.Location, e.Offset
+	case *UnmarshalTypeError:
+		return &e.Location, e.Offset
+	case *DuplicateKeyError:
+		return &e.Location, e.Offset
+	case *DepthLimitError:
+		return &e.Location, e.Offset
+	case *StringLimitError:
+		return &e.Location, e.Offset
+	case *ArrayLimitError:
+		return &e.Location, e.Offset
+	case *ObjectKeysLimitError:
+		return &e.Location, e.Offset
+	case *SchemaError:
+		return &e.Location, e.Offset
+	}
+	return nil, 0
+}
+
+// PrependPathKey adds the object key to the front of the JSON path of err.
+// It is called by the object decoders while a decode error is returned to the caller.
+func PrependPathKey(err error, key string) error {
+	loc, _ := locationOf(err)
+	if loc == nil || loc.located {
+		return err
+	}
+	if isIdentifier(key) {
+		loc.Path = "." + key + loc.Path
+	} else {
+		loc.Path = "[" + strconv.Quote(key) + "]" + loc.Path
+	}
+	return err
+}
+
+// PrependPathIndex adds the array index to the front of the JSON path of err.
+// It is called by the array decoders while a decode error is returned to the caller.
+func PrependPathIndex(err error, idx int) error {
+	loc, _ := locationOf(err)
+	if loc == nil || loc.located {
+		return err
+	}
+	loc.Path = "[" + strconv.Itoa(idx) + "]" + loc.Path
+	return err
+}
+
+// Locate fills in the line, column, path and snippet of err.
+// buf holds the input starting at offset base, line is the number of
+// the line containing base and lineStart is the offset where that line begins.
+// Errors that were already located, e.g. by a nested Unmarshal call, are left unchanged.
+func Locate(err error, buf []byte, base, line, lineStart int64) error {
+	loc, offset := locationOf(err)
+	if loc == nil || loc.located {
+		return err
+	}
+	if loc.Line == 0 {
+		loc.setPosition(offset, buf, base, line, lineStart)
+	}
+	loc.located = true
+	loc.Path = "$" + loc.Path
+	return err
+}
+
+// LocatePosition fills in the line, column and snippet of err like Locate,
+// but still allows the path of err to be extended.
+// It is used when the input around err is not kept until decoding finishes.
+func LocatePosition(err error, buf []byte, base, line, lineStart int64) {
+	loc, offset := locationOf(err)
+	if loc == nil || loc.located || loc.Line != 0 {
+		return
+	}
+	loc.setPosition(offset, buf, base, line, lineStart)
+}
+
+func (l *Location) setPosition(offset int64, buf []byte, base, line, lineStart int64) {
+	pos := offset - base
+	if pos < 0 {
+		pos = 0
+	}
+	if pos > int64(len(buf)) {
+		pos = int64(len(buf))
+	}
+	before := buf[:pos]
+	if n := bytes.Count(before, []byte{'\n'}); n > 0 {
+		line += int64(n)
+		lineStart = base + int64(bytes.LastIndexByte(before, '\n')) + 1
+	}
+	from := lineStart - base
+	if from < 0 {
+		from = 0
+	}
+	if pos-from > snippetWidth {
+		from = pos - snippetWidth
+	}
+	to := int64(len(buf))
+	if idx := bytes.IndexByte(buf[pos:], '\n'); idx >= 0 {
+		to = pos + int64(idx)
+	}
+	if to-pos > snippetWidth+1 {
+		to = pos + snippetWidth + 1
+	}
+	l.Line = line
+	l.Column = base + pos - lineStart + 1
+	l.text = strings.TrimSuffix(string(buf[from:to]), "\r")
+	l.caret = int(pos - from)
+	if l.caret > len(l.text) {
+		l.caret = len(l.text)
+	}
+}
+
+func isIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,345 @@
+package jsonpath
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/goccy/go-json/internal/decoder"
+	"github.com/goccy/go-json/internal/errors"
+)
+
+// Node is a value selected by a query.
+type Node struct {
+	Path       string // normalized path of the value, e.g. $['store']['book'][0]
+	Start, End int64  // position of the value in the evaluated buffer
+}
+
+type node struct {
+	start, end int64
+	depth      int64
+	loc        *location
+}
+
+// location is the normalized path of a node, linked from the node to the root.
+type location struct {
+	parent *location
+	key    string
+	index  int
+	isKey  bool
+}
+
+func (l *location) appendPath(b []byte) []byte {
+	if l == nil {
+		return append(b, '$')
+	}
+	b = l.parent.appendPath(b)
+	if !l.isKey {
+		b = append(b, '[')
+		b = strconv.AppendInt(b, int64(l.index), 10)
+		return append(b, ']')
+	}
+	b = append(b, '[', '\'')
+	for i := 0; i < len(l.key); i++ {
+		switch c := l.key[i]; c {
+		case '\b':
+			b = append(b, `\b`...)
+		case '\f':
+			b = append(b, `\f`...)
+		case '\n':
+			b = append(b, `\n`...)
+		case '\r':
+			b = append(b, `\r`...)
+		case '\t':
+			b = append(b, `\t`...)
+		case '\'', '\\':
+			b = append(b, '\\', c)
+		default:
+			if c < 0x20 {
+				b = append(b, `\u00`...)
+				b = append(b, hex[c>>4], hex[c&0xf])
+			} else {
+				b = append(b, c)
+			}
+		}
+	}
+	return append(b, '\'', ']')
+}
+
+const hex = "0123456789abcdef"
+
+// child is a member of an object or an element of an array.
+type child struct {
+	key        []byte
+	start, end int64
+}
+
+type evaluator struct {
+	buf     []byte
+	root    node
+	err     error
+	regexps map[string]*regexp.Regexp
+}
+
+// Eval evaluates q against the JSON value in buf, which must end with a nul byte,
+// and returns the selected nodes in order.
+func (q *Query) Eval(buf []byte) ([]Node, error) {
+	s := &decoder.Scanner{Buf: buf}
+	s.Next()
+	start := s.Cursor
+	end, err := decoder.SkipValue(buf, start, 0)
+	if err != nil {
+		return nil, err
+	}
+	s.Cursor = end
+	if c := s.Next(); c != 0 || s.Cursor != int64(len(buf))-1 {
+		return nil, errors.ErrAfterTopLevelValue(c, s.Cursor)
+	}
+	e := &evaluator{buf: buf, root: node{start: start, end: end}}
+	nodes := e.query(q.query, e.root)
+	if e.err != nil {
+		return nil, e.err
+	}
+	result := make([]Node, 0, len(nodes))
+	var path []byte
+	for _, n := range nodes {
+		path = n.loc.appendPath(path[:0])
+		result = append(result, Node{Path: string(path), Start: n.start, End: n.end})
+	}
+	return result, nil
+}
+
+func (e *evaluator) fail(err error) {
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+func (e *evaluator) query(q *query, current node) []node {
+	nodes := []node{e.root}
+	if q.relative {
+		nodes[0] = current
+	}
+	for _, seg := range q.segments {
+		var selected []node
+		for _, n := range nodes {
+			selected = e.segment(seg, n, selected)
+		}
+		if len(selected) == 0 || e.err != nil {
+			return nil
+		}
+		nodes = selected
+	}
+	return nodes
+}
+
+// segment appends the nodes selected by seg from n to nodes.
+// A descendant segment is applied to n and then to each of its descendants in document order.
+func (e *evaluator) segment(seg *segment, n node, nodes []node) []node {
+	children := e.children(n)
+	for _, sel := range seg.selectors {
+		nodes = e.selector(sel, n, children, nodes)
+	}
+	if seg.descendant {
+		for i := range children {
+			nodes = e.segment(seg, e.child(n, children, i), nodes)
+		}
+	}
+	return nodes
+}
+
+// children returns the members of the object n or the elements of the array n, skipping their values.
+func (e *evaluator) children(n node) []child {
+	end := byte(']')
+	switch e.buf[n.start] {
+	case '{':
+		end = '}'
+	case '[':
+	default:
+		return nil
+	}
+	var children []child
+	s := &decoder.Scanner{Buf: e.buf, Cursor: n.start}
+	if err := s.Begin(e.buf[n.start], n.depth+1); err != nil {
+		e.fail(err)
+		return nil
+	}
+	for first := true; ; first = false {
+		more, err := s.Element(first, end)
+		if err != nil {
+			e.fail(err)
+			return nil
+		}
+		if !more {
+			return children
+		}
+		var key []byte
+		if end == '}' {
+			if key, err = s.Key(); err != nil {
+				e.fail(err)
+				return nil
+			}
+			s.Next()
+		}
+		cursor, err := decoder.SkipValue(e.buf, s.Cursor, n.depth+1)
+		if err != nil {
+			e.fail(err)
+			return nil
+		}
+		children = append(children, child{key: key, start: s.Cursor, end: cursor})
+		s.Cursor = cursor
+	}
+}
+
+// child returns the node of the i'th child of n.
+func (e *evaluator) child(n node, children []child, i int) node {
+	c := children[i]
+	loc := &location{parent: n.loc, index: i}
+	if e.buf[n.start] == '{' {
+		loc.key = string(c.key)
+		loc.isKey = true
+	}
+	return node{start: c.start, end: c.end, depth: n.depth + 1, loc: loc}
+}
+
+func (e *evaluator) selector(sel *selector, n node, children []child, nodes []node) []node {
+	isArray := e.buf[n.start] == '['
+	switch sel.kind {
+	case nameSelector:
+		if isArray {
+			break
+		}
+		// the last member wins like when decoding into a map
+		for i := len(children) - 1; i >= 0; i-- {
+			if string(children[i].key) == sel.name {
+				return append(nodes, e.child(n, children, i))
+			}
+		}
+	case wildcardSelector:
+		for i := range children {
+			nodes = append(nodes, e.child(n, children, i))
+		}
+	case indexSelector:
+		if !isArray {
+			break
+		}
+		i := sel.index
+		if i < 0 {
+			i += int64(len(children))
+		}
+		if i >= 0 && i < int64(len(children)) {
+			nodes = append(nodes, e.child(n, children, int(i)))
+		}
+	case sliceSelector:
+		if !isArray {
+			break
+		}
+		lower, upper := sel.slice.bounds(int64(len(children)))
+		step := sel.slice.step
+		switch {
+		case step > 0:
+			for i := lower; i < upper; i += step {
+				nodes = append(nodes, e.child(n, children, int(i)))
+			}
+		case step < 0:
+			for i := upper; lower < i; i += step {
+				nodes = append(nodes, e.child(n, children, int(i)))
+			}
+		}
+	case filterSelector:
+		for i := range children {
+			c := e.child(n, children, i)
+			if sel.filter.test(e, c) {
+				nodes = append(nodes, c)
+			}
+		}
+	}
+	return nodes
+}
+
+// bounds returns the bounds of the slice for an array of length n as defined by RFC 9535:
+// [lower, upper) for a positive step and (lower, upper] for a negative step.
+func (s slice) bounds(n int64) (int64, int64) {
+	normalize := func(i int64) int64 {
+		if i >= 0 {
+			return i
+		}
+		return n + i
+	}
+	clamp := func(i, min, max int64) int64 {
+		if i < min {
+			return min
+		}
+		if i > max {
+			return max
+		}
+		return i
+	}
+	if s.step >= 0 {
+		start, end := int64(0), n
+		if s.hasStart {
+			start = normalize(s.start)
+		}
+		if s.hasEnd {
+			end = normalize(s.end)
+		}
+		return clamp(start, 0, n), clamp(end, 0, n)
+	}
+	start, end := n-1, -n-1
+	if s.hasStart {
+		start = normalize(s.start)
+	}
+	if s.hasEnd {
+		end = normalize(s.end)
+	}
+	return clamp(end, -1, n-1), clamp(start, -1, n-1)
+}
+
+// nodeValue decodes the value of n. Arrays and objects are compared in place and not decoded.
+func (e *evaluator) nodeValue(n node) value {
+	switch e.buf[n.start] {
+	case '{':
+		return value{kind: objectKind, node: n}
+	case '[':
+		return value{kind: arrayKind, node: n}
+	case 't':
+		return value{kind: boolKind, b: true}
+	case 'f':
+		return value{kind: boolKind}
+	case 'n':
+		return value{kind: nullKind}
+	case '"':
+		s := &decoder.Scanner{Buf: e.buf, Cursor: n.start}
+		str, err := s.String()
+		if err != nil {
+			e.fail(err)
+			return value{}
+		}
+		return value{kind: stringKind, str: string(str)}
+	}
+	s := &decoder.Scanner{Buf: e.buf, Cursor: n.start}
+	literal, err := s.Number()
+	if err != nil {
+		e.fail(err)
+		return value{}
+	}
+	num, _ := strconv.ParseFloat(string(literal), 64) // out of range numbers are rounded to infinity
+	return value{kind: numberKind, num: num}
+}
+
+// regexp returns the regular expression compiled from the I-Regexp pattern,
+// or nil if pattern is invalid.
+func (e *evaluator) regexp(pattern string, full bool) *regexp.Regexp {
+	key := pattern
+	if full {
+		key = "\x00" + pattern
+	}
+	if re, exists := e.regexps[key]; exists {
+		return re
+	}
+	if e.regexps == nil {
+		e.regexps = map[string]*regexp.Regexp{}
+	}
+	re := compileRegexp(pattern, full)
+	e.regexps[key] = re
+	return re
+}
@@ -0,0 +1,328 @@
+package jsonpath
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+type valueKind int
+
+const (
+	nothing valueKind = iota // the absence of a value, e.g. the result of a query that selects no node
+	nullKind
+	boolKind
+	numberKind
+	stringKind
+	arrayKind
+	objectKind
+)
+
+// value is the value of a comparison operand. Arrays and objects refer to their node in the document.
+type value struct {
+	kind valueKind
+	b    bool
+	num  float64
+	str  string
+	node node
+}
+
+type logicalExpr interface {
+	test(e *evaluator, current node) bool
+}
+
+type valueExpr interface {
+	value(e *evaluator, current node) value
+}
+
+type orExpr []logicalExpr
+
+func (x orExpr) test(e *evaluator, current node) bool {
+	for _, operand := range x {
+		if operand.test(e, current) {
+			return true
+		}
+	}
+	return false
+}
+
+type andExpr []logicalExpr
+
+func (x andExpr) test(e *evaluator, current node) bool {
+	for _, operand := range x {
+		if !operand.test(e, current) {
+			return false
+		}
+	}
+	return true
+}
+
+type notExpr struct {
+	expr logicalExpr
+}
+
+func (x *notExpr) test(e *evaluator, current node) bool {
+	return !x.expr.test(e, current)
+}
+
+// existExpr tests whether a query selects any node.
+type existExpr struct {
+	query *query
+}
+
+func (x *existExpr) test(e *evaluator, current node) bool {
+	return len(e.query(x.query, current)) > 0
+}
+
+type compareExpr struct {
+	op          string
+	left, right valueExpr
+}
+
+func (x *compareExpr) test(e *evaluator, current node) bool {
+	left, right := x.left.value(e, current), x.right.value(e, current)
+	switch x.op {
+	case "==":
+		return e.equal(left, right)
+	case "!=":
+		return !e.equal(left, right)
+	case "<":
+		return less(left, right)
+	case "<=":
+		return less(left, right) || e.equal(left, right)
+	case ">":
+		return less(right, left)
+	}
+	return less(right, left) || e.equal(left, right)
+}
+
+type literal value
+
+func (x *literal) value(*evaluator, node) value {
+	return value(*x)
+}
+
+type singularQuery struct {
+	query *query
+}
+
+func (x *singularQuery) value(e *evaluator, current node) value {
+	nodes := e.query(x.query, current)
+	if len(nodes) != 1 {
+		return value{}
+	}
+	return e.nodeValue(nodes[0])
+}
+
+// equal compares a and b as defined by RFC 9535: numbers by their value and arrays and objects deeply.
+func (e *evaluator) equal(a, b value) bool {
+	if a.kind != b.kind {
+		return false
+	}
+	switch a.kind {
+	case boolKind:
+		return a.b == b.b
+	case numberKind:
+		return a.num == b.num
+	case stringKind:
+		return a.str == b.str
+	case arrayKind:
+		x, y := e.children(a.node), e.children(b.node)
+		if len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !e.equal(e.nodeValue(e.child(a.node, x, i)), e.nodeValue(e.child(b.node, y, i))) {
+				return false
+			}
+		}
+		return true
+	case objectKind:
+		x, y := e.members(a.node), e.members(b.node)
+		if len(x) != len(y) {
+			return false
+		}
+		for key, xv := range x {
+			yv, exists := y[key]
+			if !exists || !e.equal(e.nodeValue(xv), e.nodeValue(yv)) {
+				return false
+			}
+		}
+		return true
+	}
+	return true
+}
+
+// members returns the member values of the object n by key. The last of duplicate keys wins.
+func (e *evaluator) members(n node) map[string]node {
+	children := e.children(n)
+	members := make(map[string]node, len(children))
+	for i := range children {
+		members[string(children[i].key)] = node{start: children[i].start, end: children[i].end, depth: n.depth + 1}
+	}
+	return members
+}
+
+// less reports whether a is less than b. Only numbers and strings are ordered.
+func less(a, b value) bool {
+	switch {
+	case a.kind == numberKind && b.kind == numberKind:
+		return a.num < b.num
+	case a.kind == stringKind && b.kind == stringKind:
+		return a.str < b.str
+	}
+	return false
+}
+
+// exprType is the type of a function parameter or result.
+type exprType int
+
+const (
+	valueType exprType = iota
+	logicalType
+	nodesType
+)
+
+// funcResult is a function argument or result of the type of the parameter or function.
+type funcResult struct {
+	value   value
+	logical bool
+	nodes   []node
+}
+
+type function struct {
+	params []exprType
+	result exprType
+	call   func(e *evaluator, args []funcResult) funcResult
+}
+
+var functions = map[string]*function{
+	"length": {params: []exprType{valueType}, result: valueType, call: lengthFunc},
+	"count":  {params: []exprType{nodesType}, result: valueType, call: countFunc},
+	"match":  {params: []exprType{valueType, valueType}, result: logicalType, call: matchFunc},
+	"search": {params: []exprType{valueType, valueType}, result: logicalType, call: searchFunc},
+	"value":  {params: []exprType{nodesType}, result: valueType, call: valueFunc},
+}
+
+type funcArg struct {
+	typ     exprType
+	value   valueExpr
+	logical logicalExpr
+	nodes   *query
+}
+
+type funcCall struct {
+	name string
+	fn   *function
+	args []*funcArg
+}
+
+func (x *funcCall) eval(e *evaluator, current node) funcResult {
+	args := make([]funcResult, len(x.args))
+	for i, arg := range x.args {
+		switch arg.typ {
+		case valueType:
+			args[i].value = arg.value.value(e, current)
+		case logicalType:
+			args[i].logical = arg.logical.test(e, current)
+		case nodesType:
+			args[i].nodes = e.query(arg.nodes, current)
+		}
+	}
+	return x.fn.call(e, args)
+}
+
+func (x *funcCall) value(e *evaluator, current node) value {
+	return x.eval(e, current).value
+}
+
+func (x *funcCall) test(e *evaluator, current node) bool {
+	result := x.eval(e, current)
+	if x.fn.result == nodesType {
+		return len(result.nodes) > 0
+	}
+	return result.logical
+}
+
+func numberValue(n int) funcResult {
+	return funcResult{value: value{kind: numberKind, num: float64(n)}}
+}
+
+// lengthFunc returns the number of characters of a string, elements of an array or members of an object.
+func lengthFunc(e *evaluator, args []funcResult) funcResult {
+	switch v := args[0].value; v.kind {
+	case stringKind:
+		return numberValue(utf8.RuneCountInString(v.str))
+	case arrayKind:
+		return numberValue(len(e.children(v.node)))
+	case objectKind:
+		return numberValue(len(e.members(v.node)))
+	}
+	return funcResult{}
+}
+
+func countFunc(_ *evaluator, args []funcResult) funcResult {
+	return numberValue(len(args[0].nodes))
+}
+
+func matchFunc(e *evaluator, args []funcResult) funcResult {
+	return funcResult{logical: regexpMatch(e, args, true)}
+}
+
+func searchFunc(e *evaluator, args []funcResult) funcResult {
+	return funcResult{logical: regexpMatch(e, args, false)}
+}
+
+func regexpMatch(e *evaluator, args []funcResult, full bool) bool {
+	s, pattern := args[0].value, args[1].value
+	if s.kind != stringKind || pattern.kind != stringKind {
+		return false
+	}
+	re := e.regexp(pattern.str, full)
+	return re != nil && re.MatchString(s.str)
+}
+
+func valueFunc(e *evaluator, args []funcResult) funcResult {
+	if len(args[0].nodes) != 1 {
+		return funcResult{}
+	}
+	return funcResult{value: e.nodeValue(args[0].nodes[0])}
+}
+
+// compileRegexp compiles the I-Regexp (RFC 9485) pattern, which is matched against the whole string if full is true.
+// The dot of I-Regexp matches any character except line breaks, which is translated for the regexp package.
+func compileRegexp(pattern string, full bool) *regexp.Regexp {
+	var b strings.Builder
+	if full {
+		b.WriteString(`\A(?:`)
+	}
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '\\':
+			b.WriteByte(c)
+			if i+1 < len(pattern) {
+				i++
+				b.WriteByte(pattern[i])
+			}
+		case c == '[' && !inClass:
+			inClass = true
+			b.WriteByte(c)
+		case c == ']' && inClass:
+			inClass = false
+			b.WriteByte(c)
+		case c == '.' && !inClass:
+			b.WriteString(`[^\n\r]`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if full {
+		b.WriteString(`)\z`)
+	}
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil
+	}
+	return re
+}
@@ -0,0 +1,746 @@
+// Package jsonpath implements JSONPath queries (RFC 9535) for json.Query.
+// Queries are evaluated over the raw bytes of a document: values are located with
+// the decoder's scanner and skip functions and only decoded when a filter compares them.
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// maxInt is the largest index allowed in a query, the maximum exactly representable integer of I-JSON.
+const maxInt = 1<<53 - 1
+
+type segment struct {
+	descendant bool
+	selectors  []*selector
+}
+
+type selectorKind int
+
+const (
+	nameSelector selectorKind = iota
+	wildcardSelector
+	indexSelector
+	sliceSelector
+	filterSelector
+)
+
+type selector struct {
+	kind   selectorKind
+	name   string
+	index  int64
+	slice  slice
+	filter logicalExpr
+}
+
+type slice struct {
+	start, end, step int64
+	hasStart, hasEnd bool
+}
+
+// query is the root query or a query used in a filter.
+type query struct {
+	relative bool
+	segments []*segment
+}
+
+// singular reports whether q selects at most one node: it only has child segments with one name or index selector.
+func (q *query) singular() bool {
+	for _, seg := range q.segments {
+		if seg.descendant || len(seg.selectors) != 1 {
+			return false
+		}
+		if kind := seg.selectors[0].kind; kind != nameSelector && kind != indexSelector {
+			return false
+		}
+	}
+	return true
+}
+
+// expr is a filter expression before it is checked against the type expected where it is used:
+// a *literal, a *query, a *funcCall or a logicalExpr.
+type expr interface{}
+
+// Query is a parsed JSONPath query.
+type Query struct {
+	query *query
+}
+
+// Parse parses the JSONPath query s.
+func Parse(s string) (*Query, error) {
+	p := &parser{query: s}
+	if !utf8.ValidString(s) {
+		return nil, p.errorf("invalid UTF-8")
+	}
+	if p.peek() != '$' {
+		return nil, p.errorf("query must start with '$'")
+	}
+	p.pos++
+	segments, err := p.segments()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(s) {
+		return nil, p.errorf("unexpected character %q", p.query[p.pos])
+	}
+	return &Query{query: &query{segments: segments}}, nil
+}
+
+type parser struct {
+	query string
+	pos   int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("json: invalid JSONPath %q at offset %d: %s", p.query, p.pos, fmt.Sprintf(format, args...))
+}
+
+// peek returns the byte at the current position, or 0 at the end of the query.
+func (p *parser) peek() byte {
+	if p.pos >= len(p.query) {
+		return 0
+	}
+	return p.query[p.pos]
+}
+
+func (p *parser) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(p.query[p.pos:], prefix)
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.query) {
+		switch p.query[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+			continue
+		}
+		return
+	}
+}
+
+func (p *parser) expect(c byte) error {
+	if p.peek() != c {
+		if p.pos >= len(p.query) {
+			return p.errorf("expected %q but reached the end of the query", c)
+		}
+		return p.errorf("expected %q but found %q", c, p.query[p.pos])
+	}
+	p.pos++
+	return nil
+}
+
+func (p *parser) segments() ([]*segment, error) {
+	var segments []*segment
+	for {
+		start := p.pos
+		p.skipSpace()
+		var (
+			seg *segment
+			err error
+		)
+		switch {
+		case p.hasPrefix(".."):
+			p.pos += 2
+			if p.peek() == '[' {
+				seg, err = p.bracketedSelection()
+			} else {
+				seg, err = p.shorthand()
+			}
+			if seg != nil {
+				seg.descendant = true
+			}
+		case p.peek() == '.':
+			p.pos++
+			seg, err = p.shorthand()
+		case p.peek() == '[':
+			seg, err = p.bracketedSelection()
+		default:
+			p.pos = start
+			return segments, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		segments = append(segments, seg)
+	}
+}
+
+// shorthand parses the wildcard or member name following a dot.
+func (p *parser) shorthand() (*segment, error) {
+	if p.peek() == '*' {
+		p.pos++
+		return &segment{selectors: []*selector{{kind: wildcardSelector}}}, nil
+	}
+	start := p.pos
+	for p.pos < len(p.query) {
+		r, size := utf8.DecodeRuneInString(p.query[p.pos:])
+		if !isNameFirst(r) && (p.pos == start || r < '0' || r > '9') {
+			break
+		}
+		p.pos += size
+	}
+	if p.pos == start {
+		return nil, p.errorf("expected member name or '*'")
+	}
+	return &segment{selectors: []*selector{{kind: nameSelector, name: p.query[start:p.pos]}}}, nil
+}
+
+func isNameFirst(r rune) bool {
+	return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || r == '_' || r >= 0x80
+}
+
+func (p *parser) bracketedSelection() (*segment, error) {
+	p.pos++
+	seg := &segment{}
+	for {
+		p.skipSpace()
+		sel, err := p.selector()
+		if err != nil {
+			return nil, err
+		}
+		seg.selectors = append(seg.selectors, sel)
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return seg, nil
+		default:
+			return nil, p.expect(']')
+		}
+	}
+}
+
+func (p *parser) selector() (*selector, error) {
+	switch c := p.peek(); {
+	case c == '\'' || c == '"':
+		name, err := p.stringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return &selector{kind: nameSelector, name: name}, nil
+	case c == '*':
+		p.pos++
+		return &selector{kind: wildcardSelector}, nil
+	case c == '?':
+		p.pos++
+		p.skipSpace()
+		e, err := p.logicalOr()
+		if err != nil {
+			return nil, err
+		}
+		filter, err := p.toLogical(e)
+		if err != nil {
+			return nil, err
+		}
+		return &selector{kind: filterSelector, filter: filter}, nil
+	case c == '-' || c == ':' || isDigit(c):
+		return p.indexOrSlice()
+	}
+	return nil, p.errorf("expected selector")
+}
+
+func (p *parser) indexOrSlice() (*selector, error) {
+	var s slice
+	if p.peek() != ':' {
+		start, err := p.integer()
+		if err != nil {
+			return nil, err
+		}
+		end := p.pos
+		p.skipSpace()
+		if p.peek() != ':' {
+			p.pos = end
+			return &selector{kind: indexSelector, index: start}, nil
+		}
+		s.start, s.hasStart = start, true
+	}
+	p.pos++
+	p.skipSpace()
+	if c := p.peek(); c == '-' || isDigit(c) {
+		end, err := p.integer()
+		if err != nil {
+			return nil, err
+		}
+		s.end, s.hasEnd = end, true
+		p.skipSpace()
+	}
+	s.step = 1
+	if p.peek() == ':' {
+		p.pos++
+		p.skipSpace()
+		if c := p.peek(); c == '-' || isDigit(c) {
+			step, err := p.integer()
+			if err != nil {
+				return nil, err
+			}
+			s.step = step
+		}
+	}
+	return &selector{kind: sliceSelector, slice: s}, nil
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// integer parses an index, which has no leading zeros and is in the range of I-JSON integers.
+func (p *parser) integer() (int64, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	switch c := p.peek(); {
+	case c == '0':
+		p.pos++
+		if p.pos-start > 1 {
+			return 0, p.errorf("invalid integer -0")
+		}
+		if isDigit(p.peek()) {
+			return 0, p.errorf("integer with leading zero")
+		}
+	case isDigit(c):
+		for isDigit(p.peek()) {
+			p.pos++
+		}
+	default:
+		return 0, p.errorf("expected integer")
+	}
+	n, err := strconv.ParseInt(p.query[start:p.pos], 10, 64)
+	if err != nil || n > maxInt || n < -maxInt {
+		return 0, p.errorf("integer %s out of range", p.query[start:p.pos])
+	}
+	return n, nil
+}
+
+// number parses a number literal of a filter expression.
+func (p *parser) number() (float64, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	switch c := p.peek(); {
+	case c == '0':
+		p.pos++
+	case isDigit(c):
+		for isDigit(p.peek()) {
+			p.pos++
+		}
+	default:
+		return 0, p.errorf("expected number")
+	}
+	if p.peek() == '.' {
+		p.pos++
+		if !isDigit(p.peek()) {
+			return 0, p.errorf("expected digit after decimal point")
+		}
+		for isDigit(p.peek()) {
+			p.pos++
+		}
+	}
+	if c := p.peek(); c == 'e' || c == 'E' {
+		p.pos++
+		if c := p.peek(); c == '+' || c == '-' {
+			p.pos++
+		}
+		if !isDigit(p.peek()) {
+			return 0, p.errorf("expected digit in exponent")
+		}
+		for isDigit(p.peek()) {
+			p.pos++
+		}
+	}
+	n, err := strconv.ParseFloat(p.query[start:p.pos], 64)
+	if err != nil && err.(*strconv.NumError).Err != strconv.ErrRange {
+		return 0, p.errorf("invalid number %s", p.query[start:p.pos])
+	}
+	return n, nil
+}
+
+// stringLiteral parses a single or double quoted string literal.
+func (p *parser) stringLiteral() (string, error) {
+	quote := p.query[p.pos]
+	p.pos++
+	var b strings.Builder
+	for {
+		if p.pos >= len(p.query) {
+			return "", p.errorf("unterminated string literal")
+		}
+		switch c := p.query[p.pos]; {
+		case c == quote:
+			p.pos++
+			return b.String(), nil
+		case c == '\\':
+			p.pos++
+			if err := p.escape(&b, quote); err != nil {
+				return "", err
+			}
+		case c < 0x20:
+			return "", p.errorf("invalid character %q in string literal", c)
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+func (p *parser) escape(b *strings.Builder, quote byte) error {
+	c := p.peek()
+	p.pos++
+	switch c {
+	case 'b':
+		b.WriteByte('\b')
+	case 'f':
+		b.WriteByte('\f')
+	case 'n':
+		b.WriteByte('\n')
+	case 'r':
+		b.WriteByte('\r')
+	case 't':
+		b.WriteByte('\t')
+	case '/', '\\', quote:
+		b.WriteByte(c)
+	case 'u':
+		r, err := p.hex4()
+		if err != nil {
+			return err
+		}
+		switch {
+		case utf16.IsSurrogate(r) && r < 0xdc00:
+			if !p.hasPrefix(`\u`) {
+				return p.errorf("missing low surrogate")
+			}
+			p.pos += 2
+			low, err := p.hex4()
+			if err != nil {
+				return err
+			}
+			if r = utf16.DecodeRune(r, low); r == utf8.RuneError {
+				return p.errorf("invalid low surrogate")
+			}
+		case utf16.IsSurrogate(r):
+			return p.errorf("unexpected low surrogate")
+		}
+		b.WriteRune(r)
+	default:
+		p.pos--
+		return p.errorf("invalid escape sequence")
+	}
+	return nil
+}
+
+func (p *parser) hex4() (rune, error) {
+	if p.pos+4 > len(p.query) {
+		return 0, p.errorf("invalid unicode escape sequence")
+	}
+	n, err := strconv.ParseUint(p.query[p.pos:p.pos+4], 16, 32)
+	if err != nil {
+		return 0, p.errorf("invalid unicode escape sequence")
+	}
+	p.pos += 4
+	return rune(n), nil
+}
+
+func (p *parser) logicalOr() (expr, error) {
+	e, err := p.logicalAnd()
+	if err != nil {
+		return nil, err
+	}
+	var or orExpr
+	for {
+		end := p.pos
+		p.skipSpace()
+		if !p.hasPrefix("||") {
+			p.pos = end
+			break
+		}
+		if or == nil {
+			left, err := p.toLogical(e)
+			if err != nil {
+				return nil, err
+			}
+			or = orExpr{left}
+		}
+		p.pos += 2
+		p.skipSpace()
+		right, err := p.logicalAnd()
+		if err != nil {
+			return nil, err
+		}
+		operand, err := p.toLogical(right)
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, operand)
+	}
+	if or == nil {
+		return e, nil
+	}
+	return or, nil
+}
+
+func (p *parser) logicalAnd() (expr, error) {
+	e, err := p.basic()
+	if err != nil {
+		return nil, err
+	}
+	var and andExpr
+	for {
+		end := p.pos
+		p.skipSpace()
+		if !p.hasPrefix("&&") {
+			p.pos = end
+			break
+		}
+		if and == nil {
+			left, err := p.toLogical(e)
+			if err != nil {
+				return nil, err
+			}
+			and = andExpr{left}
+		}
+		p.pos += 2
+		p.skipSpace()
+		right, err := p.basic()
+		if err != nil {
+			return nil, err
+		}
+		operand, err := p.toLogical(right)
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, operand)
+	}
+	if and == nil {
+		return e, nil
+	}
+	return and, nil
+}
+
+var comparisonOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// basic parses a parenthesized expression, a negation, a comparison or
+// the operand of a test expression, which is returned as is.
+func (p *parser) basic() (expr, error) {
+	switch p.peek() {
+	case '!':
+		p.pos++
+		p.skipSpace()
+		var (
+			e   expr
+			err error
+		)
+		if p.peek() == '(' {
+			e, err = p.paren()
+		} else {
+			e, err = p.primary()
+		}
+		if err != nil {
+			return nil, err
+		}
+		operand, err := p.toLogical(e)
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr: operand}, nil
+	case '(':
+		return p.paren()
+	}
+	left, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	end := p.pos
+	p.skipSpace()
+	var op string
+	for _, candidate := range comparisonOps {
+		if p.hasPrefix(candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		p.pos = end
+		return left, nil
+	}
+	p.pos += len(op)
+	p.skipSpace()
+	right, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	l, err := p.comparable(left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := p.comparable(right)
+	if err != nil {
+		return nil, err
+	}
+	return &compareExpr{op: op, left: l, right: r}, nil
+}
+
+func (p *parser) paren() (logicalExpr, error) {
+	p.pos++
+	p.skipSpace()
+	e, err := p.logicalOr()
+	if err != nil {
+		return nil, err
+	}
+	l, err := p.toLogical(e)
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if err := p.expect(')'); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// primary parses a query, a literal or a function call.
+func (p *parser) primary() (expr, error) {
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		segments, err := p.segments()
+		if err != nil {
+			return nil, err
+		}
+		return &query{relative: c == '@', segments: segments}, nil
+	case c == '\'' || c == '"':
+		s, err := p.stringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		return &literal{kind: stringKind, str: s}, nil
+	case c == '-' || isDigit(c):
+		n, err := p.number()
+		if err != nil {
+			return nil, err
+		}
+		return &literal{kind: numberKind, num: n}, nil
+	case 'a' <= c && c <= 'z':
+		start := p.pos
+		for c := p.peek(); 'a' <= c && c <= 'z' || c == '_' || isDigit(c); c = p.peek() {
+			p.pos++
+		}
+		name := p.query[start:p.pos]
+		if p.peek() == '(' {
+			return p.funcCall(name)
+		}
+		switch name {
+		case "true":
+			return &literal{kind: boolKind, b: true}, nil
+		case "false":
+			return &literal{kind: boolKind}, nil
+		case "null":
+			return &literal{kind: nullKind}, nil
+		}
+		p.pos = start
+		return nil, p.errorf("unknown literal %q", name)
+	}
+	return nil, p.errorf("expected query, literal or function call")
+}
+
+func (p *parser) funcCall(name string) (*funcCall, error) {
+	fn, exists := functions[name]
+	if !exists {
+		return nil, p.errorf("unknown function %s()", name)
+	}
+	p.pos++
+	p.skipSpace()
+	call := &funcCall{name: name, fn: fn}
+	if p.peek() == ')' {
+		p.pos++
+	} else {
+		for {
+			e, err := p.logicalOr()
+			if err != nil {
+				return nil, err
+			}
+			if len(call.args) == len(fn.params) {
+				return nil, p.errorf("too many arguments to %s()", name)
+			}
+			arg, err := p.argument(call, fn.params[len(call.args)], e)
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			p.skipSpace()
+			if p.peek() == ')' {
+				p.pos++
+				break
+			}
+			if err := p.expect(','); err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+		}
+	}
+	if len(call.args) != len(fn.params) {
+		return nil, p.errorf("%s() takes %d arguments", name, len(fn.params))
+	}
+	return call, nil
+}
+
+func (p *parser) argument(call *funcCall, typ exprType, e expr) (*funcArg, error) {
+	switch typ {
+	case valueType:
+		v, err := p.comparable(e)
+		if err != nil {
+			return nil, p.errorf("argument %d of %s() must be a value", len(call.args)+1, call.name)
+		}
+		return &funcArg{typ: typ, value: v}, nil
+	case nodesType:
+		if q, ok := e.(*query); ok {
+			return &funcArg{typ: typ, nodes: q}, nil
+		}
+		return nil, p.errorf("argument %d of %s() must be a query", len(call.args)+1, call.name)
+	}
+	l, err := p.toLogical(e)
+	if err != nil {
+		return nil, err
+	}
+	return &funcArg{typ: typ, logical: l}, nil
+}
+
+// toLogical converts e to a logical expression. A query tests whether it selects any node.
+func (p *parser) toLogical(e expr) (logicalExpr, error) {
+	switch e := e.(type) {
+	case *query:
+		return &existExpr{query: e}, nil
+	case *funcCall:
+		if e.fn.result == valueType {
+			return nil, p.errorf("result of %s() is not a logical value", e.name)
+		}
+		return e, nil
+	case logicalExpr:
+		return e, nil
+	}
+	return nil, p.errorf("literal is not a logical expression")
+}
+
+// comparable converts e to an operand of a comparison.
+func (p *parser) comparable(e expr) (valueExpr, error) {
+	switch e := e.(type) {
+	case *literal:
+		return e, nil
+	case *query:
+		if !e.singular() {
+			return nil, p.errorf("query in comparison must be singular")
+		}
+		return &singularQuery{query: e}, nil
+	case *funcCall:
+		if e.fn.result != valueType {
+			return nil, p.errorf("result of %s() is not comparable", e.name)
+		}
+		return e, nil
+	}
+	return nil, p.errorf("logical expression is not comparable")
+}
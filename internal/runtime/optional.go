@@ -0,0 +1,24 @@
+package runtime
+
+import (
+	"reflect"
+)
+
+// OptionalState tells whether an Optional value was set, set to null or not set at all.
+// It is the first field of the Optional type, which the encoder and decoder compilers
+// recognize by it.
+type OptionalState uint8
+
+const (
+	OptionalUnset OptionalState = iota
+	OptionalNull
+	OptionalSet
+)
+
+var optionalStateType = reflect.TypeOf(OptionalState(0))
+
+// IsOptional reports whether typ is an instance of the Optional type:
+// a struct with an OptionalState field followed by the value.
+func IsOptional(typ *Type) bool {
+	return typ.Kind() == reflect.Struct && typ.NumField() == 2 && typ.Field(0).Type == optionalStateType
+}
@@ -0,0 +1,18 @@
+package runtime
+
+import (
+	"reflect"
+)
+
+// OrderedMapKeys holds the keys of an OrderedMap in order.
+// It is the first field of the OrderedMap type, followed by the values in the same order
+// and the index of the keys, which the encoder and decoder compilers recognize it by.
+type OrderedMapKeys []string
+
+var orderedMapKeysType = reflect.TypeOf(OrderedMapKeys(nil))
+
+// IsOrderedMap reports whether typ is an instance of the OrderedMap type:
+// a struct with an OrderedMapKeys field followed by the slice of the values and the index of the keys.
+func IsOrderedMap(typ *Type) bool {
+	return typ.Kind() == reflect.Struct && typ.NumField() == 3 && typ.Field(0).Type == orderedMapKeysType
+}
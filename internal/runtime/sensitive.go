@@ -0,0 +1,33 @@
+package runtime
+
+import (
+	"reflect"
+	"sync"
+)
+
+var (
+	sensitiveMu    sync.RWMutex
+	sensitiveTypes = map[*Type]struct{}{}
+)
+
+// RegisterSensitive registers typ as a type whose values are replaced by a mask when redacting.
+func RegisterSensitive(typ *Type) {
+	sensitiveMu.Lock()
+	defer sensitiveMu.Unlock()
+	sensitiveTypes[typ] = struct{}{}
+}
+
+// IsSensitive reports whether typ, or the type it points to, is registered as sensitive.
+func IsSensitive(typ *Type) bool {
+	sensitiveMu.RLock()
+	defer sensitiveMu.RUnlock()
+	for {
+		if _, exists := sensitiveTypes[typ]; exists {
+			return true
+		}
+		if typ.Kind() != reflect.Ptr {
+			return false
+		}
+		typ = typ.Elem()
+	}
+}
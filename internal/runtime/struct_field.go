@@ -1,16 +1,44 @@
 package runtime
 
 import (
+	"fmt"
 	"reflect"
 	"strings"
+	"sync"
 	"unicode"
 )
 
-func getTag(field reflect.StructField) string {
+var (
+	structTagsMu sync.RWMutex
+	structTags   = map[*Type]map[string]string{}
+)
+
+// RegisterStructTags overrides the tags of the fields of the struct type typ.
+// tags maps field names to the tags used in place of theirs, whatever the tag key.
+func RegisterStructTags(typ *Type, tags map[string]string) {
+	overrides := make(map[string]string, len(tags))
+	for name, tag := range tags {
+		overrides[name] = tag
+	}
+	structTagsMu.Lock()
+	defer structTagsMu.Unlock()
+	structTags[typ] = overrides
+}
+
+// getTag returns the tag of field, read with tagKey if the field has it and with json otherwise.
+func getTag(field reflect.StructField, tagKey string, overrides map[string]string) string {
+	if tag, exists := overrides[field.Name]; exists {
+		return tag
+	}
+	if tagKey != "" {
+		if tag, exists := field.Tag.Lookup(tagKey); exists {
+			return tag
+		}
+	}
 	return field.Tag.Get("json")
 }
 
-func IsIgnoredStructField(field reflect.StructField) bool {
+func isIgnoredStructField(field reflect.StructField, tag string) bool {
 	if field.PkgPath != "" {
 		if field.Anonymous {
 			t := field.Type
@@ -25,16 +53,63 @@ func IsIgnoredStructField(field reflect.StructField) bool {
 			return true
 		}
 	}
-	tag := getTag(field)
 	return tag == "-"
 }
 
+// StructFieldTags returns the tags of the fields of the struct type typ that are not ignored.
+// Tags registered with RegisterStructTags take precedence over those of the fields.
+func StructFieldTags(typ *Type, tagKey string) StructTags {
+	structTagsMu.RLock()
+	overrides := structTags[typ]
+	structTagsMu.RUnlock()
+	tags := StructTags{}
+	fieldNum := typ.NumField()
+	for i := 0; i < fieldNum; i++ {
+		field := typ.Field(i)
+		tag := getTag(field, tagKey, overrides)
+		if isIgnoredStructField(field, tag) {
+			continue
+		}
+		tags = append(tags, structTagFromField(field, tag))
+	}
+	return tags
+}
+
 type StructTag struct {
-	Key         string
-	IsTaggedKey bool
-	IsOmitEmpty bool
-	IsString    bool
-	Field       reflect.StructField
+	Key          string
+	IsTaggedKey  bool
+	IsOmitEmpty  bool
+	IsString     bool
+	IsNilAsEmpty bool
+	IsNullable   bool
+	IsSensitive  bool
+	BytesFormat  BytesFormat
+	Aliases      []string // alternative keys accepted by the decoder
+	Field        reflect.StructField
+	Err          error // invalid option of the tag, reported when the struct is compiled
+}
+
+// BytesFormat is the representation of []byte values in JSON.
+type BytesFormat uint8
+
+const (
+	// BytesFormatDefault is the standard base64 encoding with padding,
+	// unless an encode or decode option selects another format.
+	BytesFormatDefault BytesFormat = iota
+	BytesFormatBase64
+	BytesFormatBase64URL
+	BytesFormatBase64Raw
+	BytesFormatHex
+	BytesFormatArray
+)
+
+// bytesFormats maps the names used by the format option of struct tags to their formats.
+var bytesFormats = map[string]BytesFormat{
+	"base64":    BytesFormatBase64,
+	"base64url": BytesFormatBase64URL,
+	"base64raw": BytesFormatBase64Raw,
+	"hex":       BytesFormatHex,
+	"array":     BytesFormatArray,
 }
 
 type StructTags []*StructTag
@@ -65,9 +140,8 @@ func isValidTag(s string) bool {
 	return true
 }
 
-func StructTagFromField(field reflect.StructField) *StructTag {
+func structTagFromField(field reflect.StructField, tag string) *StructTag {
 	keyName := field.Name
-	tag := getTag(field)
 	st := &StructTag{Field: field}
 	opts := strings.Split(tag, ",")
 	if len(opts) > 0 {
@@ -84,6 +158,27 @@ func StructTagFromField(field reflect.StructField) *StructTag {
 				st.IsOmitEmpty = true
 			case "string":
 				st.IsString = true
+			case "nilasempty":
+				st.IsNilAsEmpty = true
+			case "nullable":
+				st.IsNullable = true
+			case "sensitive":
+				st.IsSensitive = true
+			default:
+				if strings.HasPrefix(opt, "format:") {
+					name := strings.TrimPrefix(opt, "format:")
+					format, exists := bytesFormats[name]
+					if !exists {
+						st.Err = fmt.Errorf("json: unknown format %q in struct tag of field %s", name, field.Name)
+					}
+					st.BytesFormat = format
+				} else if strings.HasPrefix(opt, "alias=") {
+					for _, alias := range strings.Split(strings.TrimPrefix(opt, "alias="), "|") {
+						if isValidTag(alias) {
+							st.Aliases = append(st.Aliases, alias)
+						}
+					}
+				}
 			}
 		}
 	}
@@ -0,0 +1,54 @@
+package runtime
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Union describes an interface type whose JSON objects select their concrete type
+// with a discriminator property.
+type Union struct {
+	Key   string           // name of the discriminator property
+	Types map[string]*Type // concrete type for each discriminator value
+}
+
+// UnionMember describes the discriminator of a struct type registered in a Union.
+type UnionMember struct {
+	Key  string // name of the discriminator property
+	Name string // discriminator value of the type
+}
+
+var (
+	unionMu      sync.RWMutex
+	unions       = map[*Type]*Union{}
+	unionMembers = map[*Type]UnionMember{}
+)
+
+// RegisterUnion registers union for the interface type iface.
+// Pointer types in union.Types are registered as members by their element type.
+func RegisterUnion(iface *Type, union *Union) {
+	unionMu.Lock()
+	defer unionMu.Unlock()
+	unions[iface] = union
+	for name, typ := range union.Types {
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		unionMembers[typ] = UnionMember{Key: union.Key, Name: name}
+	}
+}
+
+// UnionOf returns the union registered for the interface type typ, or nil.
+func UnionOf(typ *Type) *Union {
+	unionMu.RLock()
+	defer unionMu.RUnlock()
+	return unions[typ]
+}
+
+// UnionMemberOf returns the discriminator of the struct type typ if it is registered in a union.
+func UnionMemberOf(typ *Type) (UnionMember, bool) {
+	unionMu.RLock()
+	defer unionMu.RUnlock()
+	member, ok := unionMembers[typ]
+	return member, ok
+}
@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 
+	"github.com/goccy/go-json/internal/decoder"
 	"github.com/goccy/go-json/internal/encoder"
+	"github.com/goccy/go-json/internal/errors"
 )
 
 // Marshaler is the interface implemented by types that
@@ -224,6 +227,10 @@ func MarshalIndentWithOption(v interface{}, prefix, indent string, optFuncs ...E
 // preferring an exact match but also accepting a case-insensitive match. By
 // default, object keys which don't have a corresponding struct field are
 // ignored (see Decoder.DisallowUnknownFields for an alternative).
+// The alias option of a field tag lists other keys accepted for the field,
+// separated by "|", e.g. `json:"newName,alias=oldName|legacy_name"` for a renamed field.
+// Aliases are matched like the key of the field, but the keys of other fields
+// take precedence, and Marshal only uses the key.
 //
 // To unmarshal JSON into an interface value,
 // Unmarshal stores one of these in the interface value:
@@ -280,7 +287,7 @@ func Unmarshal(data []byte, v interface{}) error {
 // in the value pointed to by v. If you implement the UnmarshalerContext interface,
 // call it with ctx as an argument.
 func UnmarshalContext(ctx context.Context, data []byte, v interface{}, optFuncs ...DecodeOptionFunc) error {
-	return unmarshalContext(ctx, data, v)
+	return unmarshalContext(ctx, data, v, optFuncs...)
 }
 
 func UnmarshalWithOption(data []byte, v interface{}, optFuncs ...DecodeOptionFunc) error {
@@ -334,6 +341,22 @@ func Indent(dst *bytes.Buffer, src []byte, prefix, indent string) error {
 	return encoder.Indent(dst, src, prefix, indent)
 }
 
+// CompactStream writes to w the JSON values read from r with insignificant space characters elided,
+// each followed by a newline. Unlike Compact, it reads r and writes w in chunks,
+// so memory use does not depend on the size of the input.
+// The output written before an error is found in the input is not reverted.
+func CompactStream(w io.Writer, r io.Reader) error {
+	return decoder.FormatStream(w, r, "", "", false)
+}
+
+// IndentStream writes to w an indented form of the JSON values read from r, each followed by a newline.
+// Values are indented like Indent does, but r is read and w written in chunks,
+// so memory use does not depend on the size of the input.
+// The output written before an error is found in the input is not reverted.
+func IndentStream(w io.Writer, r io.Reader, prefix, indent string) error {
+	return decoder.FormatStream(w, r, prefix, indent, true)
+}
+
 // HTMLEscape appends to dst the JSON-encoded src with <, >, &, U+2028 and U+2029
 // characters inside string literals changed to \u003c, \u003e, \u0026, \u2028, \u2029
 // so that the JSON will be safe to embed inside HTML <script> tags.
@@ -352,17 +375,40 @@ func HTMLEscape(dst *bytes.Buffer, src []byte) {
 }
 
 // Valid reports whether data is a valid JSON encoding.
+// Use Validate to find out why data is not valid.
 func Valid(data []byte) bool {
-	var v interface{}
-	decoder := NewDecoder(bytes.NewReader(data))
-	err := decoder.Decode(&v)
-	if err != nil {
-		return false
+	return Validate(data) == nil
+}
+
+// Validate checks that data is a valid JSON encoding like Valid, but reports why it is not.
+// The returned error is a *SyntaxError with the offset, line and column of the problem.
+// Unlike Valid, it does not decode data, so it does not allocate for the values of the input.
+//
+// Of the decode options, only DecodeRejectDuplicateKeys affects validation:
+// with it, an object that repeats a key is reported with a *DuplicateKeyError.
+func Validate(data []byte, optFuncs ...DecodeOptionFunc) error {
+	buf := make([]byte, len(data)+1) // append nul byte to the end
+	copy(buf, data)
+	if err := validateDocument(buf, 0, rejectDuplicateKeys(optFuncs)); err != nil {
+		return errors.Locate(err, data, 0, 1, 0)
 	}
-	if !decoder.More() {
-		return true
+	return nil
+}
+
+// ValidReader checks that r holds a valid JSON encoding, a single value optionally surrounded by space characters.
+// It reports errors like Validate, or the error returned by r, and accepts the same options. The input is read in chunks
+// and never buffered as a whole, so memory use does not depend on the size of the input.
+func ValidReader(r io.Reader, optFuncs ...DecodeOptionFunc) error {
+	return decoder.ValidateStream(r, rejectDuplicateKeys(optFuncs))
+}
+
+// rejectDuplicateKeys reports whether optFuncs include DecodeRejectDuplicateKeys.
+func rejectDuplicateKeys(optFuncs []DecodeOptionFunc) bool {
+	var opt DecodeOption
+	for _, optFunc := range optFuncs {
+		optFunc(&opt)
 	}
-	return decoder.InputOffset() >= int64(len(data))
+	return (opt.Flags & decoder.RejectDuplicateKeysOption) != 0
 }
 
 func init() {
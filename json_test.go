@@ -3,10 +3,14 @@ package json_test
 import (
 	"bytes"
 	stdjson "encoding/json"
+	"errors"
+	"io"
 	"math"
 	"math/rand"
 	"reflect"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/goccy/go-json"
 )
@@ -22,6 +26,12 @@ var validTests = []struct {
 	{`{"foo":"bar"}`, true},
 	{`{"foo":"bar","bar":{"baz":["qux"]}}`, true},
 	{`[""],`, false},
+	{`[{}]]`, false},
+	{`01`, false},
+	{`nul`, false},
+	{"\"a\tb\"", false},
+	{`"\x"`, false},
+	{" [1.5e-3, \"\\u00e9\xff\"] ", true},
 }
 
 func TestValid(t *testing.T) {
@@ -41,6 +51,101 @@ func TestValidWithComplexData(t *testing.T) {
 	}
 }
 
+func TestValidate(t *testing.T) {
+	for _, tt := range validTests {
+		if err := json.Validate([]byte(tt.data)); (err == nil) != tt.ok {
+			t.Errorf("Validate(%#q) = %v, want ok %v", tt.data, err, tt.ok)
+		}
+	}
+	err := json.Validate([]byte("{\"a\": [1,\n  2 3]}"))
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("expected SyntaxError but got %v", err)
+	}
+	assertEq(t, "offset", int64(14), syntaxErr.Offset)
+	assertEq(t, "line", int64(2), syntaxErr.Line)
+	assertEq(t, "column", int64(5), syntaxErr.Column)
+}
+
+func TestValidReader(t *testing.T) {
+	for _, tt := range validTests {
+		if err := json.ValidReader(iotest.OneByteReader(strings.NewReader(tt.data))); (err == nil) != tt.ok {
+			t.Errorf("ValidReader(%#q) = %v, want ok %v", tt.data, err, tt.ok)
+		}
+	}
+	t.Run("big", func(t *testing.T) {
+		initBig()
+		assertErr(t, json.ValidReader(bytes.NewReader(jsonBig)))
+		err := json.ValidReader(io.MultiReader(bytes.NewReader(jsonBig), strings.NewReader("\n\n}")))
+		var syntaxErr *json.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("expected SyntaxError but got %v", err)
+		}
+		assertEq(t, "offset", int64(len(jsonBig)+2), syntaxErr.Offset)
+		assertEq(t, "line", int64(3), syntaxErr.Line)
+	})
+	t.Run("same errors as Validate", func(t *testing.T) {
+		for _, test := range []struct {
+			data     string
+			expected string
+			offset   int64
+		}{
+			{data: `01`, expected: `invalid character '1' after top-level value`, offset: 1},
+			{data: `nulll`, expected: `invalid character 'l' after top-level value`, offset: 4},
+			{data: `tru`, expected: `json: true unexpected end of JSON input`, offset: 3},
+			{data: `[tx]`, expected: `json: invalid character x as true`, offset: 2},
+			{data: `[1.]`, expected: `json: invalid character ] as number`, offset: 3},
+			{data: `-`, expected: `json: number unexpected end of JSON input`, offset: 1},
+			{data: ``, expected: `json: value unexpected end of JSON input`, offset: 0},
+			{data: `[`, expected: `json: array unexpected end of JSON input`, offset: 1},
+			{data: `[1,`, expected: `json: array unexpected end of JSON input`, offset: 3},
+			{data: `[1,]`, expected: `invalid character ']' looking for beginning of value`, offset: 3},
+			{data: `[1 2]`, expected: `expected comma after array element`, offset: 3},
+			{data: `{`, expected: `json: object unexpected end of JSON input`, offset: 1},
+			{data: `{,}`, expected: `expected object key`, offset: 1},
+			{data: `{"a":1,}`, expected: `expected object key`, offset: 7},
+			{data: `{"a" 1}`, expected: `expected colon after object key`, offset: 5},
+			{data: `{"a":}`, expected: `invalid character '}' looking for beginning of value`, offset: 5},
+			{data: `{"a":1 "b":2}`, expected: `expected comma after object element`, offset: 7},
+			{data: `"abc`, expected: `json: string unexpected end of JSON input`, offset: 4},
+			{data: "\"a\x01\"", expected: "json: invalid character \x01 as string", offset: 2},
+			{data: "\"a\x00b\"", expected: `json: invalid character as string`, offset: 2},
+			{data: `"\x"`, expected: `json: invalid character x as escape sequence`, offset: 2},
+			{data: `"\u12"`, expected: `json: invalid character " as escape sequence`, offset: 5},
+			{data: `"\u12`, expected: `json: string unexpected end of JSON input`, offset: 5},
+			{data: `"\`, expected: `json: string unexpected end of JSON input`, offset: 2},
+			{data: `]`, expected: `invalid character ']' looking for beginning of value`, offset: 0},
+			{data: `[1]]`, expected: `invalid character ']' after top-level value`, offset: 3},
+			{data: "[\n1,\n2\n", expected: `json: array unexpected end of JSON input`, offset: 7},
+		} {
+			validateErr := json.Validate([]byte(test.data))
+			var validateSyntaxErr *json.SyntaxError
+			if !errors.As(validateErr, &validateSyntaxErr) || !strings.HasPrefix(validateErr.Error(), test.expected) {
+				t.Errorf("Validate(%#q) = %v, want %s", test.data, validateErr, test.expected)
+				continue
+			}
+			if validateSyntaxErr.Offset != test.offset {
+				t.Errorf("Validate(%#q) offset = %d, want %d", test.data, validateSyntaxErr.Offset, test.offset)
+			}
+			readerErr := json.ValidReader(iotest.OneByteReader(strings.NewReader(test.data)))
+			var readerSyntaxErr *json.SyntaxError
+			if !errors.As(readerErr, &readerSyntaxErr) || readerErr.Error() != validateErr.Error() {
+				t.Errorf("ValidReader(%#q) = %v, want %v", test.data, readerErr, validateErr)
+				continue
+			}
+			if readerSyntaxErr.Offset != test.offset {
+				t.Errorf("ValidReader(%#q) offset = %d, want %d", test.data, readerSyntaxErr.Offset, test.offset)
+			}
+		}
+	})
+	t.Run("read error", func(t *testing.T) {
+		err := json.ValidReader(iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader("[1, 2]"))))
+		if err != iotest.ErrTimeout {
+			t.Fatalf("expected %v but got %v", iotest.ErrTimeout, err)
+		}
+	})
+}
+
 type example struct {
 	compact string
 	indent  string
@@ -121,6 +226,16 @@ func TestCompact(t *testing.T) {
 			}
 		}
 	})
+	t.Run("append", func(t *testing.T) {
+		buf.Reset()
+		buf.WriteString("prefix:")
+		if err := json.Compact(&buf, []byte(`[1, 2]`)); err != nil {
+			t.Fatal(err)
+		}
+		if s := buf.String(); s != "prefix:[1,2]" {
+			t.Errorf("Compact appended %q", s)
+		}
+	})
 }
 
 func TestCompactSeparators(t *testing.T) {
@@ -270,6 +385,98 @@ func TestIndentErrors(t *testing.T) {
 	}
 }
 
+func TestCompactStream(t *testing.T) {
+	for _, tt := range examples {
+		var buf bytes.Buffer
+		if err := json.CompactStream(&buf, iotest.OneByteReader(strings.NewReader(tt.indent))); err != nil {
+			t.Errorf("CompactStream(%#q): %v", tt.indent, err)
+		} else if s := buf.String(); s != tt.compact+"\n" {
+			t.Errorf("CompactStream(%#q) = %#q, want %#q", tt.indent, s, tt.compact+"\n")
+		}
+	}
+	t.Run("multiple values", func(t *testing.T) {
+		var buf bytes.Buffer
+		assertErr(t, json.CompactStream(&buf, strings.NewReader(" {\"a\": [1, 2]}\n\"x\" 1.5\ttrue[ ]\n")))
+		assertEq(t, "output", "{\"a\":[1,2]}\n\"x\"\n1.5\ntrue\n[]\n", buf.String())
+	})
+	t.Run("big", func(t *testing.T) {
+		initBig()
+		var indented bytes.Buffer
+		assertErr(t, json.Indent(&indented, jsonBig, "", "\t"))
+		var buf bytes.Buffer
+		assertErr(t, json.CompactStream(&buf, &indented))
+		if b := buf.Bytes(); !bytes.Equal(b, append(jsonBig, '\n')) {
+			t.Error("CompactStream(Indent(jsonBig)) != jsonBig")
+			diff(t, b, jsonBig)
+		}
+	})
+	t.Run("syntax error", func(t *testing.T) {
+		for _, src := range []string{
+			"{\"a\": [1,\n 2,]}",
+			"{\"a\": [1,\n 2",
+			"[1,\n \"x]",
+			"[1,\n tru]",
+			"[1,\n 01]",
+			"{\"a\": 1}\n}",
+			"[1,\n \"\\x\"]",
+			"[1,\n \"\\u12\"]",
+			"[1,\n \"a\tb\"]",
+		} {
+			var buf bytes.Buffer
+			err := json.CompactStream(&buf, iotest.OneByteReader(strings.NewReader(src)))
+			var syntaxErr *json.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("%q: expected SyntaxError but got %v", src, err)
+			}
+			assertEq(t, "line", int64(2), syntaxErr.Line)
+		}
+	})
+	t.Run("read error", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := json.CompactStream(&buf, iotest.TimeoutReader(iotest.OneByteReader(strings.NewReader("[1, 2]"))))
+		if err != iotest.ErrTimeout {
+			t.Fatalf("expected %v but got %v", iotest.ErrTimeout, err)
+		}
+	})
+	t.Run("write error", func(t *testing.T) {
+		w := &errorWriter{err: errors.New("write error")}
+		err := json.CompactStream(w, strings.NewReader("[1, 2]"))
+		if err != w.err {
+			t.Fatalf("expected %v but got %v", w.err, err)
+		}
+	})
+}
+
+func TestIndentStream(t *testing.T) {
+	for _, tt := range examples {
+		var buf bytes.Buffer
+		if err := json.IndentStream(&buf, iotest.OneByteReader(strings.NewReader(tt.compact)), "", "\t"); err != nil {
+			t.Errorf("IndentStream(%#q): %v", tt.compact, err)
+		} else if s := buf.String(); s != tt.indent+"\n" {
+			t.Errorf("IndentStream(%#q) = %#q, want %#q", tt.compact, s, tt.indent+"\n")
+		}
+	}
+	t.Run("big", func(t *testing.T) {
+		initBig()
+		var expected bytes.Buffer
+		assertErr(t, json.Indent(&expected, jsonBig, ">", "  "))
+		var buf bytes.Buffer
+		assertErr(t, json.IndentStream(&buf, bytes.NewReader(jsonBig), ">", "  "))
+		if b := buf.Bytes(); !bytes.Equal(b, append(expected.Bytes(), '\n')) {
+			t.Error("IndentStream(jsonBig) != Indent(jsonBig)")
+			diff(t, b, expected.Bytes())
+		}
+	})
+}
+
+type errorWriter struct {
+	err error
+}
+
+func (w *errorWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
 func diff(t *testing.T, a, b []byte) {
 	t.Helper()
 	for i := 0; ; i++ {
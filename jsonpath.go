@@ -0,0 +1,45 @@
+package json
+
+import (
+	"github.com/goccy/go-json/internal/errors"
+	"github.com/goccy/go-json/internal/jsonpath"
+)
+
+// QueryResult is a value selected by Query.
+type QueryResult struct {
+	// Path is the normalized path of the value, e.g. $['store']['book'][0]['title'].
+	Path string
+	// Value is the raw JSON value.
+	Value RawMessage
+}
+
+// Query evaluates the JSONPath query (RFC 9535) against the JSON document data
+// and returns the selected values in the order defined by the query:
+//
+//	results, err := json.Query(data, "$.store.book[?@.price < 10].title")
+//
+// Queries support child and descendant segments with name, wildcard, index, slice and filter selectors.
+// Filters can use the functions length, count, match, search and value; match and search use
+// the syntax of the regexp package, with the dot not matching line breaks as in I-Regexp (RFC 9485).
+//
+// The document is not decoded into Go values: Query skips over the values that the query
+// does not select, and only decodes the values compared by filters.
+// Only the parts of the document visited by the query are fully validated.
+// The returned values do not share memory with data.
+func Query(data []byte, query string) ([]QueryResult, error) {
+	q, err := jsonpath.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, len(data)+1) // append nul byte to the end
+	copy(buf, data)
+	nodes, err := q.Eval(buf)
+	if err != nil {
+		return nil, errors.Locate(err, data, 0, 1, 0)
+	}
+	results := make([]QueryResult, 0, len(nodes))
+	for _, n := range nodes {
+		results = append(results, QueryResult{Path: n.Path, Value: RawMessage(buf[n.Start:n.End:n.End])})
+	}
+	return results, nil
+}
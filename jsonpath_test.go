@@ -0,0 +1,181 @@
+package json_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+const jsonPathStore = `{ "store": {
+    "book": [
+      { "category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95 },
+      { "category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99 },
+      { "category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99 },
+      { "category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99 }
+    ],
+    "bicycle": { "color": "red", "price": 399 }
+  } }`
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		query string
+		paths []string
+		want  []string
+	}{
+		{
+			name:  "filter",
+			data:  jsonPathStore,
+			query: "$.store.book[?@.price < 10].title",
+			paths: []string{"$['store']['book'][0]['title']", "$['store']['book'][2]['title']"},
+			want:  []string{`"Sayings of the Century"`, `"Moby Dick"`},
+		},
+		{
+			name:  "descendant",
+			data:  jsonPathStore,
+			query: "$.store..price",
+			paths: []string{
+				"$['store']['book'][0]['price']",
+				"$['store']['book'][1]['price']",
+				"$['store']['book'][2]['price']",
+				"$['store']['book'][3]['price']",
+				"$['store']['bicycle']['price']",
+			},
+			want: []string{"8.95", "12.99", "8.99", "22.99", "399"},
+		},
+		{
+			name:  "wildcard",
+			data:  jsonPathStore,
+			query: `$["store"].*.color`,
+			paths: []string{"$['store']['bicycle']['color']"},
+			want:  []string{`"red"`},
+		},
+		{
+			name:  "index and union",
+			data:  `[0, 1, 2, 3, 4, 5]`,
+			query: "$[-1, 0, 0]",
+			paths: []string{"$[5]", "$[0]", "$[0]"},
+			want:  []string{"5", "0", "0"},
+		},
+		{
+			name:  "slice",
+			data:  `[0, 1, 2, 3, 4, 5]`,
+			query: "$[1:5:2]",
+			paths: []string{"$[1]", "$[3]"},
+			want:  []string{"1", "3"},
+		},
+		{
+			name:  "negative step",
+			data:  `[0, 1, 2, 3, 4, 5]`,
+			query: "$[::-2]",
+			paths: []string{"$[5]", "$[3]", "$[1]"},
+			want:  []string{"5", "3", "1"},
+		},
+		{
+			name:  "logical operators",
+			data:  jsonPathStore,
+			query: "$.store.book[?(@.price > 10 && @.price < 20) || !@.isbn && @.category == 'reference'].author",
+			paths: []string{"$['store']['book'][0]['author']", "$['store']['book'][1]['author']"},
+			want:  []string{`"Nigel Rees"`, `"Evelyn Waugh"`},
+		},
+		{
+			name:  "existence",
+			data:  jsonPathStore,
+			query: "$..book[?@.isbn].price",
+			paths: []string{"$['store']['book'][2]['price']", "$['store']['book'][3]['price']"},
+			want:  []string{"8.99", "22.99"},
+		},
+		{
+			name:  "functions",
+			data:  jsonPathStore,
+			query: "$..book[?match(@.author, 'J.*') || search(@.title, 'Hon') && length(@.title) == 15].price",
+			paths: []string{"$['store']['book'][1]['price']", "$['store']['book'][3]['price']"},
+			want:  []string{"12.99", "22.99"},
+		},
+		{
+			name:  "count and value",
+			data:  jsonPathStore,
+			query: "$.store[?count(@.*) == 2 && value(@..color) == 'red'].price",
+			paths: []string{"$['store']['bicycle']['price']"},
+			want:  []string{"399"},
+		},
+		{
+			name:  "deep equality",
+			data:  `{"a": {"x": [1, {"y": null}]}, "b": {"x": [1.0, {"y": null}]}, "c": {"x": [1]}}`,
+			query: "$[?@ == $.a]",
+			paths: []string{"$['a']", "$['b']"},
+			want:  []string{`{"x": [1, {"y": null}]}`, `{"x": [1.0, {"y": null}]}`},
+		},
+		{
+			name:  "missing values",
+			data:  `[{"a": null}, {}, {"a": 1}]`,
+			query: "$[?@.a == null]",
+			paths: []string{"$[0]"},
+			want:  []string{`{"a": null}`},
+		},
+		{
+			name:  "normalized path escapes",
+			data:  `{"it's": {"a\nb": true}}`,
+			query: "$..*",
+			paths: []string{`$['it\'s']`, `$['it\'s']['a\nb']`},
+			want:  []string{`{"a\nb": true}`, "true"},
+		},
+		{
+			name:  "no match",
+			data:  jsonPathStore,
+			query: "$.store.book[10].title",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			results, err := json.Query([]byte(test.data), test.query)
+			assertErr(t, err)
+			var paths, values []string
+			for _, result := range results {
+				paths = append(paths, result.Path)
+				values = append(values, string(result.Value))
+			}
+			if !reflect.DeepEqual(paths, test.paths) {
+				t.Fatalf("unexpected paths: %q", paths)
+			}
+			if !reflect.DeepEqual(values, test.want) {
+				t.Fatalf("unexpected values: %q", values)
+			}
+		})
+	}
+	t.Run("invalid query", func(t *testing.T) {
+		for _, query := range []string{
+			"",
+			"store",
+			"$.",
+			"$[",
+			"$ ",
+			"$[01]",
+			"$[-0]",
+			"$[9007199254740992]",
+			"$['a]",
+			"$[?true]",
+			"$[?@.a == @.*]",
+			"$[?count(@.*)]",
+			"$[?length(@.*) > 1]",
+			"$[?match(@.a, 'a') == true]",
+			"$[?unknown(@)]",
+			"$[?!@.a == 1]",
+		} {
+			if _, err := json.Query([]byte(`{}`), query); err == nil {
+				t.Errorf("expected error for %q", query)
+			}
+		}
+	})
+	t.Run("syntax error", func(t *testing.T) {
+		_, err := json.Query([]byte("{\"a\": [1,\n 2,]}"), "$.a[*]")
+		var syntaxErr *json.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("expected SyntaxError but got %v", err)
+		}
+		assertEq(t, "line", int64(2), syntaxErr.Line)
+	})
+}
@@ -0,0 +1,35 @@
+package json
+
+import (
+	"reflect"
+
+	"github.com/goccy/go-json/internal/encoder"
+	"github.com/goccy/go-json/internal/runtime"
+)
+
+// JSONSchemaer is the interface implemented by types that describe their own
+// JSON encoding with a JSON Schema. SchemaFor uses it for types implementing
+// Marshaler or encoding.TextMarshaler, whose encoding cannot be derived from
+// their Go type. JSONSchema is called on a pointer to the zero value.
+type JSONSchemaer interface {
+	JSONSchema() []byte
+}
+
+// SchemaFor returns a JSON Schema (draft 2020-12) describing the encoding of values of typ.
+//
+// The schema is generated from the same field resolution as Marshal: field names,
+// omitempty, the "string", format and nilasempty options, embedded struct fields and the elimination of
+// duplicated fields all match what Marshal produces. Fields with omitempty are not required
+// and pointers, slices and maps are nullable, except for slices and maps with nilasempty. Named struct types are placed in "$defs",
+// so recursive types are supported.
+//
+// Types implementing Marshaler or encoding.TextMarshaler are described by their JSONSchema
+// method if they implement JSONSchemaer. Otherwise time.Time is described as a date-time
+// string, other TextMarshalers as strings, and other Marshalers accept any value.
+// Interfaces registered with RegisterUnion are described as one of their member types.
+func SchemaFor(typ reflect.Type) ([]byte, error) {
+	if typ == nil {
+		return nil, &UnsupportedTypeError{Type: typ}
+	}
+	return encoder.Schema(runtime.Type2RType(typ))
+}
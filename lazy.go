@@ -0,0 +1,231 @@
+package json
+
+import (
+	"reflect"
+
+	"github.com/goccy/go-json/internal/decoder"
+	"github.com/goccy/go-json/internal/dom"
+	"github.com/goccy/go-json/internal/errors"
+)
+
+// Kind is the kind of a JSON value.
+type Kind = dom.Kind
+
+const (
+	KindInvalid = dom.KindInvalid
+	KindNull    = dom.KindNull
+	KindBool    = dom.KindBool
+	KindNumber  = dom.KindNumber
+	KindString  = dom.KindString
+	KindArray   = dom.KindArray
+	KindObject  = dom.KindObject
+)
+
+// LazyValue is a raw JSON value that is decoded on demand.
+// Unlike RawMessage, it can inspect its object members and array elements:
+// the first call to Get or Index indexes the members of the value by skipping over them
+// without decoding, and nested values are only scanned once they are accessed themselves.
+//
+// A LazyValue is usually obtained by decoding into it with Unmarshal or a Decoder.
+// Malformed JSON is reported by the method that reaches it.
+// A LazyValue must not be used by multiple goroutines at the same time.
+type LazyValue struct {
+	buf   []byte // nul terminated JSON text, shared with the nested values
+	start int64
+	end   int64
+	index *lazyIndex
+}
+
+type lazyIndex struct {
+	keys   map[string]int
+	values []*LazyValue
+}
+
+// UnmarshalJSON sets v to a copy of data.
+func (v *LazyValue) UnmarshalJSON(data []byte) error {
+	buf := make([]byte, len(data)+1) // append nul byte to the end
+	copy(buf, data)
+	s := &decoder.Scanner{Buf: buf}
+	s.Next()
+	end := int64(len(data))
+	for end > s.Cursor {
+		switch buf[end-1] {
+		case ' ', '\t', '\n', '\r':
+			end--
+			continue
+		}
+		break
+	}
+	*v = LazyValue{buf: buf, start: s.Cursor, end: end}
+	return nil
+}
+
+// MarshalJSON returns the raw JSON value, or null for the zero LazyValue.
+func (v LazyValue) MarshalJSON() ([]byte, error) {
+	if v.start >= v.end {
+		return []byte("null"), nil
+	}
+	return v.buf[v.start:v.end:v.end], nil
+}
+
+// Raw returns the raw JSON value. It shares memory with v and must not be modified.
+func (v *LazyValue) Raw() RawMessage {
+	if v == nil || v.start >= v.end {
+		return nil
+	}
+	return RawMessage(v.buf[v.start:v.end:v.end])
+}
+
+// Kind returns the kind of v, as determined by its first byte.
+// It returns KindInvalid for a nil or zero LazyValue.
+func (v *LazyValue) Kind() Kind {
+	if v == nil || v.start >= v.end {
+		return KindInvalid
+	}
+	switch v.buf[v.start] {
+	case 'n':
+		return KindNull
+	case 't', 'f':
+		return KindBool
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return KindNumber
+	case '"':
+		return KindString
+	case '[':
+		return KindArray
+	case '{':
+		return KindObject
+	}
+	return KindInvalid
+}
+
+// Get returns the value of key in the object v, or nil if v does not have key.
+// If key appears more than once, the last value is returned like when decoding into a map.
+func (v *LazyValue) Get(key string) (*LazyValue, error) {
+	if err := v.buildIndex(KindObject, reflect.TypeOf(map[string]LazyValue(nil))); err != nil {
+		return nil, err
+	}
+	i, exists := v.index.keys[key]
+	if !exists {
+		return nil, nil
+	}
+	return v.index.values[i], nil
+}
+
+// Index returns the i'th element of the array v, or nil if i is out of range.
+func (v *LazyValue) Index(i int) (*LazyValue, error) {
+	if err := v.buildIndex(KindArray, reflect.TypeOf([]LazyValue(nil))); err != nil {
+		return nil, err
+	}
+	if i < 0 || i >= len(v.index.values) {
+		return nil, nil
+	}
+	return v.index.values[i], nil
+}
+
+// Len returns the number of elements of the array v or the number of members of the object v.
+func (v *LazyValue) Len() (int, error) {
+	kind := v.Kind()
+	if kind != KindObject {
+		kind = KindArray
+	}
+	if err := v.buildIndex(kind, reflect.TypeOf([]LazyValue(nil))); err != nil {
+		return 0, err
+	}
+	return len(v.index.values), nil
+}
+
+// String returns the unescaped value of the string v.
+func (v *LazyValue) String() (string, error) {
+	var s string
+	if err := v.decodeKind(KindString, &s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
+// Int returns the value of the number v, which must be an integer that fits in an int64.
+func (v *LazyValue) Int() (int64, error) {
+	var n int64
+	if err := v.decodeKind(KindNumber, &n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Decode decodes v into the value pointed to by dst like UnmarshalWithOption.
+func (v *LazyValue) Decode(dst interface{}, optFuncs ...DecodeOptionFunc) error {
+	if v.Kind() == KindInvalid {
+		return errors.ErrUnexpectedEndOfJSON("value", 0)
+	}
+	return unmarshal(v.Raw(), dst, optFuncs...)
+}
+
+func (v *LazyValue) decodeKind(kind Kind, dst interface{}) error {
+	if actual := v.Kind(); actual != kind {
+		return v.typeError(actual, reflect.TypeOf(dst).Elem())
+	}
+	return unmarshal(v.Raw(), dst)
+}
+
+func (v *LazyValue) typeError(kind Kind, typ reflect.Type) error {
+	var offset int64
+	if v != nil {
+		offset = v.start
+	}
+	return &UnmarshalTypeError{Value: kind.String(), Type: typ, Offset: offset}
+}
+
+// buildIndex records the position of every member of the array or object v,
+// skipping the member values without decoding them.
+func (v *LazyValue) buildIndex(kind Kind, typ reflect.Type) error {
+	if actual := v.Kind(); actual != kind {
+		return v.typeError(actual, typ)
+	}
+	if v.index != nil {
+		return nil
+	}
+	end := byte(']')
+	index := &lazyIndex{}
+	if kind == KindObject {
+		end = '}'
+		index.keys = map[string]int{}
+	}
+	s := &decoder.Scanner{Buf: v.buf, Cursor: v.start}
+	if err := s.Begin(v.buf[v.start], 1); err != nil {
+		return v.locate(err)
+	}
+	for first := true; ; first = false {
+		more, err := s.Element(first, end)
+		if err != nil {
+			return v.locate(err)
+		}
+		if !more {
+			break
+		}
+		if kind == KindObject {
+			key, err := s.Key()
+			if err != nil {
+				return v.locate(err)
+			}
+			index.keys[string(key)] = len(index.values)
+			s.Next()
+		}
+		cursor, err := decoder.SkipValue(v.buf, s.Cursor, 1)
+		if err != nil {
+			return v.locate(err)
+		}
+		index.values = append(index.values, &LazyValue{buf: v.buf, start: s.Cursor, end: cursor})
+		s.Cursor = cursor
+	}
+	if s.Cursor > v.end {
+		return v.locate(errors.ErrUnexpectedEndOfJSON(kind.String(), v.end))
+	}
+	v.index = index
+	return nil
+}
+
+// locate sets the position of err in the whole JSON text v was decoded from.
+func (v *LazyValue) locate(err error) error {
+	return errors.Locate(err, v.buf[:len(v.buf)-1], 0, 1, 0)
+}
@@ -0,0 +1,111 @@
+package json
+
+import (
+	"github.com/goccy/go-json/internal/decoder"
+	"github.com/goccy/go-json/internal/runtime"
+)
+
+// OrderedMap is a JSON object that keeps the order of its keys.
+// Keys are looked up in constant time, and the map is encoded with its keys in insertion order.
+// The zero value is an empty map ready to use.
+type OrderedMap[V any] struct {
+	keys   runtime.OrderedMapKeys
+	values []V
+	index  map[string]int
+}
+
+// Object is the OrderedMap decoded for JSON objects into interface{} values
+// with DecodeOrderedObjects.
+type Object = OrderedMap[interface{}]
+
+// Len returns the number of keys in m.
+func (m *OrderedMap[V]) Len() int {
+	return len(m.keys)
+}
+
+// Get returns the value for key and whether key exists in m.
+func (m *OrderedMap[V]) Get(key string) (V, bool) {
+	if i, ok := m.index[key]; ok {
+		return m.values[i], true
+	}
+	var zero V
+	return zero, false
+}
+
+// Set sets the value for key. A new key is added after all existing keys,
+// an existing key keeps its position.
+func (m *OrderedMap[V]) Set(key string, value V) {
+	if i, ok := m.index[key]; ok {
+		m.values[i] = value
+		return
+	}
+	if m.index == nil {
+		m.index = map[string]int{}
+	}
+	m.index[key] = len(m.keys)
+	m.keys = append(m.keys, key)
+	m.values = append(m.values, value)
+}
+
+// Delete removes key from m. The keys after it move up by one position,
+// so Delete takes time proportional to their number.
+func (m *OrderedMap[V]) Delete(key string) {
+	i, ok := m.index[key]
+	if !ok {
+		return
+	}
+	delete(m.index, key)
+	m.keys = append(m.keys[:i], m.keys[i+1:]...)
+	m.values = append(m.values[:i], m.values[i+1:]...)
+	for ; i < len(m.keys); i++ {
+		m.index[m.keys[i]] = i
+	}
+}
+
+// Keys returns the keys of m in order.
+func (m *OrderedMap[V]) Keys() []string {
+	keys := make([]string, len(m.keys))
+	copy(keys, m.keys)
+	return keys
+}
+
+// Range calls f for each key and value in order until f returns false.
+// m must not be modified by f.
+func (m *OrderedMap[V]) Range(f func(key string, value V) bool) {
+	for i, key := range m.keys {
+		if !f(key, m.values[i]) {
+			return
+		}
+	}
+}
+
+// MarshalJSON encodes m as a JSON object with its keys in order.
+// OrderedMap values are handled by the encoder directly, so that they are encoded
+// with the options of the caller, and MarshalJSON is only used by other packages such as encoding/json.
+func (m OrderedMap[V]) MarshalJSON() ([]byte, error) {
+	return Marshal(m)
+}
+
+// UnmarshalJSON adds the keys of the JSON object in data to m in order.
+// Objects decoded into interface{} values are ordered as well.
+// Like MarshalJSON, it is only used by other packages.
+func (m *OrderedMap[V]) UnmarshalJSON(data []byte) error {
+	return Unmarshal(data, m)
+}
+
+// DecodeOrderedObjects makes JSON objects decoded into interface{} values become *Object
+// instead of map[string]interface{}, so that their key order is kept.
+func DecodeOrderedObjects() DecodeOptionFunc {
+	return func(opt *DecodeOption) {
+		opt.Flags |= decoder.OrderedObjectsOption
+		opt.NewObject = newOrderedObject
+	}
+}
+
+func init() {
+	decoder.NewOrderedObject = newOrderedObject
+}
+
+func newOrderedObject() decoder.OrderedObject {
+	return &Object{}
+}
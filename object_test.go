@@ -0,0 +1,173 @@
+package json_test
+
+import (
+	stdjson "encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+func TestDecodeOrderedObjects(t *testing.T) {
+	const src = `{"z":1,"a":{"y":[{"c":true,"b":null}],"x":"s"},"m":2}`
+	keys := func(t *testing.T, v interface{}) string {
+		t.Helper()
+		obj, ok := v.(*json.Object)
+		if !ok {
+			t.Fatalf("expected *json.Object, got %T", v)
+		}
+		return strings.Join(obj.Keys(), ",")
+	}
+	assertResult := func(t *testing.T, v interface{}) {
+		t.Helper()
+		assertEq(t, "keys", "z,a,m", keys(t, v))
+		a, _ := v.(*json.Object).Get("a")
+		assertEq(t, "nested keys", "y,x", keys(t, a))
+		y, _ := a.(*json.Object).Get("y")
+		assertEq(t, "array element keys", "c,b", keys(t, y.([]interface{})[0]))
+		got, err := json.Marshal(v)
+		assertErr(t, err)
+		assertEq(t, "encode", src, string(got))
+	}
+	t.Run("unmarshal", func(t *testing.T) {
+		var v interface{}
+		assertErr(t, json.UnmarshalWithOption([]byte(src), &v, json.DecodeOrderedObjects()))
+		assertResult(t, v)
+	})
+	t.Run("stream", func(t *testing.T) {
+		var v interface{}
+		assertErr(t, json.NewDecoder(strings.NewReader(src)).DecodeWithOption(&v, json.DecodeOrderedObjects()))
+		assertResult(t, v)
+	})
+	t.Run("object target", func(t *testing.T) {
+		var v json.Object
+		assertErr(t, json.Unmarshal([]byte(src), &v))
+		assertResult(t, &v)
+	})
+	t.Run("typed values", func(t *testing.T) {
+		var v json.OrderedMap[int]
+		assertErr(t, json.Unmarshal([]byte(`{"b":2,"a":1,"b":3}`), &v))
+		assertEq(t, "keys", "b,a", strings.Join(v.Keys(), ","))
+		b, ok := v.Get("b")
+		assertEq(t, "found", true, ok)
+		assertEq(t, "last value wins", 3, b)
+
+		var typeErr *json.UnmarshalTypeError
+		if err := json.Unmarshal([]byte(`[1]`), &v); !errors.As(err, &typeErr) {
+			t.Fatalf("expected UnmarshalTypeError, got %v", err)
+		}
+	})
+	t.Run("duplicate keys", func(t *testing.T) {
+		var v interface{}
+		err := json.UnmarshalWithOption([]byte(`{"a":1,"a":2}`), &v, json.DecodeOrderedObjects(), json.DecodeRejectDuplicateKeys())
+		var dupErr *json.DuplicateKeyError
+		if !errors.As(err, &dupErr) {
+			t.Fatalf("expected DuplicateKeyError, got %v", err)
+		}
+	})
+	t.Run("error path", func(t *testing.T) {
+		var v interface{}
+		err := json.UnmarshalWithOption([]byte(`{"a":{"b":[1,tru]}}`), &v, json.DecodeOrderedObjects())
+		var syntaxErr *json.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("expected SyntaxError, got %v", err)
+		}
+		assertEq(t, "path", "$.a.b[1]", syntaxErr.Path)
+	})
+}
+
+func TestEncodeOrderedMap(t *testing.T) {
+	var m json.OrderedMap[int]
+	m.Set("z", 1)
+	m.Set("a", 2)
+	m.Set("m", 3)
+	m.Set("z", 4)
+	m.Delete("a")
+	m.Set("<b>", 5)
+	assertEq(t, "len", 3, m.Len())
+
+	got, err := json.Marshal(&m)
+	assertErr(t, err)
+	assertEq(t, "pointer", `{"z":4,"m":3,"\u003cb\u003e":5}`, string(got))
+
+	got, err = json.Marshal(struct {
+		M json.OrderedMap[int] `json:"m"`
+	}{M: m})
+	assertErr(t, err)
+	assertEq(t, "field", `{"m":{"z":4,"m":3,"\u003cb\u003e":5}}`, string(got))
+
+	got, err = json.MarshalIndent(&m, "", "  ")
+	assertErr(t, err)
+	assertEq(t, "indent", "{\n  \"z\": 4,\n  \"m\": 3,\n  \"\\u003cb\\u003e\": 5\n}", string(got))
+
+	var visited []string
+	m.Range(func(key string, value int) bool {
+		visited = append(visited, key)
+		return key != "m"
+	})
+	assertEq(t, "range", "z,m", strings.Join(visited, ","))
+
+	got, err = json.Marshal(json.Object{})
+	assertErr(t, err)
+	assertEq(t, "empty", `{}`, string(got))
+}
+
+func TestOrderedMapOptions(t *testing.T) {
+	type T struct {
+		P string `json:"p,sensitive"`
+		B []byte `json:"b"`
+	}
+	t.Run("encode", func(t *testing.T) {
+		var m json.OrderedMap[T]
+		m.Set("x", T{P: "secret", B: []byte{1, 2}})
+		got, err := json.MarshalWithOption(m, json.EncodeRedact(nil), json.EncodeBytesFormat(json.BytesFormatHex))
+		assertErr(t, err)
+		assertEq(t, "redact and bytes format", `{"x":{"p":"***","b":"0102"}}`, string(got))
+
+		var paths []string
+		_, err = json.MarshalWithOption(map[string]*json.OrderedMap[T]{"m": &m}, json.EncodeRedact(func(path string) string {
+			paths = append(paths, path)
+			return ""
+		}))
+		assertErr(t, err)
+		assertEq(t, "path", "$.*.*.p", strings.Join(paths, ","))
+
+		var nested json.OrderedMap[json.OrderedMap[int]]
+		var inner json.OrderedMap[int]
+		inner.Set("b", 1)
+		inner.Set("a", 2)
+		nested.Set("y", inner)
+		nested.Set("x", json.OrderedMap[int]{})
+		got, err = json.MarshalIndent(nested, "", "  ")
+		assertErr(t, err)
+		assertEq(t, "nested indent", "{\n  \"y\": {\n    \"b\": 1,\n    \"a\": 2\n  },\n  \"x\": {}\n}", string(got))
+
+		got, err = stdjson.Marshal(nested)
+		assertErr(t, err)
+		assertEq(t, "encoding/json", `{"y":{"b":1,"a":2},"x":{}}`, string(got))
+	})
+	t.Run("decode", func(t *testing.T) {
+		var m json.OrderedMap[int]
+		err := json.UnmarshalWithOption([]byte(`{"a":1,"a":2}`), &m, json.DecodeRejectDuplicateKeys())
+		var dupErr *json.DuplicateKeyError
+		if !errors.As(err, &dupErr) {
+			t.Fatalf("expected DuplicateKeyError, got %v", err)
+		}
+		err = json.NewDecoder(strings.NewReader(`{"a":1,"a":2}`)).DecodeWithOption(&m, json.DecodeRejectDuplicateKeys())
+		if !errors.As(err, &dupErr) {
+			t.Fatalf("stream: expected DuplicateKeyError, got %v", err)
+		}
+
+		var v json.OrderedMap[T]
+		assertErr(t, json.UnmarshalWithOption([]byte(`{"x":{"b":"0102"}}`), &v, json.DecodeBytesFormat(json.BytesFormatHex)))
+		x, _ := v.Get("x")
+		assertEq(t, "bytes format", "\x01\x02", string(x.B))
+
+		var std json.OrderedMap[interface{}]
+		assertErr(t, stdjson.Unmarshal([]byte(`{"b":{"d":1,"c":2},"a":null}`), &std))
+		assertEq(t, "encoding/json keys", "b,a", strings.Join(std.Keys(), ","))
+		b, _ := std.Get("b")
+		assertEq(t, "encoding/json nested keys", "d,c", strings.Join(b.(*json.Object).Keys(), ","))
+	})
+}
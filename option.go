@@ -5,6 +5,8 @@ import (
 
 	"github.com/goccy/go-json/internal/decoder"
 	"github.com/goccy/go-json/internal/encoder"
+	"github.com/goccy/go-json/internal/runtime"
+	"github.com/goccy/go-json/schema"
 )
 
 type EncodeOption = encoder.Option
@@ -63,6 +65,89 @@ func Colorize(scheme *ColorScheme) EncodeOptionFunc {
 	}
 }
 
+// BytesFormat selects the representation of []byte values in JSON.
+// Struct fields can select it with the format option of their tag, e.g. `json:"id,format:base64url"`,
+// which takes precedence over EncodeBytesFormat and DecodeBytesFormat.
+// An unknown format name in a tag makes encoding and decoding the struct fail.
+type BytesFormat = runtime.BytesFormat
+
+const (
+	// BytesFormatBase64 is the standard base64 encoding with padding (RFC 4648 section 4), the default format.
+	// Its tag option is format:base64.
+	BytesFormatBase64 = runtime.BytesFormatBase64
+	// BytesFormatBase64URL is the URL and filename safe base64 encoding without padding (RFC 4648 section 5).
+	// Decoding also accepts padding. Its tag option is format:base64url.
+	BytesFormatBase64URL = runtime.BytesFormatBase64URL
+	// BytesFormatBase64Raw is the standard base64 encoding without padding.
+	// Decoding also accepts padding. Its tag option is format:base64raw.
+	BytesFormatBase64Raw = runtime.BytesFormatBase64Raw
+	// BytesFormatHex is the lowercase hexadecimal encoding. Decoding also accepts uppercase digits.
+	// Its tag option is format:hex.
+	BytesFormatHex = runtime.BytesFormatHex
+	// BytesFormatArray is an array of numbers, one for each byte. Its tag option is format:array.
+	// Decoding accepts arrays of numbers with every format, and standard base64 strings with this one.
+	BytesFormatArray = runtime.BytesFormatArray
+)
+
+// EncodeBytesFormat sets the format of []byte values, except for struct fields whose tag selects a format.
+func EncodeBytesFormat(format BytesFormat) EncodeOptionFunc {
+	return func(opt *EncodeOption) {
+		opt.Flag |= encoder.BytesFormatOption
+		opt.BytesFormat = format
+	}
+}
+
+// EncodeNilSliceAsEmpty encodes nil slices as [] instead of null.
+// A nil []byte is encoded as an empty value of its BytesFormat, e.g. "".
+// Nil pointers to slices are still encoded as null.
+// The nilasempty tag option enables this for a single struct field, for both slices and maps.
+func EncodeNilSliceAsEmpty() EncodeOptionFunc {
+	return func(opt *EncodeOption) {
+		opt.Flag |= encoder.NilSliceAsEmptyOption
+	}
+}
+
+// EncodeNilMapAsEmpty encodes nil maps as {} instead of null.
+// Nil pointers to maps are still encoded as null.
+func EncodeNilMapAsEmpty() EncodeOptionFunc {
+	return func(opt *EncodeOption) {
+		opt.Flag |= encoder.NilMapAsEmptyOption
+	}
+}
+
+// EncodeRedact replaces sensitive values with the string returned by mask,
+// e.g. to keep credentials out of logs. Struct fields are sensitive if their tag has the sensitive option,
+// e.g. `json:"password,sensitive"`. Values of the types registered with RegisterSensitiveType are sensitive
+// wherever they appear: as struct fields, array elements, map values or the encoded value itself.
+// If mask is nil, the values are replaced with "***".
+//
+// mask is called with the JSONPath of the value, e.g. "$.users[*].password", where [*] stands for
+// any array element and .* for any map value. Values in interface values get their full path
+// from the root, and fields of recursive types get the path of the first occurrence of the type.
+//
+// A sensitive field is written with the mask even if it is empty or null, so the output does not tell
+// whether it was set. Only nil pointers are still omitted by omitempty.
+// The fields of a sensitive embedded struct are promoted and masked one by one.
+func EncodeRedact(mask func(path string) string) EncodeOptionFunc {
+	if mask == nil {
+		mask = func(string) string { return "***" }
+	}
+	return func(opt *EncodeOption) {
+		opt.Flag |= encoder.RedactOption
+		opt.Redact = mask
+	}
+}
+
+// EncodeTagKey reads struct tags with key, e.g. "api", instead of json.
+// Fields without a tag with key fall back to their json tag.
+// The code compiled for each key is cached apart from that of the json tags.
+func EncodeTagKey(key string) EncodeOptionFunc {
+	return func(opt *EncodeOption) {
+		opt.Flag |= encoder.TagKeyOption
+		opt.TagKey = key
+	}
+}
+
 type DecodeOption = decoder.Option
 type DecodeOptionFunc func(*DecodeOption)
 
@@ -77,3 +162,121 @@ func DecodeFieldPriorityFirstWin() DecodeOptionFunc {
 		opt.Flags |= decoder.FirstWinOption
 	}
 }
+
+// DecodeLimits bounds the resources used while decoding untrusted input.
+// A zero value for a field disables the corresponding limit.
+// MaxDepth can only lower the built-in nesting limit of 10000.
+type DecodeLimits = decoder.Limits
+
+// DecodeWithLimits enforces limits on nesting depth, string length, array length,
+// number of object keys and total input size while decoding.
+// When a limit is exceeded, decoding stops with a DepthLimitError, StringLimitError,
+// ArrayLimitError, ObjectKeysLimitError or BytesLimitError.
+func DecodeWithLimits(limits DecodeLimits) DecodeOptionFunc {
+	return func(opt *DecodeOption) {
+		opt.Flags |= decoder.LimitsOption
+		opt.Limits = limits
+	}
+}
+
+// DecodeRejectDuplicateKeys makes decoding fail with a DuplicateKeyError
+// when an object contains the same key more than once.
+// Keys are compared after unescaping, so "x" and "\u0078" are duplicates.
+// For struct targets, keys that select the same field (e.g. differing only in case) are duplicates.
+// Objects inside values that are skipped, e.g. because they match no struct field, are checked too.
+// Validate and ValidReader accept this option to check a document without decoding it.
+func DecodeRejectDuplicateKeys() DecodeOptionFunc {
+	return func(opt *DecodeOption) {
+		opt.Flags |= decoder.RejectDuplicateKeysOption
+	}
+}
+
+// DecodeCollectErrors keeps decoding when a value inside an object or array
+// cannot be assigned to its Go type. The value is skipped and decoding continues
+// with the next one; when decoding finishes, all failures are returned as DecodeErrors.
+// Syntax errors still stop decoding immediately.
+func DecodeCollectErrors() DecodeOptionFunc {
+	return func(opt *DecodeOption) {
+		opt.Flags |= decoder.CollectErrorsOption
+	}
+}
+
+// DecodeRejectNull makes decoding fail with an UnmarshalTypeError when an object contains null
+// for a struct field that cannot hold it, instead of leaving the field unchanged.
+// Only interface and Optional fields, fields whose type implements Unmarshaler and fields with the nullable
+// tag option accept null, e.g. `json:"parent,nullable"` for a pointer field that may be set to nil.
+func DecodeRejectNull() DecodeOptionFunc {
+	return func(opt *DecodeOption) {
+		opt.Flags |= decoder.RejectNullOption
+	}
+}
+
+// DecodeTagKey reads struct tags with key, e.g. "api", instead of json.
+// Fields without a tag with key fall back to their json tag.
+// The decoders compiled for each key are cached apart from those of the json tags.
+func DecodeTagKey(key string) DecodeOptionFunc {
+	return func(opt *DecodeOption) {
+		opt.Flags |= decoder.TagKeyOption
+		opt.TagKey = key
+	}
+}
+
+// LenientRules selects the coercions enabled by DecodeLenient.
+// Rules can be combined with the bitwise OR operator.
+type LenientRules = decoder.LenientRules
+
+const (
+	// LenientStringToNumber accepts a string containing a number for integer and float types, e.g. "42".
+	LenientStringToNumber = decoder.LenientStringToNumber
+	// LenientNumberToBool accepts 0 and 1 for bool types.
+	LenientNumberToBool = decoder.LenientNumberToBool
+	// LenientEmptyStringToNull treats "" like null for number and bool types, and pointers to them.
+	LenientEmptyStringToNull = decoder.LenientEmptyStringToNull
+	// LenientNumberToString accepts a number for string types and keeps its text, e.g. 42 as "42".
+	LenientNumberToString = decoder.LenientNumberToString
+)
+
+// DecodeLenient makes the scalar decoders accept the alternate forms of values selected by rules.
+// Values in their usual form are decoded as before.
+func DecodeLenient(rules LenientRules) DecodeOptionFunc {
+	return func(opt *DecodeOption) {
+		opt.Flags |= decoder.LenientOption
+		opt.Lenient = rules
+	}
+}
+
+// DecodeUseNumber makes numbers decoded into interface{} values become Number instead of float64,
+// like Decoder.UseNumber, but per call and also for Unmarshal.
+func DecodeUseNumber() DecodeOptionFunc {
+	return func(opt *DecodeOption) {
+		opt.Flags |= decoder.UseNumberOption
+	}
+}
+
+// DecodeIntegersAsInt64 makes integral numbers decoded into interface{} values become int64
+// when they fit into an int64. Other numbers are decoded as float64, or as Number with DecodeUseNumber.
+func DecodeIntegersAsInt64() DecodeOptionFunc {
+	return func(opt *DecodeOption) {
+		opt.Flags |= decoder.IntegersAsInt64Option
+	}
+}
+
+// DecodeBytesFormat sets the format of the strings decoded into []byte values,
+// except for struct fields whose tag selects a format.
+func DecodeBytesFormat(format BytesFormat) DecodeOptionFunc {
+	return func(opt *DecodeOption) {
+		opt.Flags |= decoder.BytesFormatOption
+		opt.BytesFormat = format
+	}
+}
+
+// DecodeValidateSchema validates the input against the JSON Schema s while decoding.
+// The input is validated in the same pass that decodes it, so the destination may be updated
+// even when the input does not conform to s. The failures are returned as schema.ValidationErrors,
+// also when they make decoding fail, e.g. for a string where the destination expects a number.
+func DecodeValidateSchema(s *schema.Schema) DecodeOptionFunc {
+	return func(opt *DecodeOption) {
+		opt.Flags |= decoder.SchemaOption
+		opt.Schema = s
+	}
+}
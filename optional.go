@@ -0,0 +1,53 @@
+package json
+
+import (
+	"github.com/goccy/go-json/internal/runtime"
+)
+
+// Optional is a value that tells an absent object member apart from a null one,
+// e.g. for the fields of a PATCH request body:
+//
+//	type UserPatch struct {
+//		Name  json.Optional[string] `json:"name"`
+//		Email json.Optional[string] `json:"email"`
+//	}
+//
+// When an Optional struct field is decoded, it becomes set if the key appears in the object,
+// and also null if its value is null. Fields whose key does not appear are left unchanged,
+// so they are not set in a zero value.
+//
+// An Optional struct field that is not set is omitted by the encoder, even without omitempty.
+// Elsewhere, an Optional that is not set or null is encoded as null.
+// The string tag option applies to the value of T if T is a string, number or bool type.
+//
+// Optional values are handled by the encoder and decoder directly, like the values of T,
+// so they do not have the cost of a MarshalJSON or UnmarshalJSON method.
+type Optional[T any] struct {
+	state runtime.OptionalState
+	value T
+}
+
+// NewOptional returns an Optional set to v.
+func NewOptional[T any](v T) Optional[T] {
+	return Optional[T]{state: runtime.OptionalSet, value: v}
+}
+
+// NullOptional returns an Optional set to null.
+func NullOptional[T any]() Optional[T] {
+	return Optional[T]{state: runtime.OptionalNull}
+}
+
+// IsSet reports whether o was set, to a value or to null.
+func (o Optional[T]) IsSet() bool {
+	return o.state != runtime.OptionalUnset
+}
+
+// IsNull reports whether o was set to null.
+func (o Optional[T]) IsNull() bool {
+	return o.state == runtime.OptionalNull
+}
+
+// Value returns the value of o, or the zero value of T if o is not set or null.
+func (o Optional[T]) Value() T {
+	return o.value
+}
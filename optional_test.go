@@ -0,0 +1,168 @@
+package json_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+)
+
+type optionalAddress struct {
+	City string `json:"city"`
+}
+
+type optionalPatch struct {
+	Name    json.Optional[string]            `json:"name"`
+	Age     json.Optional[int]               `json:"age"`
+	Address json.Optional[optionalAddress]   `json:"address"`
+	Tags    json.Optional[[]string]          `json:"tags"`
+	Labels  json.Optional[map[string]string] `json:"labels"`
+	Parent  json.Optional[*optionalAddress]  `json:"parent"`
+}
+
+func TestOptional(t *testing.T) {
+	t.Run("decode", func(t *testing.T) {
+		src := `{"name": "a", "age": null, "address": {"city": "b"}, "tags": ["c"], "labels": null}`
+		for _, decode := range []func(string, interface{}) error{
+			func(src string, v interface{}) error { return json.Unmarshal([]byte(src), v) },
+			func(src string, v interface{}) error { return json.NewDecoder(strings.NewReader(src)).Decode(v) },
+		} {
+			v := optionalPatch{Labels: json.NewOptional(map[string]string{"x": "y"})}
+			assertErr(t, decode(src, &v))
+			assertEq(t, "name set", true, v.Name.IsSet())
+			assertEq(t, "name null", false, v.Name.IsNull())
+			assertEq(t, "name", "a", v.Name.Value())
+			assertEq(t, "age set", true, v.Age.IsSet())
+			assertEq(t, "age null", true, v.Age.IsNull())
+			assertEq(t, "address", "b", v.Address.Value().City)
+			assertEq(t, "tags", true, reflect.DeepEqual([]string{"c"}, v.Tags.Value()))
+			assertEq(t, "labels null", true, v.Labels.IsNull())
+			assertEq(t, "labels", 0, len(v.Labels.Value()))
+			assertEq(t, "parent set", false, v.Parent.IsSet())
+		}
+	})
+	t.Run("encode", func(t *testing.T) {
+		v := optionalPatch{
+			Name:    json.NewOptional("a"),
+			Age:     json.NullOptional[int](),
+			Address: json.NewOptional(optionalAddress{City: "b"}),
+			Parent:  json.NewOptional(&optionalAddress{City: "c"}),
+		}
+		expected := `{"name":"a","age":null,"address":{"city":"b"},"parent":{"city":"c"}}`
+		b, err := json.Marshal(v)
+		assertErr(t, err)
+		assertEq(t, "json", expected, string(b))
+
+		b, err = json.Marshal(optionalPatch{Tags: json.NewOptional([]string{"x"}), Labels: json.NewOptional(map[string]string{"k": "v"})})
+		assertErr(t, err)
+		assertEq(t, "first fields unset", `{"tags":["x"],"labels":{"k":"v"}}`, string(b))
+
+		b, err = json.Marshal(optionalPatch{})
+		assertErr(t, err)
+		assertEq(t, "unset", `{}`, string(b))
+
+		b, err = json.Marshal(&optionalPatch{Parent: json.NullOptional[*optionalAddress]()})
+		assertErr(t, err)
+		assertEq(t, "pointer", `{"parent":null}`, string(b))
+
+		b, err = json.MarshalIndent(v, "", "  ")
+		assertErr(t, err)
+		var buf bytes.Buffer
+		assertErr(t, json.Indent(&buf, []byte(expected), "", "  "))
+		assertEq(t, "indent", buf.String(), string(b))
+
+		b, err = json.MarshalWithOption(v, json.Colorize(json.DefaultColorScheme))
+		assertErr(t, err)
+		assertEq(t, "color", expected, colorCode.ReplaceAllString(string(b), ""))
+	})
+	t.Run("values", func(t *testing.T) {
+		b, err := json.Marshal([]json.Optional[int]{json.NewOptional(1), json.NullOptional[int](), {}})
+		assertErr(t, err)
+		assertEq(t, "slice", `[1,null,null]`, string(b))
+
+		b, err = json.Marshal(map[string]json.Optional[string]{"a": json.NewOptional("x"), "b": {}})
+		assertErr(t, err)
+		assertEq(t, "map", `{"a":"x","b":null}`, string(b))
+
+		o := json.NewOptional(2)
+		b, err = json.Marshal(&o)
+		assertErr(t, err)
+		assertEq(t, "pointer", `2`, string(b))
+
+		var got []json.Optional[int]
+		assertErr(t, json.Unmarshal([]byte(`[1, null]`), &got))
+		assertEq(t, "decoded", true, reflect.DeepEqual([]json.Optional[int]{json.NewOptional(1), json.NullOptional[int]()}, got))
+	})
+	t.Run("errors", func(t *testing.T) {
+		var v optionalPatch
+		err := json.Unmarshal([]byte(`{"age": "1"}`), &v)
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			t.Fatalf("expected UnmarshalTypeError but got %v", err)
+		}
+		assertEq(t, "path", "$.age", typeErr.Path)
+		assertEq(t, "age set", false, v.Age.IsSet())
+
+		assertErr(t, json.UnmarshalWithOption([]byte(`{"name": null}`), &v, json.DecodeRejectNull()))
+		assertEq(t, "name null", true, v.Name.IsNull())
+	})
+	t.Run("string option", func(t *testing.T) {
+		type T struct {
+			Int    json.Optional[int]         `json:"int,string"`
+			Uint   json.Optional[uint8]       `json:"uint,string"`
+			Float  json.Optional[float64]     `json:"float,string"`
+			Bool   json.Optional[bool]        `json:"bool,string"`
+			String json.Optional[string]      `json:"string,string"`
+			Number json.Optional[json.Number] `json:"number,string"`
+			Null   json.Optional[int]         `json:"null,string"`
+			Slice  json.Optional[[]int]       `json:"slice,string"`
+		}
+		v := T{
+			Int:    json.NewOptional(4),
+			Uint:   json.NewOptional(uint8(5)),
+			Float:  json.NewOptional(1.5),
+			Bool:   json.NewOptional(true),
+			String: json.NewOptional("x"),
+			Number: json.NewOptional(json.Number("12")),
+			Null:   json.NullOptional[int](),
+			Slice:  json.NewOptional([]int{1}),
+		}
+		const expected = `{"int":"4","uint":"5","float":"1.5","bool":"true","string":"\"x\"","number":"12","null":null,"slice":[1]}`
+		b, err := json.Marshal(v)
+		assertErr(t, err)
+		assertEq(t, "encode", expected, string(b))
+		b, err = json.MarshalIndent(v, "", "")
+		assertErr(t, err)
+		assertEq(t, "indent", expected, strings.NewReplacer("\n", "", ": ", ":").Replace(string(b)))
+
+		var got T
+		assertErr(t, json.Unmarshal([]byte(expected), &got))
+		if !reflect.DeepEqual(v, got) {
+			t.Fatalf("failed to decode. exp=[%#v] but act=[%#v]", v, got)
+		}
+		got = T{}
+		assertErr(t, json.NewDecoder(strings.NewReader(expected)).Decode(&got))
+		if !reflect.DeepEqual(v, got) {
+			t.Fatalf("failed to decode stream. exp=[%#v] but act=[%#v]", v, got)
+		}
+	})
+	t.Run("schema", func(t *testing.T) {
+		b, err := json.SchemaFor(reflect.TypeOf(optionalPatch{}))
+		assertErr(t, err)
+		var s struct {
+			Defs map[string]struct {
+				Required []string `json:"required"`
+			} `json:"$defs"`
+		}
+		assertErr(t, json.Unmarshal(b, &s))
+		if required := s.Defs["optionalPatch"].Required; len(required) != 0 {
+			t.Errorf("schema %s requires Optional fields %v", b, required)
+		}
+		if !strings.Contains(string(b), `"age":{"type":["integer","null"]}`) {
+			t.Errorf("schema %s does not describe age as a nullable integer", b)
+		}
+	})
+}
@@ -0,0 +1,493 @@
+package schema
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json/internal/decoder"
+)
+
+type typeSet uint8
+
+const (
+	typeNull typeSet = 1 << iota
+	typeBoolean
+	typeObject
+	typeArray
+	typeNumber
+	typeString
+	typeInteger
+)
+
+var typeNames = map[string]typeSet{
+	"null":    typeNull,
+	"boolean": typeBoolean,
+	"object":  typeObject,
+	"array":   typeArray,
+	"number":  typeNumber,
+	"string":  typeString,
+	"integer": typeInteger,
+}
+
+// node is a compiled schema.
+type node struct {
+	location string // JSON pointer to the schema in the document
+
+	isBool    bool
+	boolValue bool
+
+	ref     string
+	refNode *node
+
+	types      typeSet
+	enum       []interface{}
+	hasEnum    bool
+	constValue interface{}
+	hasConst   bool
+
+	multipleOf       number
+	maximum          number
+	exclusiveMaximum number
+	minimum          number
+	exclusiveMinimum number
+
+	maxLength int64
+	minLength int64
+	pattern   *regexp.Regexp
+
+	maxItems    int64
+	minItems    int64
+	uniqueItems bool
+	maxContains int64
+	minContains int64
+
+	maxProperties     int64
+	minProperties     int64
+	required          []string
+	dependentRequired []*dependency
+
+	allOf            []*node
+	anyOf            []*node
+	oneOf            []*node
+	not              *node
+	ifNode           *node
+	thenNode         *node
+	elseNode         *node
+	dependentSchemas []*dependency
+
+	prefixItems []*node
+	items       *node
+	contains    *node
+
+	properties           map[string]*node
+	patternProperties    []*patternProperty
+	additionalProperties *node
+	propertyNames        *node
+
+	unevaluatedItems      *node
+	unevaluatedProperties *node
+}
+
+// dependency is an entry of dependentRequired or dependentSchemas,
+// which applies when the object has the property key.
+type dependency struct {
+	key      string
+	required []string
+	node     *node
+}
+
+type patternProperty struct {
+	re   *regexp.Regexp
+	node *node
+}
+
+// needKeys reports whether validating an object against n needs the set of its keys.
+func (n *node) needKeys() bool {
+	return len(n.required) > 0 || len(n.dependentRequired) > 0 || len(n.dependentSchemas) > 0
+}
+
+// needEvaluated reports whether n needs to know which properties and items were evaluated by its subschemas.
+func (n *node) needEvaluated() bool {
+	return n.unevaluatedItems != nil || n.unevaluatedProperties != nil
+}
+
+type compiler struct {
+	nodes map[string]*node // compiled schemas by URI
+	refs  []*pendingRef
+}
+
+type pendingRef struct {
+	node *node
+	key  string
+}
+
+func compile(data []byte) (*node, error) {
+	buf := make([]byte, len(data)+1) // append nul byte to the end
+	copy(buf, data)
+	s := &decoder.Scanner{Buf: buf}
+	doc, err := parseValue(s, 0)
+	if err != nil {
+		return nil, err
+	}
+	if c := s.Next(); c != 0 {
+		return nil, fmt.Errorf("json: invalid character %q after schema document", c)
+	}
+	c := &compiler{nodes: map[string]*node{}}
+	root, err := c.compile(doc, &url.URL{}, "", "")
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range c.refs {
+		n, exists := c.nodes[ref.key]
+		if !exists {
+			return nil, fmt.Errorf("json: unresolved $ref %q in schema at %q", ref.node.ref, ref.node.location)
+		}
+		ref.node.refNode = n
+	}
+	return root, nil
+}
+
+func schemaError(location, format string, args ...interface{}) error {
+	return fmt.Errorf("json: invalid schema at %q: %s", location, fmt.Sprintf(format, args...))
+}
+
+func escapePointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// uriKey returns the key of the schema at fragment in the resource base in compiler.nodes.
+func uriKey(base *url.URL, fragment string) string {
+	u := *base
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String() + "#" + fragment
+}
+
+// compile compiles the schema v found at the JSON pointer location of the document.
+// base is the URI of the enclosing schema resource and pointer the location of v in that resource.
+func (c *compiler) compile(v interface{}, base *url.URL, location, pointer string) (*node, error) {
+	n := &node{
+		location:      location,
+		maxLength:     -1,
+		minLength:     -1,
+		maxItems:      -1,
+		minItems:      -1,
+		maxContains:   -1,
+		minContains:   -1,
+		maxProperties: -1,
+		minProperties: -1,
+	}
+	if b, ok := v.(bool); ok {
+		n.isBool = true
+		n.boolValue = b
+		c.nodes[uriKey(base, pointer)] = n
+		return n, nil
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, schemaError(location, "schema must be an object or a boolean")
+	}
+	c.nodes[uriKey(base, pointer)] = n
+	if id, exists := obj["$id"]; exists {
+		s, ok := id.(string)
+		if !ok {
+			return nil, schemaError(location, "$id must be a string")
+		}
+		u, err := url.Parse(s)
+		if err != nil {
+			return nil, schemaError(location, "invalid $id %q", s)
+		}
+		base = base.ResolveReference(u)
+		pointer = ""
+		c.nodes[uriKey(base, "")] = n
+	}
+	for _, keyword := range []string{"$anchor", "$dynamicAnchor"} {
+		if anchor, exists := obj[keyword]; exists {
+			s, ok := anchor.(string)
+			if !ok {
+				return nil, schemaError(location, "%s must be a string", keyword)
+			}
+			c.nodes[uriKey(base, s)] = n
+		}
+	}
+	for _, keyword := range []string{"$ref", "$dynamicRef"} {
+		if ref, exists := obj[keyword]; exists {
+			s, ok := ref.(string)
+			if !ok {
+				return nil, schemaError(location, "%s must be a string", keyword)
+			}
+			u, err := url.Parse(s)
+			if err != nil {
+				return nil, schemaError(location, "invalid %s %q", keyword, s)
+			}
+			n.ref = s
+			u = base.ResolveReference(u)
+			c.refs = append(c.refs, &pendingRef{node: n, key: uriKey(u, u.Fragment)})
+		}
+	}
+
+	sub := func(keyword string, value interface{}, tokens ...string) (*node, error) {
+		suffix := "/" + escapePointer(keyword)
+		for _, token := range tokens {
+			suffix += "/" + escapePointer(token)
+		}
+		return c.compile(value, base, location+suffix, pointer+suffix)
+	}
+	subList := func(keyword string) ([]*node, error) {
+		value, exists := obj[keyword]
+		if !exists {
+			return nil, nil
+		}
+		list, ok := value.([]interface{})
+		if !ok || len(list) == 0 {
+			return nil, schemaError(location, "%s must be a non-empty array", keyword)
+		}
+		nodes := make([]*node, 0, len(list))
+		for i, v := range list {
+			n, err := sub(keyword, v, strconv.Itoa(i))
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+		}
+		return nodes, nil
+	}
+	subMap := func(keyword string) (map[string]*node, error) {
+		value, exists := obj[keyword]
+		if !exists {
+			return nil, nil
+		}
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, schemaError(location, "%s must be an object", keyword)
+		}
+		nodes := make(map[string]*node, len(m))
+		for _, k := range sortedKeys(m) {
+			n, err := sub(keyword, m[k], k)
+			if err != nil {
+				return nil, err
+			}
+			nodes[k] = n
+		}
+		return nodes, nil
+	}
+	single := func(keyword string) (*node, error) {
+		value, exists := obj[keyword]
+		if !exists {
+			return nil, nil
+		}
+		return sub(keyword, value)
+	}
+
+	var err error
+	for _, keyword := range []string{"$defs", "definitions"} {
+		if _, err = subMap(keyword); err != nil {
+			return nil, err
+		}
+	}
+	if n.allOf, err = subList("allOf"); err != nil {
+		return nil, err
+	}
+	if n.anyOf, err = subList("anyOf"); err != nil {
+		return nil, err
+	}
+	if n.oneOf, err = subList("oneOf"); err != nil {
+		return nil, err
+	}
+	if n.prefixItems, err = subList("prefixItems"); err != nil {
+		return nil, err
+	}
+	for keyword, dst := range map[string]**node{
+		"not":                   &n.not,
+		"if":                    &n.ifNode,
+		"then":                  &n.thenNode,
+		"else":                  &n.elseNode,
+		"items":                 &n.items,
+		"contains":              &n.contains,
+		"additionalProperties":  &n.additionalProperties,
+		"propertyNames":         &n.propertyNames,
+		"unevaluatedItems":      &n.unevaluatedItems,
+		"unevaluatedProperties": &n.unevaluatedProperties,
+	} {
+		if *dst, err = single(keyword); err != nil {
+			return nil, err
+		}
+	}
+	if n.ifNode == nil {
+		n.thenNode, n.elseNode = nil, nil
+	}
+	dependentSchemas, err := subMap("dependentSchemas")
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range sortedKeys(dependentSchemas) {
+		n.dependentSchemas = append(n.dependentSchemas, &dependency{key: key, node: dependentSchemas[key]})
+	}
+	if n.properties, err = subMap("properties"); err != nil {
+		return nil, err
+	}
+	patterns, err := subMap("patternProperties")
+	if err != nil {
+		return nil, err
+	}
+	for _, pattern := range sortedKeys(patterns) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, schemaError(location, "invalid pattern property %q: %s", pattern, err)
+		}
+		n.patternProperties = append(n.patternProperties, &patternProperty{re: re, node: patterns[pattern]})
+	}
+	if err := n.compileAssertions(obj); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// compileAssertions compiles the keywords of obj that do not contain subschemas.
+func (n *node) compileAssertions(obj map[string]interface{}) error {
+	if value, exists := obj["type"]; exists {
+		names, ok := value.([]interface{})
+		if !ok {
+			names = []interface{}{value}
+		}
+		for _, name := range names {
+			s, _ := name.(string)
+			typ, ok := typeNames[s]
+			if !ok {
+				return schemaError(n.location, "invalid type %v", name)
+			}
+			n.types |= typ
+		}
+	}
+	if value, exists := obj["enum"]; exists {
+		values, ok := value.([]interface{})
+		if !ok {
+			return schemaError(n.location, "enum must be an array")
+		}
+		n.enum = values
+		n.hasEnum = true
+	}
+	if value, exists := obj["const"]; exists {
+		n.constValue = value
+		n.hasConst = true
+	}
+	for keyword, dst := range map[string]*number{
+		"multipleOf":       &n.multipleOf,
+		"maximum":          &n.maximum,
+		"exclusiveMaximum": &n.exclusiveMaximum,
+		"minimum":          &n.minimum,
+		"exclusiveMinimum": &n.exclusiveMinimum,
+	} {
+		value, exists := obj[keyword]
+		if !exists {
+			continue
+		}
+		num, ok := value.(number)
+		if !ok {
+			return schemaError(n.location, "%s must be a number", keyword)
+		}
+		*dst = num
+	}
+	if n.multipleOf != "" && compareNumbers(n.multipleOf, "0") <= 0 {
+		return schemaError(n.location, "multipleOf must be greater than 0")
+	}
+	for keyword, dst := range map[string]*int64{
+		"maxLength":     &n.maxLength,
+		"minLength":     &n.minLength,
+		"maxItems":      &n.maxItems,
+		"minItems":      &n.minItems,
+		"maxContains":   &n.maxContains,
+		"minContains":   &n.minContains,
+		"maxProperties": &n.maxProperties,
+		"minProperties": &n.minProperties,
+	} {
+		value, exists := obj[keyword]
+		if !exists {
+			continue
+		}
+		num, ok := value.(number)
+		if !ok || !num.isInteger() || compareNumbers(num, "0") < 0 {
+			return schemaError(n.location, "%s must be a non-negative integer", keyword)
+		}
+		r, _ := num.rat()
+		if r == nil || !r.Num().IsInt64() {
+			return schemaError(n.location, "%s is too large", keyword)
+		}
+		*dst = r.Num().Int64()
+	}
+	if value, exists := obj["pattern"]; exists {
+		s, ok := value.(string)
+		if !ok {
+			return schemaError(n.location, "pattern must be a string")
+		}
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return schemaError(n.location, "invalid pattern %q: %s", s, err)
+		}
+		n.pattern = re
+	}
+	if value, exists := obj["uniqueItems"]; exists {
+		b, ok := value.(bool)
+		if !ok {
+			return schemaError(n.location, "uniqueItems must be a boolean")
+		}
+		n.uniqueItems = b
+	}
+	if value, exists := obj["required"]; exists {
+		required, err := stringList(value)
+		if err != nil {
+			return schemaError(n.location, "required %s", err)
+		}
+		n.required = required
+	}
+	if value, exists := obj["dependentRequired"]; exists {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return schemaError(n.location, "dependentRequired must be an object")
+		}
+		for _, k := range sortedKeys(m) {
+			required, err := stringList(m[k])
+			if err != nil {
+				return schemaError(n.location, "dependentRequired %q %s", k, err)
+			}
+			n.dependentRequired = append(n.dependentRequired, &dependency{key: k, required: required})
+		}
+	}
+	return nil
+}
+
+func stringList(v interface{}) ([]string, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("must be an array of strings")
+	}
+	strs := make([]string, 0, len(list))
+	for _, v := range list {
+		s, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be an array of strings")
+		}
+		strs = append(strs, s)
+	}
+	return strs, nil
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	switch m := m.(type) {
+	case map[string]interface{}:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]*node:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,101 @@
+// Package schema validates JSON documents against JSON Schema (draft 2020-12).
+//
+// A schema is compiled once with Compile and can then validate any number of documents
+// from multiple goroutines. Validation scans the document with the decoder's scanner
+// without building Go values for it, and reports every failure with the JSON path
+// of the failing value:
+//
+//	s, err := schema.Compile([]byte(`{"type": "object", "required": ["name"]}`))
+//	if err != nil {
+//		...
+//	}
+//	if err := s.Validate(data); err != nil {
+//		...
+//	}
+//
+// To validate a document while decoding it, pass the schema to json.DecodeValidateSchema;
+// the decoders then report what they read to the schema, so the document is read only once.
+//
+// References with $ref and $dynamicRef are resolved within the schema document,
+// using $id, $anchor and $dynamicAnchor; $dynamicRef is resolved like $ref.
+// The format keyword is treated as an annotation and patterns use the syntax of the regexp package.
+package schema
+
+import (
+	"github.com/goccy/go-json/internal/decoder"
+	"github.com/goccy/go-json/internal/errors"
+)
+
+// ValidationError describes a value that does not conform to the schema.
+// Path is the JSON path of the value and KeywordLocation the location of the failed keyword in the schema.
+type ValidationError = errors.SchemaError
+
+// ValidationErrors is returned when a document does not conform to the schema.
+// It lists every failure in input order.
+type ValidationErrors = errors.SchemaErrors
+
+// Schema is a compiled JSON Schema.
+type Schema struct {
+	root *node
+}
+
+// Compile compiles the JSON Schema document data.
+func Compile(data []byte) (*Schema, error) {
+	root, err := compile(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{root: root}, nil
+}
+
+// MustCompile is like Compile but panics if the schema cannot be compiled.
+func MustCompile(data []byte) *Schema {
+	s, err := Compile(data)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Validate validates the JSON document data against the schema.
+// If the document does not conform to the schema, it returns ValidationErrors.
+// Syntax errors in the document are returned as *json.SyntaxError.
+func (s *Schema) Validate(data []byte) error {
+	buf := make([]byte, len(data)+1) // append nul byte to the end
+	copy(buf, data)
+	cursor, err := s.ValidateBuffer(buf, 0)
+	if err == nil {
+		sc := &decoder.Scanner{Buf: buf, Cursor: cursor}
+		if c := sc.Next(); c != 0 || sc.Cursor != int64(len(data)) {
+			err = errors.ErrAfterTopLevelValue(c, sc.Cursor)
+		}
+	}
+	if errs, ok := err.(errors.SchemaErrors); ok {
+		for _, e := range errs {
+			errors.Locate(e, data, 0, 1, 0)
+		}
+		return errs
+	}
+	return errors.Locate(err, data, 0, 1, 0)
+}
+
+// ValidateBuffer validates the JSON value at cursor in buf, which must end with a nul byte,
+// and returns the cursor after the value. It is used by json.DecodeValidateSchema;
+// most callers should use Validate instead.
+func (s *Schema) ValidateBuffer(buf []byte, cursor int64) (int64, error) {
+	v := newValidation(s.root)
+	sc := &decoder.Scanner{Buf: buf, Cursor: cursor}
+	if err := v.walk(sc, 0); err != nil {
+		return 0, err
+	}
+	if len(v.errs) > 0 {
+		return sc.Cursor, v.errs
+	}
+	return sc.Cursor, nil
+}
+
+// NewValidation returns the validation of a value read by the decoders.
+// It is used by json.DecodeValidateSchema to validate the input while decoding it.
+func (s *Schema) NewValidation() decoder.SchemaValidation {
+	return newValidation(s.root)
+}
@@ -0,0 +1,234 @@
+package schema_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/goccy/go-json/schema"
+)
+
+func validationErrors(t *testing.T, err error) schema.ValidationErrors {
+	t.Helper()
+	var errs schema.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	return errs
+}
+
+func TestValidate(t *testing.T) {
+	s := schema.MustCompile([]byte(`{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["name", "items"],
+  "properties": {
+    "name": {"type": "string", "minLength": 1, "maxLength": 8},
+    "age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 150},
+    "price": {"type": "number", "multipleOf": 0.01},
+    "tags": {"type": "array", "items": {"type": "string", "pattern": "^[a-z]+$"}, "uniqueItems": true},
+    "items": {"type": "array", "prefixItems": [{"const": "head"}], "items": {"$ref": "#/$defs/item"}, "minItems": 1},
+    "kind": {"enum": ["a", "b", 1]}
+  },
+  "additionalProperties": false,
+  "$defs": {
+    "item": {"type": "object", "properties": {"id": {"type": "integer"}}, "required": ["id"]}
+  }
+}`))
+	for _, tc := range []struct {
+		name string
+		src  string
+		errs []string // "path keyword"
+	}{
+		{
+			name: "valid",
+			src:  `{"name":"gopher","age":10,"price":1.25,"tags":["a","b"],"items":["head",{"id":1},{"id":2.0}],"kind":1.0}`,
+		},
+		{
+			name: "type",
+			src:  `[]`,
+			errs: []string{"$ type"},
+		},
+		{
+			name: "nested",
+			src:  `{"name":"","age":150,"price":0.125,"tags":["a","B","a"],"items":["tail",{"id":"x"},{}],"kind":"c","extra":null}`,
+			errs: []string{
+				"$.name minLength",
+				"$.age exclusiveMaximum",
+				"$.price multipleOf",
+				"$.tags[1] pattern",
+				"$.tags[2] uniqueItems",
+				"$.items[0] const",
+				"$.items[1].id type",
+				"$.items[2] required",
+				"$.kind enum",
+				"$.extra false",
+			},
+		},
+		{
+			name: "required",
+			src:  `{"name":"gopher"}`,
+			errs: []string{"$ required"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := s.Validate([]byte(tc.src))
+			if len(tc.errs) == 0 {
+				assertErr(t, err)
+				return
+			}
+			errs := validationErrors(t, err)
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Path+" "+e.Keyword)
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.errs, "\n") {
+				t.Fatalf("unexpected errors:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(tc.errs, "\n"))
+			}
+		})
+	}
+}
+
+func TestValidateApplicators(t *testing.T) {
+	for _, tc := range []struct {
+		schema  string
+		valid   []string
+		invalid []string
+	}{
+		{
+			schema:  `{"anyOf":[{"type":"string"},{"type":"integer"}]}`,
+			valid:   []string{`"a"`, `1`},
+			invalid: []string{`1.5`, `null`},
+		},
+		{
+			schema:  `{"oneOf":[{"type":"integer"},{"minimum":2}]}`,
+			valid:   []string{`1`, `2.5`},
+			invalid: []string{`3`, `1.5`},
+		},
+		{
+			schema:  `{"not":{"type":"null"}}`,
+			valid:   []string{`0`},
+			invalid: []string{`null`},
+		},
+		{
+			schema:  `{"if":{"properties":{"a":{"const":1}}},"then":{"required":["b"]},"else":{"required":["c"]}}`,
+			valid:   []string{`{"a":1,"b":0}`, `{"a":2,"c":0}`},
+			invalid: []string{`{"a":1}`, `{"a":2,"b":0}`},
+		},
+		{
+			schema:  `{"dependentRequired":{"a":["b"]},"dependentSchemas":{"c":{"maxProperties":1}}}`,
+			valid:   []string{`{"b":0}`, `{"a":0,"b":0}`, `{"c":0}`},
+			invalid: []string{`{"a":0}`, `{"c":0,"d":0}`},
+		},
+		{
+			schema:  `{"contains":{"type":"integer"},"minContains":2,"maxContains":3}`,
+			valid:   []string{`[1,"a",2]`, `[1,2,3]`},
+			invalid: []string{`[1,"a"]`, `[1,2,3,4]`},
+		},
+		{
+			schema:  `{"propertyNames":{"maxLength":2},"patternProperties":{"^x":{"type":"integer"}},"minProperties":1}`,
+			valid:   []string{`{"x1":1,"y":"a"}`},
+			invalid: []string{`{}`, `{"abc":1}`, `{"x":"a"}`},
+		},
+		{
+			schema:  `{"allOf":[{"properties":{"a":true}}],"anyOf":[{"properties":{"b":true}},{"properties":{"c":true}}],"unevaluatedProperties":false}`,
+			valid:   []string{`{"a":1,"b":2,"c":3}`},
+			invalid: []string{`{"a":1,"d":4}`},
+		},
+		{
+			schema:  `{"prefixItems":[true],"contains":{"type":"string"},"unevaluatedItems":{"type":"integer"}}`,
+			valid:   []string{`[null,"a",1]`},
+			invalid: []string{`[null,"a",true]`},
+		},
+		{
+			schema:  `{"$id":"https://example.com/tree","type":"object","properties":{"children":{"type":"array","items":{"$ref":"tree"}},"value":{"$ref":"#node"}},"$defs":{"node":{"$anchor":"node","type":"integer"}}}`,
+			valid:   []string{`{"value":1,"children":[{"value":2,"children":[]}]}`},
+			invalid: []string{`{"children":[{"value":"x"}]}`},
+		},
+		{
+			schema:  `{"type":["integer","null"],"maximum":1e400}`,
+			valid:   []string{`null`, `1e300`, `2.0`},
+			invalid: []string{`1e401`, `"1"`, `1.5`},
+		},
+		{
+			schema:  `false`,
+			invalid: []string{`{}`},
+		},
+	} {
+		s, err := schema.Compile([]byte(tc.schema))
+		assertErr(t, err)
+		for _, src := range tc.valid {
+			if err := s.Validate([]byte(src)); err != nil {
+				t.Errorf("%s: expected %s to be valid: %v", tc.schema, src, err)
+			}
+		}
+		for _, src := range tc.invalid {
+			err := s.Validate([]byte(src))
+			var errs schema.ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Errorf("%s: expected %s to be invalid, got %v", tc.schema, src, err)
+			}
+		}
+	}
+}
+
+func TestValidateSyntaxError(t *testing.T) {
+	s := schema.MustCompile([]byte(`{"type":"object"}`))
+	for _, src := range []string{`{"a":1,}`, `{"a" 1}`, `[1 2]`, `{"a":01}`, `{"a":"\x"}`, `{} {}`, `{"a":tru}`} {
+		var syntaxErr *json.SyntaxError
+		if err := s.Validate([]byte(src)); !errors.As(err, &syntaxErr) {
+			t.Errorf("%s: expected SyntaxError, got %v", src, err)
+		}
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	for _, src := range []string{
+		`1`,
+		`{"type":"text"}`,
+		`{"minLength":-1}`,
+		`{"pattern":"("}`,
+		`{"$ref":"#/$defs/missing"}`,
+		`{"allOf":[]}`,
+	} {
+		if _, err := schema.Compile([]byte(src)); err == nil {
+			t.Errorf("%s: expected compile error", src)
+		}
+	}
+}
+
+func TestValidationErrorLocation(t *testing.T) {
+	s := schema.MustCompile([]byte(`{"properties":{"list":{"items":{"type":"string"}}}}`))
+	errs := validationErrors(t, s.Validate([]byte("{\n  \"list\": [\"a\", 2]\n}")))
+	if len(errs) != 1 {
+		t.Fatalf("expected one error, got %v", errs)
+	}
+	e := errs[0]
+	assertEq(t, "path", "$.list[1]", e.Path)
+	assertEq(t, "keyword location", "/properties/list/items/type", e.KeywordLocation)
+	assertEq(t, "line", int64(2), e.Line)
+	assertEq(t, "column", int64(17), e.Column)
+	assertEq(t, "error", "json: schema validation failed at $.list[1]: expected string, but got number", e.Error())
+}
+
+func TestInfiniteRef(t *testing.T) {
+	s := schema.MustCompile([]byte(`{"$ref":"#"}`))
+	if err := s.Validate([]byte(`{}`)); err == nil {
+		t.Fatal("expected error for recursive $ref")
+	}
+}
+
+func assertErr(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%+v", err)
+	}
+}
+
+func assertEq(t *testing.T, msg string, exp interface{}, act interface{}) {
+	t.Helper()
+	if exp != act {
+		t.Fatalf("failed to test for %s. exp=[%v] but act=[%v]", msg, exp, act)
+	}
+}
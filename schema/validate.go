@@ -0,0 +1,913 @@
+package schema
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/goccy/go-json/internal/decoder"
+	"github.com/goccy/go-json/internal/errors"
+)
+
+// maxRefChain is the maximum number of $ref applied to the same value.
+// It stops schemas that reference themselves without descending into the value.
+const maxRefChain = 1000
+
+// path is the JSON path of a validated value, linked from the value to the root.
+type path struct {
+	parent *path
+	key    string
+	index  int
+	isKey  bool
+}
+
+// evaluated records the properties and items evaluated by the subschemas applied to a value.
+// It is only collected for schemas with unevaluatedProperties or unevaluatedItems.
+type evaluated struct {
+	props    map[string]struct{}
+	items    int // items before this index were evaluated
+	allItems bool
+	matched  map[int]struct{} // items that matched contains
+}
+
+func newEvaluated() *evaluated {
+	return &evaluated{props: map[string]struct{}{}, matched: map[int]struct{}{}}
+}
+
+func (e *evaluated) merge(src *evaluated) {
+	for k := range src.props {
+		e.props[k] = struct{}{}
+	}
+	for i := range src.matched {
+		e.matched[i] = struct{}{}
+	}
+	if src.items > e.items {
+		e.items = src.items
+	}
+	e.allItems = e.allItems || src.allItems
+}
+
+func (e *evaluated) item(i int) bool {
+	if e.allItems || i < e.items {
+		return true
+	}
+	_, exists := e.matched[i]
+	return exists
+}
+
+// validation validates a JSON value against a schema from the tokens of the value, in input order.
+// The tokens are read from a buffer by walk or reported by the decoders through the
+// decoder.SchemaValidation methods, so the value is read only once in both cases.
+// All the subschemas that apply to a value are evaluated side by side while it is read.
+// Syntax errors and recursive schemas stop the validation and are kept in err;
+// schema violations are collected in errs.
+type validation struct {
+	root   *node
+	frames []*frame // values being read, from the document down to the current value
+	errs   errors.SchemaErrors
+	err    error
+}
+
+func newValidation(root *node) *validation {
+	return &validation{root: root, frames: []*frame{{start: -1, done: -1}}}
+}
+
+// frame is a value being read and the instances validating it.
+type frame struct {
+	kind  byte // first byte of the value, '0' for numbers, or 0 for the document holding the value
+	start int64
+	p     *path
+	insts []*inst
+	isKey bool // the value is an object key validated against propertyNames
+
+	// value is the value built for const, enum and uniqueItems, see parseValue.
+	value interface{}
+	build bool
+
+	// the current member of an object or array
+	key     string
+	memberP *path
+	index   int   // number of elements read
+	done    int64 // cursor of the member ended with End, or -1
+}
+
+// role describes how the instance validating a member is used by the instance of the object or array.
+type role uint8
+
+const (
+	roleMember role = iota // properties, patternProperties, additionalProperties, prefixItems and items
+	roleContains
+	roleUnevaluated
+	rolePropertyName
+)
+
+// inst validates the value of a frame against a node.
+type inst struct {
+	n        *node
+	role     role
+	ev       *evaluated // nil if n and its owner do not need the evaluated properties and items
+	refChain int
+	errs     errors.SchemaErrors
+
+	// subschemas applied to the same value
+	ref      *inst
+	allOf    []*inst
+	anyOf    []*inst
+	oneOf    []*inst
+	not      *inst
+	ifInst   *inst
+	thenInst *inst
+	elseInst *inst
+	deps     []*inst // dependentSchemas, for objects
+
+	members     []*inst // instances validating the current member
+	keys        map[string]struct{}
+	count       int64 // number of properties
+	contains    int64
+	values      []interface{} // items read for uniqueItems
+	unevaluated []*unevaluatedMember
+}
+
+// unevaluatedMember is a member validated against unevaluatedProperties or unevaluatedItems,
+// whose failures are kept if no other keyword evaluates it.
+type unevaluatedMember struct {
+	key   string
+	index int
+	errs  errors.SchemaErrors
+}
+
+func (i *inst) fail(keyword string, p *path, offset int64, format string, args ...interface{}) {
+	location := i.n.location
+	if !i.n.isBool {
+		location += "/" + keyword
+	}
+	err := &errors.SchemaError{
+		Keyword:         keyword,
+		KeywordLocation: location,
+		Message:         fmt.Sprintf(format, args...),
+		Offset:          offset,
+	}
+	for ; p != nil; p = p.parent {
+		if p.isKey {
+			errors.PrependPathKey(err, p.key)
+		} else {
+			errors.PrependPathIndex(err, p.index)
+		}
+	}
+	i.errs = append(i.errs, err)
+}
+
+func (i *inst) valid() bool {
+	return len(i.errs) == 0
+}
+
+// apply adds the failures and the evaluated properties and items of the subschema instance sub.
+func (i *inst) apply(sub *inst) {
+	i.errs = append(i.errs, sub.errs...)
+	i.merge(sub)
+}
+
+func (i *inst) merge(sub *inst) {
+	if i.ev != nil && sub.ev != nil {
+		i.ev.merge(sub.ev)
+	}
+}
+
+func (v *validation) top() *frame {
+	return v.frames[len(v.frames)-1]
+}
+
+// add adds the instance validating the value of f against n.
+// track reports whether the caller needs the properties and items evaluated by n.
+func (v *validation) add(f *frame, n *node, track bool, refChain int) *inst {
+	i := &inst{n: n, refChain: refChain}
+	if track || n.needEvaluated() {
+		i.ev = newEvaluated()
+	}
+	if f.kind == '{' && n.needKeys() {
+		i.keys = map[string]struct{}{}
+	}
+	f.insts = append(f.insts, i)
+	v.expand(f, i)
+	return i
+}
+
+// expand adds the instances of the subschemas of i that apply to the value itself.
+func (v *validation) expand(f *frame, i *inst) {
+	n := i.n
+	if n.isBool || v.err != nil {
+		return
+	}
+	track := i.ev != nil
+	if n.refNode != nil {
+		if i.refChain >= maxRefChain {
+			v.err = fmt.Errorf("json: schema $ref %q at %q is infinitely recursive", n.ref, n.location)
+			return
+		}
+		i.ref = v.add(f, n.refNode, track, i.refChain+1)
+	}
+	for _, sub := range n.allOf {
+		i.allOf = append(i.allOf, v.add(f, sub, track, i.refChain))
+	}
+	for _, sub := range n.anyOf {
+		i.anyOf = append(i.anyOf, v.add(f, sub, track, i.refChain))
+	}
+	for _, sub := range n.oneOf {
+		i.oneOf = append(i.oneOf, v.add(f, sub, track, i.refChain))
+	}
+	if n.not != nil {
+		i.not = v.add(f, n.not, false, i.refChain)
+	}
+	if n.ifNode != nil {
+		i.ifInst = v.add(f, n.ifNode, track, i.refChain)
+		if n.thenNode != nil {
+			i.thenInst = v.add(f, n.thenNode, track, i.refChain)
+		}
+		if n.elseNode != nil {
+			i.elseInst = v.add(f, n.elseNode, track, i.refChain)
+		}
+	}
+	if f.kind == '{' {
+		for _, dep := range n.dependentSchemas {
+			i.deps = append(i.deps, v.add(f, dep.node, track, i.refChain))
+		}
+	}
+}
+
+// member adds the instance validating the current member of owner against n.
+func (v *validation) member(f *frame, owner *inst, n *node, r role) {
+	i := v.add(f, n, false, 0)
+	i.role = r
+	owner.members = append(owner.members, i)
+}
+
+// open starts reading the value at start whose first byte is kind,
+// which is the top-level value or the current member of the current value.
+func (v *validation) open(kind byte, start int64) {
+	parent := v.top()
+	f := &frame{kind: kind, start: start, build: parent.build, done: -1}
+	switch parent.kind {
+	case 0:
+		v.add(f, v.root, false, 0)
+	case '{':
+		f.p = parent.memberP
+	case '[':
+		f.p = &path{parent: parent.p, index: parent.index}
+	}
+	for _, o := range parent.insts {
+		o.members = o.members[:0]
+		v.addMembers(parent, f, o)
+	}
+	for _, i := range f.insts {
+		if i.n.hasEnum || i.n.hasConst {
+			f.build = true
+		}
+		switch kind {
+		case '{':
+			i.checkType(f, typeObject, false)
+		case '[':
+			i.checkType(f, typeArray, false)
+		}
+	}
+	if f.build {
+		switch kind {
+		case '{':
+			f.value = map[string]interface{}{}
+		case '[':
+			f.value = []interface{}{}
+		}
+	}
+	v.frames = append(v.frames, f)
+}
+
+// addMembers adds the instances validating the member f of parent against the subschemas of o.
+func (v *validation) addMembers(parent, f *frame, o *inst) {
+	n := o.n
+	if n.isBool {
+		return
+	}
+	if parent.kind == '{' {
+		matched := false
+		if sub, exists := n.properties[parent.key]; exists {
+			v.member(f, o, sub, roleMember)
+			matched = true
+		}
+		for _, pp := range n.patternProperties {
+			if pp.re.MatchString(parent.key) {
+				v.member(f, o, pp.node, roleMember)
+				matched = true
+			}
+		}
+		if !matched && n.additionalProperties != nil {
+			v.member(f, o, n.additionalProperties, roleMember)
+		}
+		if n.unevaluatedProperties != nil {
+			v.member(f, o, n.unevaluatedProperties, roleUnevaluated)
+		}
+		return
+	}
+	if n.contains != nil {
+		v.member(f, o, n.contains, roleContains)
+	}
+	sub := n.items
+	if parent.index < len(n.prefixItems) {
+		sub = n.prefixItems[parent.index]
+	}
+	if sub != nil {
+		v.member(f, o, sub, roleMember)
+	}
+	if n.unevaluatedItems != nil {
+		v.member(f, o, n.unevaluatedItems, roleUnevaluated)
+	}
+	if n.uniqueItems {
+		f.build = true
+	}
+}
+
+// key starts reading the member with the unescaped key at cursor in the current object.
+func (v *validation) key(key []byte, cursor int64) {
+	f := v.top()
+	f.key = string(key)
+	f.memberP = &path{parent: f.p, key: f.key, isKey: true}
+	var names *frame
+	for _, o := range f.insts {
+		if o.n.isBool {
+			continue
+		}
+		o.count++
+		if o.keys != nil {
+			o.keys[f.key] = struct{}{}
+		}
+		if o.n.propertyNames == nil {
+			continue
+		}
+		if names == nil {
+			names = &frame{kind: '"', start: cursor, p: f.memberP, isKey: true, done: -1}
+		}
+		o.members = o.members[:0]
+		v.member(names, o, o.n.propertyNames, rolePropertyName)
+	}
+	if names != nil {
+		for _, i := range names.insts {
+			names.build = names.build || i.n.hasEnum || i.n.hasConst
+		}
+		v.frames = append(v.frames, names)
+		v.string(key)
+		v.close()
+	}
+}
+
+// string reads the content of the current value, which is a string.
+func (v *validation) string(str []byte) {
+	f := v.top()
+	for _, i := range f.insts {
+		if i.n.isBool {
+			continue
+		}
+		n := i.n
+		i.checkType(f, typeString, false)
+		if n.maxLength >= 0 || n.minLength >= 0 {
+			length := int64(utf8.RuneCount(str))
+			if n.maxLength >= 0 && length > n.maxLength {
+				i.fail("maxLength", f.p, f.start, "string must be at most %d characters long, but is %d", n.maxLength, length)
+			}
+			if n.minLength >= 0 && length < n.minLength {
+				i.fail("minLength", f.p, f.start, "string must be at least %d characters long, but is %d", n.minLength, length)
+			}
+		}
+		if n.pattern != nil && !n.pattern.Match(str) {
+			i.fail("pattern", f.p, f.start, "string must match the pattern %q", n.pattern)
+		}
+	}
+	if f.build {
+		f.value = string(str)
+	}
+}
+
+// number reads the literal of the current value, which is a number.
+func (v *validation) number(lit []byte) {
+	f := v.top()
+	num := number(lit)
+	for _, i := range f.insts {
+		if i.n.isBool {
+			continue
+		}
+		n := i.n
+		if n.types != 0 && n.types&typeNumber == 0 {
+			i.checkType(f, typeNumber, num.isInteger())
+		}
+		if n.maximum != "" && compareNumbers(num, n.maximum) > 0 {
+			i.fail("maximum", f.p, f.start, "number must be less than or equal to %s", n.maximum)
+		}
+		if n.exclusiveMaximum != "" && compareNumbers(num, n.exclusiveMaximum) >= 0 {
+			i.fail("exclusiveMaximum", f.p, f.start, "number must be less than %s", n.exclusiveMaximum)
+		}
+		if n.minimum != "" && compareNumbers(num, n.minimum) < 0 {
+			i.fail("minimum", f.p, f.start, "number must be greater than or equal to %s", n.minimum)
+		}
+		if n.exclusiveMinimum != "" && compareNumbers(num, n.exclusiveMinimum) <= 0 {
+			i.fail("exclusiveMinimum", f.p, f.start, "number must be greater than %s", n.exclusiveMinimum)
+		}
+		if n.multipleOf != "" && !num.isMultipleOf(n.multipleOf) {
+			i.fail("multipleOf", f.p, f.start, "number must be a multiple of %s", n.multipleOf)
+		}
+	}
+	if f.build {
+		f.value = num
+	}
+}
+
+// literal reads the current value, which is true, false or null.
+func (v *validation) literal(c byte) {
+	f := v.top()
+	typ := typeBoolean
+	if c == 'n' {
+		typ = typeNull
+	}
+	for _, i := range f.insts {
+		if !i.n.isBool {
+			i.checkType(f, typ, false)
+		}
+	}
+	if f.build {
+		switch c {
+		case 't':
+			f.value = true
+		case 'f':
+			f.value = false
+		}
+	}
+}
+
+func (i *inst) checkType(f *frame, typ typeSet, isInteger bool) {
+	n := i.n
+	if n.types == 0 || n.types&typ != 0 {
+		return
+	}
+	if typ == typeNumber && isInteger && n.types&typeInteger != 0 {
+		return
+	}
+	i.fail("type", f.p, f.start, "expected %s, but got %s", typeList(n.types), typeName(typ))
+}
+
+func typeName(typ typeSet) string {
+	for name, t := range typeNames {
+		if t == typ {
+			return name
+		}
+	}
+	return ""
+}
+
+func typeList(types typeSet) string {
+	var list string
+	for _, name := range []string{"null", "boolean", "object", "array", "number", "integer", "string"} {
+		if types&typeNames[name] == 0 {
+			continue
+		}
+		if list != "" {
+			list += " or "
+		}
+		list += name
+	}
+	return list
+}
+
+// close ends the current value. Its instances are finished, the subschemas before the instances applying them,
+// and their results are passed to the instances of the enclosing value.
+func (v *validation) close() {
+	f := v.top()
+	v.frames = v.frames[:len(v.frames)-1]
+	for k := len(f.insts) - 1; k >= 0; k-- {
+		f.insts[k].finish(f)
+	}
+	parent := v.top()
+	if parent.kind == 0 {
+		v.errs = append(v.errs, f.insts[0].errs...)
+		return
+	}
+	if parent.build && !f.isKey {
+		switch value := parent.value.(type) {
+		case map[string]interface{}:
+			value[parent.key] = f.value
+		case []interface{}:
+			parent.value = append(value, f.value)
+		}
+	}
+	for _, o := range parent.insts {
+		if len(o.members) > 0 || o.n.uniqueItems && parent.kind == '[' {
+			o.endMember(parent, f)
+		}
+	}
+	if parent.kind == '[' && !f.isKey {
+		parent.index++
+	}
+}
+
+// endMember applies the results of the instances validating the member f of parent.
+func (i *inst) endMember(parent, f *frame) {
+	switch {
+	case f.isKey:
+		for _, m := range i.members {
+			i.errs = append(i.errs, m.errs...)
+		}
+	case parent.kind == '{':
+		evaluated := false
+		for _, m := range i.members {
+			switch m.role {
+			case roleMember:
+				i.errs = append(i.errs, m.errs...)
+				evaluated = true
+			case roleUnevaluated:
+				i.unevaluated = append(i.unevaluated, &unevaluatedMember{key: parent.key, errs: m.errs})
+			}
+		}
+		if evaluated && i.ev != nil {
+			i.ev.props[parent.key] = struct{}{}
+		}
+	default:
+		for _, m := range i.members {
+			if m.role == roleContains && m.valid() {
+				i.contains++
+				if i.ev != nil {
+					i.ev.matched[parent.index] = struct{}{}
+				}
+			}
+		}
+		if i.n.uniqueItems {
+			if containsValue(i.values, f.value) {
+				i.fail("uniqueItems", f.p, f.start, "array items must be unique")
+			}
+			i.values = append(i.values, f.value)
+		}
+		for _, m := range i.members {
+			switch m.role {
+			case roleMember:
+				i.errs = append(i.errs, m.errs...)
+			case roleUnevaluated:
+				i.unevaluated = append(i.unevaluated, &unevaluatedMember{index: parent.index, errs: m.errs})
+			}
+		}
+	}
+	i.members = i.members[:0]
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if equal(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// finish validates the keywords of i that need the whole value of f.
+func (i *inst) finish(f *frame) {
+	n := i.n
+	if n.isBool {
+		if !n.boolValue {
+			i.fail("false", f.p, f.start, "no value is allowed")
+		}
+		return
+	}
+	switch f.kind {
+	case '{':
+		i.finishObject(f)
+	case '[':
+		i.finishArray(f)
+	}
+	if n.hasConst && !equal(n.constValue, f.value) {
+		i.fail("const", f.p, f.start, "value must be equal to the constant")
+	}
+	if n.hasEnum && !containsValue(n.enum, f.value) {
+		i.fail("enum", f.p, f.start, "value must be one of the enumerated values")
+	}
+	i.applyInPlace(f)
+	i.applyUnevaluated(f)
+}
+
+func (i *inst) finishObject(f *frame) {
+	n := i.n
+	if n.maxProperties >= 0 && i.count > n.maxProperties {
+		i.fail("maxProperties", f.p, f.start, "object must have at most %d properties, but has %d", n.maxProperties, i.count)
+	}
+	if n.minProperties >= 0 && i.count < n.minProperties {
+		i.fail("minProperties", f.p, f.start, "object must have at least %d properties, but has %d", n.minProperties, i.count)
+	}
+	for _, key := range n.required {
+		if _, exists := i.keys[key]; !exists {
+			i.fail("required", f.p, f.start, "missing required property %q", key)
+		}
+	}
+	for _, dep := range n.dependentRequired {
+		if _, exists := i.keys[dep.key]; !exists {
+			continue
+		}
+		for _, key := range dep.required {
+			if _, exists := i.keys[key]; !exists {
+				i.fail("dependentRequired", f.p, f.start, "missing property %q required by %q", key, dep.key)
+			}
+		}
+	}
+}
+
+func (i *inst) finishArray(f *frame) {
+	n := i.n
+	if i.ev != nil {
+		if n.items != nil {
+			i.ev.allItems = true
+		} else if len(n.prefixItems) > i.ev.items {
+			i.ev.items = len(n.prefixItems)
+		}
+	}
+	length := int64(f.index)
+	if n.maxItems >= 0 && length > n.maxItems {
+		i.fail("maxItems", f.p, f.start, "array must have at most %d items, but has %d", n.maxItems, length)
+	}
+	if n.minItems >= 0 && length < n.minItems {
+		i.fail("minItems", f.p, f.start, "array must have at least %d items, but has %d", n.minItems, length)
+	}
+	if n.contains != nil {
+		minContains := n.minContains
+		if minContains < 0 {
+			minContains = 1
+		}
+		if i.contains < minContains {
+			keyword := "contains"
+			if n.minContains >= 0 {
+				keyword = "minContains"
+			}
+			i.fail(keyword, f.p, f.start, "array must contain at least %d matching items, but contains %d", minContains, i.contains)
+		}
+		if n.maxContains >= 0 && i.contains > n.maxContains {
+			i.fail("maxContains", f.p, f.start, "array must contain at most %d matching items, but contains %d", n.maxContains, i.contains)
+		}
+	}
+}
+
+// applyInPlace applies the results of the subschemas of i that apply to the value itself.
+func (i *inst) applyInPlace(f *frame) {
+	n := i.n
+	if i.ref != nil {
+		i.apply(i.ref)
+	}
+	for _, sub := range i.allOf {
+		i.apply(sub)
+	}
+	if len(i.anyOf) > 0 {
+		valid := false
+		for _, sub := range i.anyOf {
+			if sub.valid() {
+				valid = true
+				i.merge(sub)
+			}
+		}
+		if !valid {
+			i.fail("anyOf", f.p, f.start, "value must be valid against at least one schema")
+		}
+	}
+	if len(i.oneOf) > 0 {
+		matches := 0
+		for _, sub := range i.oneOf {
+			if sub.valid() {
+				matches++
+				i.merge(sub)
+			}
+		}
+		if matches != 1 {
+			i.fail("oneOf", f.p, f.start, "value must be valid against exactly one schema, but is valid against %d", matches)
+		}
+	}
+	if i.not != nil && i.not.valid() {
+		i.fail("not", f.p, f.start, "value must not be valid against the schema")
+	}
+	if i.ifInst != nil {
+		next := i.elseInst
+		if i.ifInst.valid() {
+			i.merge(i.ifInst)
+			next = i.thenInst
+		}
+		if next != nil {
+			i.apply(next)
+		}
+	}
+	for k, dep := range i.deps {
+		if _, exists := i.keys[n.dependentSchemas[k].key]; exists {
+			i.apply(dep)
+		}
+	}
+}
+
+// applyUnevaluated applies the results of the members of the value
+// that were not evaluated by the other keywords of i.
+func (i *inst) applyUnevaluated(f *frame) {
+	n := i.n
+	switch {
+	case f.kind == '{' && n.unevaluatedProperties != nil:
+		for _, m := range i.unevaluated {
+			if _, exists := i.ev.props[m.key]; exists {
+				continue
+			}
+			i.errs = append(i.errs, m.errs...)
+			i.ev.props[m.key] = struct{}{}
+		}
+	case f.kind == '[' && n.unevaluatedItems != nil:
+		for _, m := range i.unevaluated {
+			if !i.ev.item(m.index) {
+				i.errs = append(i.errs, m.errs...)
+			}
+		}
+		i.ev.allItems = true
+	}
+}
+
+// idle reports whether nothing is validated in the current value.
+func (v *validation) idle() bool {
+	f := v.top()
+	return len(f.insts) == 0 && !f.build
+}
+
+// walk reads the value at the cursor of s and validates it.
+func (v *validation) walk(s *decoder.Scanner, depth int64) error {
+	c := s.Next()
+	start := s.Cursor
+	switch c {
+	case '{':
+		if err := s.Begin('{', depth+1); err != nil {
+			return err
+		}
+		v.open('{', start)
+		if v.idle() {
+			s.Cursor = start
+			if err := s.Skip(depth); err != nil {
+				return err
+			}
+			break
+		}
+		for first := true; ; first = false {
+			more, err := s.Element(first, '}')
+			if err != nil {
+				return err
+			}
+			if !more {
+				break
+			}
+			keyStart := s.Cursor
+			key, err := s.Key()
+			if err != nil {
+				return err
+			}
+			v.key(key, keyStart)
+			if err := v.walk(s, depth+1); err != nil {
+				return err
+			}
+		}
+	case '[':
+		if err := s.Begin('[', depth+1); err != nil {
+			return err
+		}
+		v.open('[', start)
+		if v.idle() {
+			s.Cursor = start
+			if err := s.Skip(depth); err != nil {
+				return err
+			}
+			break
+		}
+		for first := true; ; first = false {
+			more, err := s.Element(first, ']')
+			if err != nil {
+				return err
+			}
+			if !more {
+				break
+			}
+			if err := v.walk(s, depth+1); err != nil {
+				return err
+			}
+		}
+	case '"':
+		str, err := s.String()
+		if err != nil {
+			return err
+		}
+		v.open('"', start)
+		v.string(str)
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		lit, err := s.Number()
+		if err != nil {
+			return err
+		}
+		v.open('0', start)
+		v.number(lit)
+	default:
+		c, err := s.Literal()
+		if err != nil {
+			return err
+		}
+		v.open(c, start)
+		v.literal(c)
+	}
+	v.close()
+	return v.err
+}
+
+// BeginObject reports the object at cursor read by the decoders.
+func (v *validation) BeginObject(cursor int64) {
+	if v.err == nil {
+		v.open('{', cursor)
+	}
+}
+
+// BeginArray reports the array at cursor read by the decoders.
+func (v *validation) BeginArray(cursor int64) {
+	if v.err == nil {
+		v.open('[', cursor)
+	}
+}
+
+// Key reports the quoted key at cursor read by the decoders.
+func (v *validation) Key(key []byte, cursor int64) {
+	if v.err != nil {
+		return
+	}
+	if bytes.IndexByte(key, '\\') < 0 {
+		v.key(key[1:len(key)-1], cursor)
+		return
+	}
+	s := &decoder.Scanner{Buf: key}
+	str, err := s.String()
+	if err != nil {
+		v.err = err
+		return
+	}
+	v.key(str, cursor)
+}
+
+// End reports the end of the object or array read by the decoders.
+func (v *validation) End() {
+	if v.err != nil {
+		return
+	}
+	start := v.top().start
+	v.close()
+	v.top().done = start
+}
+
+// Value reports the value between cursor and end in buf read by the decoders.
+// The value was already validated if the decoders reported it as an object or array,
+// or reported it before; otherwise, it is read from buf.
+func (v *validation) Value(buf []byte, cursor, end int64) {
+	if v.err != nil {
+		return
+	}
+	// the objects and arrays left unfinished by the decoders, e.g. because of a collected error,
+	// are validated again from buf
+	for len(v.frames) > 1 && v.top().start >= cursor {
+		v.frames = v.frames[:len(v.frames)-1]
+	}
+	f := v.top()
+	if f.done >= cursor {
+		f.done = -1
+		return
+	}
+	s := &decoder.Scanner{Buf: buf, Cursor: cursor}
+	c := s.Next()
+	start := s.Cursor
+	switch c {
+	case '"':
+		token := bytes.TrimRight(buf[start:end], " \t\r\n")
+		str := token[1 : len(token)-1]
+		if bytes.IndexByte(str, '\\') >= 0 {
+			var err error
+			if str, err = s.String(); err != nil {
+				v.err = err
+				return
+			}
+		}
+		v.open('"', start)
+		v.string(str)
+		v.close()
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		v.open('0', start)
+		v.number(bytes.TrimRight(buf[start:end], " \t\r\n"))
+		v.close()
+	default:
+		if err := v.walk(s, int64(len(v.frames)-1)); err != nil {
+			v.err = err
+			return
+		}
+	}
+	f.done = start
+}
+
+// Err returns the failures of the value or the error that stopped its validation.
+func (v *validation) Err() error {
+	if v.err != nil {
+		return v.err
+	}
+	if len(v.errs) > 0 {
+		return v.errs
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package schema
+
+import (
+	"math/big"
+
+	"github.com/goccy/go-json/internal/decoder"
+)
+
+// number is the literal of a JSON number.
+// Numbers are compared by their exact value, so 1, 1.0 and 10e-1 are equal.
+type number string
+
+// maxRatExponent bounds the exponents of the numbers compared exactly with big.Rat.
+// Numbers with larger exponents are compared with big.Float, which keeps their magnitude cheap to compute.
+const maxRatExponent = 1000
+
+// floatPrec is the precision used to compare numbers that are not compared exactly.
+const floatPrec = 1024
+
+func (n number) rat() (*big.Rat, bool) {
+	if exponent(string(n)) > maxRatExponent {
+		return nil, false
+	}
+	r, ok := new(big.Rat).SetString(string(n))
+	return r, ok
+}
+
+func (n number) float() *big.Float {
+	f, _, err := big.ParseFloat(string(n), 10, floatPrec, big.ToNearestEven)
+	if err != nil {
+		// the exponent is out of the range of big.Float
+		f = new(big.Float)
+		if exponentSign(string(n)) > 0 {
+			f.SetInf(n[0] == '-')
+		}
+	}
+	return f
+}
+
+// exponent returns the absolute value of the exponent of the number literal s,
+// or a value above maxRatExponent if it does not fit.
+func exponent(s string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] != 'e' && s[i] != 'E' {
+			continue
+		}
+		digits := s[i+1:]
+		if len(digits) > 0 && (digits[0] == '+' || digits[0] == '-') {
+			digits = digits[1:]
+		}
+		exp := 0
+		for j := 0; j < len(digits); j++ {
+			exp = exp*10 + int(digits[j]-'0')
+			if exp > maxRatExponent {
+				return exp
+			}
+		}
+		return exp
+	}
+	return 0
+}
+
+func exponentSign(s string) int {
+	for i := 0; i < len(s)-1; i++ {
+		if s[i] == 'e' || s[i] == 'E' {
+			if s[i+1] == '-' {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func compareNumbers(a, b number) int {
+	if x, ok := a.rat(); ok {
+		if y, ok := b.rat(); ok {
+			return x.Cmp(y)
+		}
+	}
+	return a.float().Cmp(b.float())
+}
+
+func (n number) isInteger() bool {
+	if r, ok := n.rat(); ok {
+		return r.IsInt()
+	}
+	return n.float().IsInt()
+}
+
+// isMultipleOf reports whether n divided by the positive number m is an integer.
+func (n number) isMultipleOf(m number) bool {
+	if x, ok := n.rat(); ok {
+		if y, ok := m.rat(); ok {
+			return new(big.Rat).Quo(x, y).IsInt()
+		}
+	}
+	f := n.float()
+	if f.IsInf() {
+		return false
+	}
+	return new(big.Float).SetPrec(floatPrec).Quo(f, m.float()).IsInt()
+}
+
+// parseValue reads the JSON value at the cursor of s.
+// Objects are returned as map[string]interface{}, arrays as []interface{},
+// strings as string, numbers as number, and true, false and null as bool and nil.
+func parseValue(s *decoder.Scanner, depth int64) (interface{}, error) {
+	switch s.Next() {
+	case '{':
+		if err := s.Begin('{', depth+1); err != nil {
+			return nil, err
+		}
+		obj := map[string]interface{}{}
+		for first := true; ; first = false {
+			more, err := s.Element(first, '}')
+			if err != nil {
+				return nil, err
+			}
+			if !more {
+				return obj, nil
+			}
+			key, err := s.Key()
+			if err != nil {
+				return nil, err
+			}
+			k := string(key)
+			v, err := parseValue(s, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = v
+		}
+	case '[':
+		if err := s.Begin('[', depth+1); err != nil {
+			return nil, err
+		}
+		arr := []interface{}{}
+		for first := true; ; first = false {
+			more, err := s.Element(first, ']')
+			if err != nil {
+				return nil, err
+			}
+			if !more {
+				return arr, nil
+			}
+			v, err := parseValue(s, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+	case '"':
+		str, err := s.String()
+		if err != nil {
+			return nil, err
+		}
+		return string(str), nil
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		lit, err := s.Number()
+		if err != nil {
+			return nil, err
+		}
+		return number(lit), nil
+	}
+	c, err := s.Literal()
+	if err != nil {
+		return nil, err
+	}
+	switch c {
+	case 't':
+		return true, nil
+	case 'f':
+		return false, nil
+	}
+	return nil, nil
+}
+
+// equal reports whether the values returned by parseValue are equal JSON values.
+func equal(a, b interface{}) bool {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, exists := y[k]
+			if !exists || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equal(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case number:
+		y, ok := b.(number)
+		return ok && compareNumbers(x, y) == 0
+	}
+	return a == b
+}
@@ -0,0 +1,19 @@
+package json
+
+import (
+	"reflect"
+
+	"github.com/goccy/go-json/internal/encoder"
+	"github.com/goccy/go-json/internal/runtime"
+)
+
+// RegisterSensitiveType makes values of type typ, or of pointers to it, sensitive,
+// so that EncodeRedact replaces them like the values of fields with the sensitive tag option,
+// whether they are struct fields, array elements, map values or the encoded value itself.
+// The code already compiled for the encoder is discarded, so that the types containing typ are
+// redacted from then on, at the cost of compiling all the types again.
+// RegisterSensitiveType should therefore preferably be called during initialization.
+func RegisterSensitiveType(typ reflect.Type) {
+	runtime.RegisterSensitive(runtime.Type2RType(typ))
+	encoder.ClearCache()
+}
@@ -0,0 +1,38 @@
+package json
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/goccy/go-json/internal/decoder"
+	"github.com/goccy/go-json/internal/encoder"
+	"github.com/goccy/go-json/internal/runtime"
+)
+
+// RegisterStructTags overrides the tags of the fields of the struct type typ, for structs whose
+// definition cannot be changed, such as generated code. tags maps field names to tags in the form of
+// json tags, which replace those of the fields for the encoder and the decoder:
+//
+//	json.RegisterStructTags(reflect.TypeOf(sdk.User{}), map[string]string{
+//		"ID":       "id",
+//		"Password": "-",
+//	})
+//
+// The replaced tags are also used with EncodeTagKey and DecodeTagKey, whatever the key.
+// Fields missing from tags keep their tags. Registering tags for typ again replaces the previous ones.
+// The code already compiled for the encoder and the decoder is discarded, so that typ and the types
+// containing it use the replaced tags from then on, at the cost of compiling all the types again.
+// RegisterStructTags should therefore preferably be called during initialization.
+func RegisterStructTags(typ reflect.Type, tags map[string]string) {
+	if typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("json: RegisterStructTags of non-struct type %s", typ))
+	}
+	for name := range tags {
+		if field, exists := typ.FieldByName(name); !exists || len(field.Index) != 1 {
+			panic(fmt.Sprintf("json: RegisterStructTags of unknown field %s.%s", typ, name))
+		}
+	}
+	runtime.RegisterStructTags(runtime.Type2RType(typ), tags)
+	encoder.ClearCache()
+	decoder.ClearCache()
+}
@@ -0,0 +1,53 @@
+package json
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/goccy/go-json/internal/decoder"
+	"github.com/goccy/go-json/internal/encoder"
+	"github.com/goccy/go-json/internal/runtime"
+)
+
+// RegisterUnion registers the concrete types of the interface type T by the value of
+// the discriminator property key, for example:
+//
+//	json.RegisterUnion[Shape]("type", map[string]Shape{
+//		"circle": Circle{},
+//		"square": &Square{},
+//	})
+//
+// When a JSON object is decoded into T, the concrete type is selected by the key property,
+// which may appear at any position in the object. Values registered as pointers are decoded
+// as pointers. Decoder.DisallowUnknownFields does not report the key property as an unknown field.
+// When a registered type is encoded, the key property is written before its fields
+// unless the type has a field with the same name.
+//
+// The concrete types must be structs or pointers to structs.
+// The code already compiled for the encoder and the decoder is discarded, so that T and the registered
+// types use the union from then on, at the cost of compiling all the types again.
+// RegisterUnion should therefore preferably be called during initialization.
+func RegisterUnion[T any](key string, types map[string]T) {
+	iface := reflect.TypeOf((*T)(nil)).Elem()
+	if iface.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("json: RegisterUnion of non-interface type %s", iface))
+	}
+	union := &runtime.Union{Key: key, Types: map[string]*runtime.Type{}}
+	for name, v := range types {
+		typ := reflect.TypeOf(v)
+		if typ == nil {
+			panic(fmt.Sprintf("json: RegisterUnion of nil value for %q", name))
+		}
+		elem := typ
+		if elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			panic(fmt.Sprintf("json: RegisterUnion of non-struct type %s for %q", typ, name))
+		}
+		union.Types[name] = runtime.Type2RType(typ)
+	}
+	runtime.RegisterUnion(runtime.Type2RType(iface), union)
+	encoder.ClearCache()
+	decoder.ClearCache()
+}
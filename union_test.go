@@ -0,0 +1,204 @@
+package json_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/goccy/go-json/schema"
+)
+
+type unionShape interface {
+	Area() float64
+}
+
+type unionCircle struct {
+	Radius float64 `json:"radius"`
+}
+
+func (c unionCircle) Area() float64 { return 3 * c.Radius * c.Radius }
+
+type unionSquare struct {
+	Side float64 `json:"side,omitempty"`
+}
+
+func (s *unionSquare) Area() float64 { return s.Side * s.Side }
+
+type unionLabel struct {
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+}
+
+func (unionLabel) Area() float64 { return 0 }
+
+type unionCanvas struct {
+	Main   unionShape   `json:"main"`
+	Shapes []unionShape `json:"shapes"`
+}
+
+func init() {
+	json.RegisterUnion[unionShape]("kind", map[string]unionShape{
+		"circle": unionCircle{},
+		"square": &unionSquare{},
+		"label":  unionLabel{},
+	})
+}
+
+func TestUnion(t *testing.T) {
+	const src = `{"main":{"radius":2,"kind":"circle"},"shapes":[{"kind":"square","side":3},{"text":"hi","kind":"label"},null]}`
+	expected := unionCanvas{
+		Main: unionCircle{Radius: 2},
+		Shapes: []unionShape{
+			&unionSquare{Side: 3},
+			unionLabel{Kind: "label", Text: "hi"},
+			nil,
+		},
+	}
+	t.Run("unmarshal", func(t *testing.T) {
+		var v unionCanvas
+		assertErr(t, json.Unmarshal([]byte(src), &v))
+		if !reflect.DeepEqual(expected, v) {
+			t.Fatalf("failed to decode union. exp=[%#v] but act=[%#v]", expected, v)
+		}
+	})
+	t.Run("stream", func(t *testing.T) {
+		var v unionCanvas
+		assertErr(t, json.NewDecoder(strings.NewReader(src)).Decode(&v))
+		if !reflect.DeepEqual(expected, v) {
+			t.Fatalf("failed to decode union. exp=[%#v] but act=[%#v]", expected, v)
+		}
+	})
+	t.Run("disallow unknown fields", func(t *testing.T) {
+		var v unionCanvas
+		dec := json.NewDecoder(strings.NewReader(src))
+		dec.DisallowUnknownFields()
+		assertErr(t, dec.Decode(&v))
+		if !reflect.DeepEqual(expected, v) {
+			t.Fatalf("failed to decode union. exp=[%#v] but act=[%#v]", expected, v)
+		}
+		dec = json.NewDecoder(strings.NewReader(`{"main":{"kind":"circle","radius":2,"side":1}}`))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&v); err == nil || !strings.Contains(err.Error(), `unknown field "side"`) {
+			t.Fatalf("expected unknown field error but got %v", err)
+		}
+	})
+	t.Run("marshal", func(t *testing.T) {
+		got, err := json.Marshal(expected)
+		assertErr(t, err)
+		assertEq(t, "union", `{"main":{"kind":"circle","radius":2},"shapes":[{"kind":"square","side":3},{"kind":"label","text":"hi"},null]}`, string(got))
+
+		got, err = json.Marshal(&unionSquare{})
+		assertErr(t, err)
+		assertEq(t, "omitted fields", `{"kind":"square"}`, string(got))
+
+		got, err = json.MarshalIndent(unionCircle{Radius: 1}, "", "  ")
+		assertErr(t, err)
+		assertEq(t, "indent", "{\n  \"kind\": \"circle\",\n  \"radius\": 1\n}", string(got))
+
+		got, err = json.Marshal(struct {
+			unionCircle
+			Name string `json:"name"`
+		}{unionCircle: unionCircle{Radius: 1}, Name: "embedded"})
+		assertErr(t, err)
+		assertEq(t, "embedded", `{"radius":1,"name":"embedded"}`, string(got))
+	})
+	t.Run("round trip", func(t *testing.T) {
+		b, err := json.Marshal(expected)
+		assertErr(t, err)
+		var v unionCanvas
+		assertErr(t, json.Unmarshal(b, &v))
+		if !reflect.DeepEqual(expected, v) {
+			t.Fatalf("failed to round trip union. exp=[%#v] but act=[%#v]", expected, v)
+		}
+	})
+	t.Run("errors", func(t *testing.T) {
+		for _, tc := range []struct {
+			src   string
+			value string
+		}{
+			{src: `{"main":{"radius":2}}`, value: `object without "kind"`},
+			{src: `{"main":{"kind":"hexagon"}}`, value: `object with kind "hexagon"`},
+			{src: `{"main":[]}`, value: "array"},
+		} {
+			var v unionCanvas
+			err := json.Unmarshal([]byte(tc.src), &v)
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				t.Fatalf("%s: expected UnmarshalTypeError, got %v", tc.src, err)
+			}
+			assertEq(t, "value", tc.value, typeErr.Value)
+			assertEq(t, "path", "$.main", typeErr.Path)
+
+			err = json.NewDecoder(strings.NewReader(tc.src)).Decode(&v)
+			if !errors.As(err, &typeErr) {
+				t.Fatalf("%s: expected UnmarshalTypeError from stream, got %v", tc.src, err)
+			}
+			assertEq(t, "stream value", tc.value, typeErr.Value)
+		}
+	})
+}
+
+type unionLateShape interface {
+	Sides() int
+}
+
+type unionTriangle struct {
+	Base float64 `json:"base"`
+}
+
+func (unionTriangle) Sides() int { return 3 }
+
+type unionLateCanvas struct {
+	Main unionLateShape `json:"main"`
+}
+
+func TestRegisterUnionAfterFirstUse(t *testing.T) {
+	const src = `{"main":{"kind":"triangle","base":2}}`
+	v := unionLateCanvas{Main: unionTriangle{Base: 1}}
+	got, err := json.Marshal(v)
+	assertErr(t, err)
+	assertEq(t, "before", `{"main":{"base":1}}`, string(got))
+	var decoded unionLateCanvas
+	if err := json.Unmarshal([]byte(src), &decoded); err == nil {
+		t.Fatal("expected an error decoding into an unregistered interface")
+	}
+
+	json.RegisterUnion[unionLateShape]("kind", map[string]unionLateShape{
+		"triangle": unionTriangle{},
+	})
+	got, err = json.Marshal(v)
+	assertErr(t, err)
+	assertEq(t, "marshal", `{"main":{"kind":"triangle","base":1}}`, string(got))
+
+	expected := unionLateCanvas{Main: unionTriangle{Base: 2}}
+	decoded = unionLateCanvas{}
+	assertErr(t, json.Unmarshal([]byte(src), &decoded))
+	if !reflect.DeepEqual(expected, decoded) {
+		t.Fatalf("failed to decode union. exp=[%#v] but act=[%#v]", expected, decoded)
+	}
+	decoded = unionLateCanvas{}
+	assertErr(t, json.NewDecoder(strings.NewReader(src)).Decode(&decoded))
+	if !reflect.DeepEqual(expected, decoded) {
+		t.Fatalf("failed to decode union from stream. exp=[%#v] but act=[%#v]", expected, decoded)
+	}
+}
+
+func TestUnionSchemaFor(t *testing.T) {
+	b, err := json.SchemaFor(reflect.TypeOf(unionCanvas{}))
+	assertErr(t, err)
+	s, err := schema.Compile(b)
+	assertErr(t, err)
+	data, err := json.Marshal(unionCanvas{
+		Main:   unionCircle{Radius: 1},
+		Shapes: []unionShape{&unionSquare{Side: 2}, unionLabel{Text: "a"}, nil},
+	})
+	assertErr(t, err)
+	if err := s.Validate(data); err != nil {
+		t.Fatalf("%s does not validate %s: %v", b, data, err)
+	}
+	if err := s.Validate([]byte(`{"main":{"kind":"circle","side":2},"shapes":null}`)); err == nil {
+		t.Fatalf("%s validates a mismatched member", b)
+	}
+}
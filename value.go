@@ -0,0 +1,54 @@
+package json
+
+import (
+	"github.com/goccy/go-json/internal/dom"
+)
+
+// Value is a mutable JSON document tree.
+// Unlike map[string]interface{}, objects keep the order of their members and numbers keep
+// their original literal, so decoding into a Value and encoding it again preserves the document.
+// Values are decoded and encoded by dedicated decoders and opcodes, without going through interface{}.
+//
+// Values in the tree are addressed by JSON Pointers (RFC 6901) with Get, Set and Delete:
+//
+//	var v json.Value
+//	if err := json.Unmarshal([]byte(`{"items":[{"name":"a"}]}`), &v); err != nil {
+//		...
+//	}
+//	name := v.Get("/items/0/name").Text() // "a"
+//	v.Set("/items/0/count", json.NewNumber("1"))
+//	v.Delete("/items/0/name")
+//
+// The zero Value is null.
+type Value = dom.Value
+
+// NewNull returns a null Value.
+func NewNull() *Value {
+	return dom.NewNull()
+}
+
+// NewBool returns a bool Value.
+func NewBool(b bool) *Value {
+	return dom.NewBool(b)
+}
+
+// NewNumber returns a number Value with the literal n.
+// An invalid literal is reported when the Value is encoded.
+func NewNumber(n Number) *Value {
+	return dom.NewNumber(n)
+}
+
+// NewString returns a string Value.
+func NewString(s string) *Value {
+	return dom.NewString(s)
+}
+
+// NewArray returns an array Value with elems as its elements.
+func NewArray(elems ...*Value) *Value {
+	return dom.NewArray(elems...)
+}
+
+// NewObject returns an empty object Value. Members are added with Set.
+func NewObject() *Value {
+	return dom.NewObject()
+}